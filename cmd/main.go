@@ -2,22 +2,64 @@ package main
 
 import (
 	_ "NotesService/docs"
+	"NotesService/internal/api/cursor"
+	"NotesService/internal/api/deadline"
+	"NotesService/internal/api/lockout"
 	"NotesService/internal/auth"
 	"NotesService/internal/config"
+	"NotesService/internal/handlers/health/liveness"
+	"NotesService/internal/handlers/health/readiness"
+	"NotesService/internal/handlers/keys/getJWKS"
+	"NotesService/internal/handlers/link/createNoteLink"
+	"NotesService/internal/handlers/link/getNoteLinks"
+	"NotesService/internal/handlers/link/getPublicNote"
+	"NotesService/internal/handlers/link/revokeNoteLink"
 	"NotesService/internal/handlers/note/deleteNote"
 	"NotesService/internal/handlers/note/getAllNotes"
 	"NotesService/internal/handlers/note/getOneNote"
+	"NotesService/internal/handlers/note/patchNote"
 	"NotesService/internal/handlers/note/putNote"
 	"NotesService/internal/handlers/note/saveNotes"
+	"NotesService/internal/handlers/note/searchNotes"
+	"NotesService/internal/handlers/notebook/createNotebook"
+	"NotesService/internal/handlers/notebook/deleteNotebook"
+	"NotesService/internal/handlers/notebook/getNotebook"
+	"NotesService/internal/handlers/notebook/getNotebooks"
+	"NotesService/internal/handlers/notebook/putNotebook"
+	"NotesService/internal/handlers/revision/diffNoteRevisions"
+	"NotesService/internal/handlers/revision/getNoteRevision"
+	"NotesService/internal/handlers/revision/getNoteRevisions"
+	"NotesService/internal/handlers/revision/restoreNoteRevision"
+	"NotesService/internal/handlers/share/getNoteShares"
+	"NotesService/internal/handlers/share/getSharedNotes"
+	"NotesService/internal/handlers/share/shareNote"
+	"NotesService/internal/handlers/share/unshareNote"
+	"NotesService/internal/handlers/tag/getTags"
+	"NotesService/internal/handlers/trash/emptyTrash"
+	"NotesService/internal/handlers/trash/getTrash"
+	"NotesService/internal/handlers/trash/restoreNote"
+	"NotesService/internal/handlers/users/loginUser"
+	"NotesService/internal/handlers/users/logoutAllSessions"
+	"NotesService/internal/handlers/users/logoutUser"
+	"NotesService/internal/handlers/users/refreshToken"
 	"NotesService/internal/handlers/users/registUser"
-	"NotesService/internal/storage/postgresql"
+	"NotesService/internal/health"
+	"NotesService/internal/jobs/revisionRetention"
+	"NotesService/internal/jobs/trashPurge"
+	"NotesService/internal/metrics"
+	"NotesService/internal/tracing"
 	sl "NotesService/pkg/logger/logSlog"
 	mwLogger "NotesService/pkg/logger/loggerMiddleware"
 	logger "NotesService/pkg/logger/setupLogger"
-	"fmt"
+	"context"
+	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -40,40 +82,132 @@ func main() {
 	_ = godotenv.Load()
 
 	cfg := config.MustLoad()
-	fmt.Println(cfg)
 
-	log := logger.SetupLogger(cfg.Env)
+	log := logger.SetupLogger(cfg.Env, cfg.Log.RedactKeys)
 	log.Info("starting server", slog.String("env", cfg.Env))
+	log.Debug("config loaded", slog.Any("config", cfg))
 	log.Debug("debug logging enabled")
 
-	secret := os.Getenv("JWT_SECRET")
-	// Время жизни токена
-	tokenDuration := 30 * time.Minute
+	// app migrate up|down|status|redo — управление схемой без запуска сервера
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(cfg, log, os.Args[2:]))
+	}
+
+	// app users set-password <user_name> — пароль для пользователя, созданного до появления паролей
+	if len(os.Args) > 1 && os.Args[1] == "users" {
+		os.Exit(runUsers(cfg, log, os.Args[2:]))
+	}
 
-	jwtManager, err := auth.NewJWTManager(secret, tokenDuration)
+	// Трассировка: спаны HTTP запросов, вызовов хранилища и SQL; trace_id попадает в логи
+	traces, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		File:         cfg.Tracing.File,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+		ServiceName:  cfg.Tracing.ServiceName,
+	})
 	if err != nil {
-		slog.Error("failed to create JWT manager", "error", err)
+		log.Error("failed to set up tracing", sl.Err(err))
 		os.Exit(1)
 	}
 
-	storage, err := postgresql.New(cfg.StoragePath())
+	storage, err := openStorage(cfg, log)
 	if err != nil {
 		log.Error("error initializing storage", sl.Err(err))
 		os.Exit(1)
 	}
 
+	// Проверки готовности обращаются к самому хранилищу, а не к обёртке с метриками
+	checkers, err := storageCheckers(log, storage)
+	if err != nil {
+		log.Error("failed to create storage health checks", sl.Err(err))
+		os.Exit(1)
+	}
+
+	// Метрики Prometheus: каждый вызов хранилища измеряется обёрткой
+	appMetrics := metrics.New()
+	if stats, ok := storage.(metrics.StatsProvider); ok {
+		if err := appMetrics.RegisterDBStats(cfg.Storage.Driver, stats); err != nil {
+			log.Error("failed to register database metrics", sl.Err(err))
+			os.Exit(1)
+		}
+	}
+	storage = metrics.NewStorage(appMetrics, storage)
+
+	// Фоновые задачи останавливаются отменой jobsCtx при завершении сервиса
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	var jobs sync.WaitGroup
+
+	// Список отозванных токенов и фоновая очистка истёкших
+	revocations := auth.NewRevocationList(log, storage, cfg.JWT.RevocationCacheTTL)
+	jobs.Go(func() { revocations.Run(jobsCtx, cfg.JWT.PurgeInterval) })
+
+	// Удаление старых версий заметок по политике хранения
+	retention := revisionRetention.New(log, storage, cfg.Revisions.MaxCount, cfg.Revisions.MaxAge)
+	jobs.Go(func() { retention.Run(jobsCtx, cfg.Revisions.PruneInterval) })
+
+	// Окончательное удаление заметок из корзины по истечении срока хранения
+	purger := trashPurge.New(log, storage, cfg.Trash.Retention)
+	jobs.Go(func() { purger.Run(jobsCtx, cfg.Trash.PurgeInterval) })
+
+	keys, err := auth.NewKeySet(cfg.JWT.Secret, cfg.JWT.PrivateKeyFile, cfg.JWT.PublicKeyFiles, cfg.JWT.AcceptHS256)
+	if err != nil {
+		log.Error("failed to load JWT keys", sl.Err(err))
+		os.Exit(1)
+	}
+	if cfg.JWT.PrivateKeyFile != "" {
+		switch {
+		case cfg.JWT.AcceptHS256:
+			log.Warn("JWT_ACCEPT_HS256 is enabled, tokens signed with JWT_SECRET are still accepted; disable it once they expire")
+		case cfg.JWT.Secret != "":
+			log.Info("JWT_SECRET is ignored: HS256 tokens are not accepted without JWT_ACCEPT_HS256")
+		}
+	}
+
+	jwtManager, err := auth.NewJWTManager(keys, cfg.JWT.TokenTTL, cfg.JWT.RefreshTTL, revocations)
+	if err != nil {
+		slog.Error("failed to create JWT manager", "error", err)
+		os.Exit(1)
+	}
+
+	if cfg.Pagination.CursorSecret == "" {
+		log.Warn("PAGINATION_CURSOR_SECRET is not set, cursors will be invalidated on restart")
+	}
+
+	cursors, err := cursor.New(cfg.Pagination.CursorSecret)
+	if err != nil {
+		log.Error("failed to create cursor codec", sl.Err(err))
+		os.Exit(1)
+	}
+
+	// Готовность принимать запросы; снимается первой при остановке сервиса
+	healthState := &health.State{}
+	healthState.Register(checkers...)
+
 	//init router
 	router := chi.NewRouter()
 
 	//middleware
 	router.Use(middleware.RequestID) //Генерирует уникальный ID для каждого запроса (для логов и отладки)
 	router.Use(middleware.RealIP)    // Определяет реальный IP клиента (если есть прокси/балансировщик)
-	router.Use(middleware.Logger)    //Логирует все запросы (URL, метод, статус)
-	router.Use(middleware.Heartbeat("/ping"))
+	router.Use(health.Heartbeat("/ping", healthState))
+	// Спан на запрос с продолжением трассировки из traceparent; до логгера, чтобы в логах был trace_id
+	router.Use(tracing.HTTP)
 	router.Use(mwLogger.New(log))
+	// Число и длительность запросов по шаблону маршрута и статусу; снаружи Recoverer, чтобы учитывать паники как 500
+	router.Use(appMetrics.HTTP)
 	router.Use(middleware.Recoverer) //Ловит паники (аварийные завершения) в хендлерах и не даёт упасть серверу
 	router.Use(middleware.URLFormat) //Поддержка форматов URL вроде /api.json, /page.html
 
+	// По истечении HTTP_TIMEOUT обращения к хранилищу прерываются и запрос завершается 504
+	router.Use(deadline.New(cfg.HTTPServer.Timeout))
+
+	router.Get("/metrics", appMetrics.Handler().ServeHTTP)
+
+	// Проверки для оркестратора: /healthz — процесс жив, /readyz — зависимости доступны
+	router.Get("/healthz", liveness.New())
+	router.Get("/readyz", readiness.New(log, healthState, cfg.Health.CheckTimeout))
+
 	// Редирект с /docs на /docs/index.html
 	router.Get("/docs", func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/docs/index.html", http.StatusMovedPermanently)
@@ -82,29 +216,133 @@ func main() {
 	// Основной Swagger UI
 	router.Get("/docs/*", httpSwagger.WrapHandler)
 
+	// URLFormat отрезает расширение при маршрутизации, поэтому путь /.well-known/jwks.json
+	// регистрируется без ".json"
+	router.Get("/.well-known/jwks", getJWKS.New(log, jwtManager))
+
+	// Публичные ссылки на заметки открываются без авторизации; перебор паролей ограничен
+	linkPasswords := lockout.New(cfg.Links.PasswordAttempts, cfg.Links.PasswordLockout)
+	router.Get("/public/notes/{token}", getPublicNote.New(log, storage, linkPasswords))
+
 	router.Post("/users", registUser.New(log, storage, jwtManager))
+	router.Post("/auth/login", loginUser.New(log, storage, jwtManager))
+	router.Post("/auth/refresh", refreshToken.New(log, storage, jwtManager))
+
+	router.Group(func(r chi.Router) {
+		r.Use(auth.JWTAuth(jwtManager))
+		r.Post("/auth/logout", logoutUser.New(log, storage, jwtManager))
+		r.Post("/auth/logout/all", logoutAllSessions.New(log, storage, jwtManager))
+	})
 
 	router.Route("/users/{id}/notes", func(r chi.Router) {
 		r.Use(auth.JWTAuth(jwtManager))
 		r.Post("/", saveNotes.New(log, storage))
-		r.Get("/", getAllNotes.New(log, storage))
+		r.Get("/", getAllNotes.New(log, storage, cursors))
+		r.Get("/search", searchNotes.New(log, storage))
 		r.Get("/{note_id}", getOneNote.New(log, storage))
-		r.Put("/{note_id}", putNote.New(log, storage))
-		r.Delete("/{note_id}", deleteNote.New(log, storage))
+		r.Put("/{note_id}", putNote.New(log, storage, cfg.HTTPServer.RequireIfMatch))
+		r.Patch("/{note_id}", patchNote.New(log, storage, cfg.HTTPServer.RequireIfMatch))
+		r.Delete("/{note_id}", deleteNote.New(log, storage, cfg.HTTPServer.RequireIfMatch))
+		r.Post("/{note_id}/restore", restoreNote.New(log, storage))
+
+		r.Post("/{note_id}/shares", shareNote.New(log, storage))
+		r.Get("/{note_id}/shares", getNoteShares.New(log, storage))
+		r.Delete("/{note_id}/shares/{user_id}", unshareNote.New(log, storage))
+
+		r.Post("/{note_id}/links", createNoteLink.New(log, storage))
+		r.Get("/{note_id}/links", getNoteLinks.New(log, storage))
+		r.Delete("/{note_id}/links/{link_id}", revokeNoteLink.New(log, storage))
+
+		r.Get("/{note_id}/revisions", getNoteRevisions.New(log, storage))
+		r.Get("/{note_id}/revisions/diff", diffNoteRevisions.New(log, storage))
+		r.Get("/{note_id}/revisions/{revision}", getNoteRevision.New(log, storage))
+		r.Post("/{note_id}/revisions/{revision}/restore", restoreNoteRevision.New(log, storage))
+	})
+
+	router.Route("/users/{id}/notebooks", func(r chi.Router) {
+		r.Use(auth.JWTAuth(jwtManager))
+		r.Post("/", createNotebook.New(log, storage))
+		r.Get("/", getNotebooks.New(log, storage))
+		r.Get("/{notebook_id}", getNotebook.New(log, storage))
+		r.Put("/{notebook_id}", putNotebook.New(log, storage))
+		r.Delete("/{notebook_id}", deleteNotebook.New(log, storage))
+	})
+
+	router.With(auth.JWTAuth(jwtManager)).Get("/users/{id}/shared-notes", getSharedNotes.New(log, storage))
+	router.With(auth.JWTAuth(jwtManager)).Get("/users/{id}/tags", getTags.New(log, storage))
+
+	router.Route("/users/{id}/trash", func(r chi.Router) {
+		r.Use(auth.JWTAuth(jwtManager))
+		r.Get("/", getTrash.New(log, storage, cfg.Trash.Retention))
+		r.Delete("/", emptyTrash.New(log, storage))
 	})
 
 	//START SERVER
 	log.Info("starting server", slog.String("Address", cfg.HTTPServer.Address))
 
+	// WriteTimeout с запасом после HTTP_TIMEOUT, чтобы ответ 504 успел записаться
 	srv := &http.Server{
 		Addr:         cfg.HTTPServer.Address,
 		Handler:      router,
 		ReadTimeout:  cfg.HTTPServer.Timeout,
-		WriteTimeout: cfg.HTTPServer.Timeout,
+		WriteTimeout: cfg.HTTPServer.Timeout + time.Second,
 		IdleTimeout:  cfg.HTTPServer.IdleTimeout,
 	}
-	if err := srv.ListenAndServe(); err != nil {
+
+	// SIGTERM присылает Kubernetes при выкатке, SIGINT — Ctrl+C при локальном запуске
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	exitCode := 0
+	select {
+	case <-ctx.Done():
+		// Сначала снимается готовность, и балансировщик перестаёт направлять сюда запросы
+		healthState.Drain()
+		log.Info("shutdown signal received, readiness withdrawn", slog.Duration("delay", cfg.HTTPServer.ShutdownDelay))
+		time.Sleep(cfg.HTTPServer.ShutdownDelay)
+	case err := <-serverErr:
 		log.Error("error starting server", sl.Err(err))
+		exitCode = 1
+	}
+	// Повторный сигнал завершает процесс сразу
+	stop()
+
+	shutdown(log, cfg, srv, func() {
+		stopJobs()
+		jobs.Wait()
+	}, storage, traces)
+
+	os.Exit(exitCode)
+}
+
+// shutdown дожидается обрабатываемых запросов не дольше HTTP_SHUTDOWN_TIMEOUT,
+// затем останавливает фоновые задачи, закрывает хранилище и отправляет оставшиеся спаны
+func shutdown(log *slog.Logger, cfg *config.Config, srv *http.Server, stopJobs func(), storage io.Closer, traces *tracing.Tracing) {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTPServer.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error("failed to drain connections", sl.Err(err))
+	} else {
+		log.Info("http server stopped")
+	}
+
+	stopJobs()
+	log.Info("background jobs stopped")
+
+	if err := storage.Close(); err != nil {
+		log.Error("failed to close storage", sl.Err(err))
+	}
+
+	if err := traces.Shutdown(ctx); err != nil {
+		log.Error("failed to flush traces", sl.Err(err))
 	}
-	log.Error("stopping server", slog.String("Address", cfg.HTTPServer.Address))
+	log.Info("server stopped", slog.String("Address", cfg.HTTPServer.Address))
 }
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"NotesService/internal/config"
+	"NotesService/internal/storage/migrator"
+	sl "NotesService/pkg/logger/logSlog"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = "usage: app migrate up|down|status|redo"
+
+// runMigrate выполняет команду migrate и возвращает код выхода процесса
+func runMigrate(cfg *config.Config, log *slog.Logger, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	if cfg.Storage.Driver == "memory" {
+		fmt.Fprintln(os.Stderr, "migrate requires STORAGE_DRIVER=postgres or sqlite")
+		return 2
+	}
+
+	storage, err := openSQLStorage(cfg)
+	if err != nil {
+		log.Error("error initializing storage", sl.Err(err))
+		return 1
+	}
+	defer storage.Close()
+
+	m, err := storage.Migrator(log)
+	if err != nil {
+		log.Error("failed to load migrations", sl.Err(err))
+		return 1
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		if err != nil {
+			log.Error("migrate up failed", slog.Int("applied", applied), sl.Err(err))
+			return 1
+		}
+		log.Info("migrate up finished", slog.Int("applied", applied))
+	case "down":
+		if err := m.Down(ctx); err != nil {
+			if errors.Is(err, migrator.ErrNoApplied) {
+				log.Info("nothing to roll back")
+				return 0
+			}
+			log.Error("migrate down failed", sl.Err(err))
+			return 1
+		}
+	case "redo":
+		if err := m.Redo(ctx); err != nil {
+			log.Error("migrate redo failed", sl.Err(err))
+			return 1
+		}
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			log.Error("migrate status failed", sl.Err(err))
+			return 1
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		tw.Flush()
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	return 0
+}
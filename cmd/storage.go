@@ -0,0 +1,86 @@
+package main
+
+import (
+	"NotesService/internal/config"
+	"NotesService/internal/health"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/memory"
+	"NotesService/internal/storage/migrator"
+	"NotesService/internal/storage/postgresql"
+	"NotesService/internal/storage/sqlite"
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// migratable — хранилище со схемой, которой управляет мигратор (postgres и sqlite)
+type migratable interface {
+	storage.Storage
+	Ping(ctx context.Context) error
+	Migrator(log *slog.Logger) (*migrator.Migrator, error)
+}
+
+// openStorage создаёт хранилище, выбранное STORAGE_DRIVER. Для postgres и sqlite при
+// DB_AUTO_MIGRATE применяются недостающие миграции
+func openStorage(cfg *config.Config, log *slog.Logger) (storage.Storage, error) {
+	if cfg.Storage.Driver == "memory" {
+		log.Warn("using in-memory storage, data will be lost on restart")
+		return memory.New(), nil
+	}
+
+	db, err := openSQLStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.DB.AutoMigrate {
+		m, err := db.Migrator(log)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("load migrations: %w", err)
+		}
+
+		if _, err := m.Up(context.Background()); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("apply migrations: %w", err)
+		}
+	}
+
+	return db, nil
+}
+
+// openSQLStorage открывает хранилище с SQL базой: sqlite или postgres
+func openSQLStorage(cfg *config.Config) (migratable, error) {
+	if cfg.Storage.Driver == "sqlite" {
+		return sqlite.New(cfg.SQLite.Path, cfg.Storage.QueryTimeout)
+	}
+	return postgresql.New(cfg.StoragePath(), cfg.Storage.QueryTimeout)
+}
+
+// storageCheckers возвращает проверки готовности хранилища: база отвечает и все
+// встроенные миграции применены. У memory внешних зависимостей нет
+func storageCheckers(log *slog.Logger, s storage.Storage) ([]health.HealthChecker, error) {
+	db, ok := s.(migratable)
+	if !ok {
+		return nil, nil
+	}
+
+	m, err := db.Migrator(log)
+	if err != nil {
+		return nil, fmt.Errorf("load migrations: %w", err)
+	}
+
+	return []health.HealthChecker{
+		health.CheckFunc("database", db.Ping),
+		health.CheckFunc("migrations", func(ctx context.Context) error {
+			pending, err := m.Pending(ctx)
+			if err != nil {
+				return err
+			}
+			if len(pending) > 0 {
+				return fmt.Errorf("migrations not applied: %v", pending)
+			}
+			return nil
+		}),
+	}, nil
+}
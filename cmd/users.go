@@ -0,0 +1,82 @@
+package main
+
+import (
+	"NotesService/internal/auth"
+	"NotesService/internal/config"
+	sl "NotesService/pkg/logger/logSlog"
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const usersUsage = "usage: app users set-password <user_name>  (password is read from stdin)"
+
+// runUsers выполняет команду users и возвращает код выхода процесса. set-password задаёт пароль
+// пользователю, например созданному до появления паролей (у таких пользователей пустой хеш
+// и войти они не могут), и завершает все его сессии
+func runUsers(cfg *config.Config, log *slog.Logger, args []string) int {
+	if len(args) != 2 || args[0] != "set-password" {
+		fmt.Fprintln(os.Stderr, usersUsage)
+		return 2
+	}
+
+	if cfg.Storage.Driver == "memory" {
+		fmt.Fprintln(os.Stderr, "users requires STORAGE_DRIVER=postgres or sqlite")
+		return 2
+	}
+
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		fmt.Fprintln(os.Stderr, "failed to read password from stdin:", err)
+		return 2
+	}
+	password = strings.TrimRight(password, "\r\n")
+	// Те же ограничения, что при регистрации (models.UserRequest)
+	if len(password) < 8 || len(password) > auth.MaxPasswordBytes {
+		fmt.Fprintf(os.Stderr, "password must be 8-%d bytes long\n", auth.MaxPasswordBytes)
+		return 2
+	}
+
+	storage, err := openSQLStorage(cfg)
+	if err != nil {
+		log.Error("error initializing storage", sl.Err(err))
+		return 1
+	}
+	defer storage.Close()
+
+	ctx := context.Background()
+	userName := strings.TrimSpace(args[1])
+
+	user, err := storage.GetUserByName(ctx, userName)
+	if err != nil {
+		log.Error("failed to get user", slog.String("user_name", userName), sl.Err(err))
+		return 1
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		log.Error("failed to hash password", sl.Err(err))
+		return 1
+	}
+
+	if err := storage.SetUserPassword(ctx, user.ID, hash); err != nil {
+		log.Error("failed to set password", sl.Err(err))
+		return 1
+	}
+
+	// Выданные до смены пароля токены больше не действуют
+	if err := storage.RevokeUserRefreshTokens(ctx, user.ID); err != nil {
+		log.Error("failed to revoke refresh tokens", sl.Err(err))
+		return 1
+	}
+	if _, err := storage.IncrementTokenGeneration(ctx, user.ID); err != nil {
+		log.Error("failed to revoke access tokens", sl.Err(err))
+		return 1
+	}
+
+	log.Info("password set", slog.Int64("id", user.ID))
+	return 0
+}
@@ -15,9 +15,29 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/users": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Returns the public keys used to verify access tokens, in JWK Set format (RFC 7517). Keys are matched by the \"kid\" token header. HS256 secrets are never published.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get token verification keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_auth.JWKS"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
             "post": {
-                "description": "Creates a new user and returns user info with JWT token",
+                "description": "Checks user credentials and returns user info with new JWT access and refresh tokens",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,23 +45,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "Register new user",
+                "summary": "Log in",
                 "parameters": [
                     {
-                        "description": "User registration data",
+                        "description": "User credentials",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/NotesService_internal_models.UserRequest"
+                            "$ref": "#/definitions/NotesService_internal_models.LoginRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/NotesService_internal_models.UserResponse"
                         }
@@ -49,20 +69,24 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request"
                     },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
                     "500": {
                         "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
                     }
                 }
             }
         },
-        "/users/{id}/notes": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Returns all notes for a specific user. Requires JWT authentication.",
+        "/auth/logout": {
+            "post": {
+                "description": "Revokes the access token used for this request. If a refresh token is passed, every refresh token issued from the same login is revoked too. Requires JWT authentication.",
                 "consumes": [
                     "application/json"
                 ],
@@ -70,47 +94,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notes"
+                    "auth"
                 ],
-                "summary": "Get all notes for a user",
+                "summary": "Log out",
                 "parameters": [
                     {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Limit number of notes",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Offset for pagination",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort by field (createdAt)",
-                        "name": "sort",
-                        "in": "query"
+                        "description": "Refresh token to revoke",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.LogoutRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of notes",
+                        "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/NotesService_internal_models.NoteResponse"
-                            }
+                            "$ref": "#/definitions/NotesService_internal_api_response.Response"
                         }
                     },
                     "400": {
@@ -121,16 +122,61 @@ const docTemplate = `{
                     },
                     "500": {
                         "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
+                ]
+            }
+        },
+        "/auth/logout/all": {
+            "post": {
+                "description": "Revokes every access and refresh token issued to the user, on all devices. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
                 ],
-                "description": "Saves a new note for a specific user. Requires JWT authentication.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Log out from all sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_api_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchanges a refresh token for a new access token and a new refresh token. Each refresh token can be used only once: replaying a used token revokes every token issued from the same login.",
                 "consumes": [
                     "application/json"
                 ],
@@ -138,33 +184,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notes"
+                    "auth"
                 ],
-                "summary": "Create a new note",
+                "summary": "Refresh tokens",
                 "parameters": [
                     {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Note payload",
-                        "name": "request",
+                        "description": "Refresh token",
+                        "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/NotesService_internal_models.SaveNoteRequest"
+                            "$ref": "#/definitions/NotesService_internal_models.RefreshRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created note",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/NotesService_internal_models.NoteResponse"
+                            "$ref": "#/definitions/NotesService_internal_models.TokenResponse"
                         }
                     },
                     "400": {
@@ -175,74 +213,121 @@ const docTemplate = `{
                     },
                     "500": {
                         "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
                     }
                 }
             }
         },
-        "/users/{id}/notes/{note_id}": {
+        "/healthz": {
             "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Returns a single note for a specific user. Requires JWT authentication.",
-                "consumes": [
+                "description": "Reports that the process is running and serving HTTP. Dependencies are not checked, so a database outage does not make the orchestrator restart the service.",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/notes/{token}": {
+            "get": {
+                "description": "Returns a note by its public link token. Does not require authentication. Password-protected links require the X-Link-Password header; after too many wrong passwords the link is locked for a while and answers 429 with Retry-After. Every successful view increments the link's view counter.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notes"
+                    "links"
                 ],
-                "summary": "Get one note by ID",
+                "summary": "Open a public link",
                 "parameters": [
                     {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Link token",
+                        "name": "token",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Note ID",
-                        "name": "note_id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Link password",
+                        "name": "X-Link-Password",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Single note",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/NotesService_internal_models.NoteResponse"
+                            "$ref": "#/definitions/NotesService_internal_models.PublicNoteResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
-                    },
                     "401": {
                         "description": "Unauthorized"
                     },
                     "404": {
                         "description": "Not Found"
                     },
+                    "410": {
+                        "description": "Gone"
+                    },
+                    "429": {
+                        "description": "Too Many Requests"
+                    },
                     "500": {
                         "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Runs all registered dependency checks (database connectivity, applied migrations) and returns the status of each one. Error details are only written to the service log. Responds 503 if any check fails or the service is shutting down.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
                 ],
-                "description": "Updates the title and/or content of a note for a specific user. Requires JWT authentication.",
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "post": {
+                "description": "Creates a new user and returns user info with JWT access and refresh tokens",
                 "consumes": [
                     "application/json"
                 ],
@@ -250,74 +335,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notes"
+                    "users"
                 ],
-                "summary": "Update a note by ID",
+                "summary": "Register new user",
                 "parameters": [
                     {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Note ID",
-                        "name": "note_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Note update payload",
-                        "name": "request",
+                        "description": "User registration data",
+                        "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/NotesService_internal_models.PutNoteRequest"
+                            "$ref": "#/definitions/NotesService_internal_models.UserRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Updated note",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/NotesService_internal_models.NoteResponse"
+                            "$ref": "#/definitions/NotesService_internal_models.UserResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
-                    "401": {
-                        "description": "Unauthorized"
-                    },
-                    "404": {
-                        "description": "Not Found"
+                    "409": {
+                        "description": "Conflict"
                     },
                     "500": {
                         "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Delete a specific note by ID. Requires JWT authentication.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/users/{id}/notebooks": {
+            "get": {
+                "description": "Returns all notebooks of the user as a flat list. The hierarchy is described by parent_id (null for top-level notebooks). Requires JWT authentication.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notes"
+                    "notebooks"
                 ],
-                "summary": "Delete a note",
+                "summary": "List notebooks",
                 "parameters": [
                     {
                         "minimum": 1,
@@ -326,12 +392,258 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Note ID",
-                        "name": "note_id",
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.NotebookListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Creates a notebook for a specific user. Pass parent_id to nest it inside another notebook of the same user. Requires JWT authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notebooks"
+                ],
+                "summary": "Create a notebook",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Notebook payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.NotebookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created notebook",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.NotebookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/notebooks/{notebook_id}": {
+            "get": {
+                "description": "Returns a notebook of a specific user. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notebooks"
+                ],
+                "summary": "Get a notebook by ID",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Notebook ID",
+                        "name": "notebook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.NotebookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replaces the name and parent of a notebook. Moving a notebook moves its nested notebooks and notes along with it; omit parent_id to make it a top-level notebook. A notebook cannot be moved into itself or its own subtree. Requires JWT authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notebooks"
+                ],
+                "summary": "Rename or move a notebook",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Notebook ID",
+                        "name": "notebook_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Notebook payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.NotebookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated notebook",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.NotebookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "409": {
+                        "description": "Conflict"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Deletes a notebook together with its nested notebooks. Notes from the deleted notebooks are kept and moved out of any notebook. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notebooks"
+                ],
+                "summary": "Delete a notebook",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Notebook ID",
+                        "name": "notebook_id",
                         "in": "path",
                         "required": true
                     }
@@ -352,21 +664,2491 @@ const docTemplate = `{
                     "404": {
                         "description": "Not Found"
                     },
-                    "500": {
-                        "description": "Internal Server Error"
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/notes": {
+            "get": {
+                "description": "Returns a page of notes for a specific user. Pages are selected either by an opaque cursor (next_cursor / prev_cursor from the previous response) or by offset. With a cursor the sort order is taken from the cursor. Requires JWT authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Get all notes for a user",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit number of notes",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination, cannot be combined with cursor",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from next_cursor or prev_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "updated_at",
+                            "title"
+                        ],
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Sort field",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of tags to filter by",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "or",
+                            "and"
+                        ],
+                        "type": "string",
+                        "default": "or",
+                        "description": "Tag match mode: or (any tag) or and (all tags)",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Notebook ID to filter by",
+                        "name": "notebook_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Include notes from nested notebooks",
+                        "name": "recursive",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after (RFC 3339 or YYYY-MM-DD)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before (RFC 3339 or YYYY-MM-DD)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Updated at or after (RFC 3339 or YYYY-MM-DD)",
+                        "name": "updated_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Updated before (RFC 3339 or YYYY-MM-DD)",
+                        "name": "updated_to",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 200,
+                        "type": "string",
+                        "description": "Title starts with (case-insensitive)",
+                        "name": "title_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 200,
+                        "type": "string",
+                        "description": "Title contains (case-insensitive)",
+                        "name": "title_contains",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Minimum content length in characters",
+                        "name": "min_length",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Maximum content length in characters",
+                        "name": "max_length",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of notes",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.NoteListResponse"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to the first, prev, next and last pages"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of notes matching the filters"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Saves a new note for a specific user. Requires JWT authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Create a new note",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.SaveNoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created note",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.NoteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/notes/search": {
+            "get": {
+                "description": "Searches the user's notes by title and content. Words are combined with AND; use \"quoted text\" for a phrase, a trailing * for a prefix match (e.g. meet*), -word to exclude a word and OR between words for alternatives. Results are ordered by relevance; title and snippet are HTML-escaped note text in which matches are wrapped in \u003cmark\u003e\u003c/mark\u003e, so they can be inserted into a page as HTML. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Full-text search over notes",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 200,
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit number of results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/notes/{note_id}": {
+            "get": {
+                "description": "Returns a single note for a specific user. Notes of other users are returned if the owner has shared them with the caller. Requires JWT authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Get one note by ID",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached note version",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Single note",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.NoteResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Note version"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Note has not changed since the given ETag"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Updates the title and/or content of a note for a specific user. Notes of other users can be updated if the owner has shared them with the caller with write permission; only the owner can pass tags and notebook_id (403). Requires JWT authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Update a note by ID",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the note version being edited",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Note update payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.PutNoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated note",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.NoteResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Note version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "428": {
+                        "description": "Precondition Required"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Moves a specific note to the trash. Notes in the trash can be restored until they are permanently removed after the retention period or when the trash is emptied. Requires JWT authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Delete a note",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the note version being deleted",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.DeleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "428": {
+                        "description": "Precondition Required"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Applies a JSON Merge Patch (RFC 7386, application/merge-patch+json) or a JSON Patch (RFC 6902, application/json-patch+json) to the note document (title, content, tags, notebook_id). The resulting document is validated and only changed fields are stored. Notes of other users can be patched if the owner has shared them with the caller with write permission; only the owner can change tags and notebook_id (403). Requires JWT authentication.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Partially update a note by ID",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the note version being edited",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Merge patch document or array of JSON Patch operations",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated note",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.NoteResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Note version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "409": {
+                        "description": "Conflict"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "428": {
+                        "description": "Precondition Required"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/notes/{note_id}/links": {
+            "get": {
+                "description": "Returns all public links of a note, including revoked and expired ones, with their view counters. Tokens are not returned. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "List public links of a note",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Owner user ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.LinkListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Creates an unguessable read-only link to a note that can be opened without an account. The link can expire and can be protected with a password. The token is returned only once. Requires JWT authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Create a public link to a note",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Owner user ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Link options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.CreateLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created link",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.LinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/notes/{note_id}/links/{link_id}": {
+            "delete": {
+                "description": "Revokes a public link so it can no longer be opened. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Revoke a public link",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Owner user ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Link ID",
+                        "name": "link_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.DeleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/notes/{note_id}/restore": {
+            "post": {
+                "description": "Moves a deleted note back out of the trash. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a note from the trash",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored note",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.NoteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/notes/{note_id}/revisions": {
+            "get": {
+                "description": "Returns the saved previous versions of a note, newest first. A version is saved every time the note's title or content changes. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "List note revisions",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.RevisionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/notes/{note_id}/revisions/diff": {
+            "get": {
+                "description": "Returns a line-based diff of the title and content between two saved versions of a note. Without the to parameter the from version is compared with the current note. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Diff two note revisions",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Base revision number",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Target revision number (defaults to the current note)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.RevisionDiffResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/notes/{note_id}/revisions/{revision}": {
+            "get": {
+                "description": "Returns the title and content of a saved version of a note. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Get a note revision",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "revision",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.RevisionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/notes/{note_id}/revisions/{revision}/restore": {
+            "post": {
+                "description": "Replaces the note's title and content with a saved version. The current version is saved to the history first, so a restore can be undone. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Restore a note revision",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "revision",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored note",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.NoteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/notes/{note_id}/shares": {
+            "get": {
+                "description": "Returns the users that have access to a note and their permissions. Only the owner can list them. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "List who a note is shared with",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Owner user ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.ShareListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Grants another user read or write access to a note. Sharing the note again with the same user replaces the permission. Only the owner can share a note. Requires JWT authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "Share a note with another user",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Owner user ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User and permission",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.ShareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Granted access",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.ShareResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/notes/{note_id}/shares/{user_id}": {
+            "delete": {
+                "description": "Revokes the access to a note previously granted to another user. Only the owner can revoke access. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "Revoke access to a note",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Owner user ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "note_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "ID of the user whose access is revoked",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.DeleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/shared-notes": {
+            "get": {
+                "description": "Returns notes of other users that the user has been given access to, with the granted permission. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "List notes shared with me",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.SharedNotesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/tags": {
+            "get": {
+                "description": "Returns all tags of the user with the number of notes for each tag. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List user tags",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.TagListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/trash": {
+            "get": {
+                "description": "Returns deleted notes of the user, most recently deleted first, with the time each note will be permanently removed. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "List notes in the trash",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.TrashResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Permanently deletes all notes in the user's trash. This cannot be undone. Requires JWT authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Empty the trash",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotesService_internal_models.EmptyTrashResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    },
+                    "504": {
+                        "description": "Gateway Timeout"
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        }
+    },
+    "definitions": {
+        "NotesService_internal_api_response.Response": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
+                }
+            }
+        },
+        "NotesService_internal_auth.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string",
+                    "example": "RS256"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string",
+                    "example": "AQAB"
+                },
+                "kid": {
+                    "type": "string",
+                    "example": "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+                },
+                "kty": {
+                    "type": "string",
+                    "example": "RSA"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string",
+                    "example": "sig"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "NotesService_internal_auth.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NotesService_internal_auth.JWK"
+                    }
+                }
+            }
+        },
+        "NotesService_internal_health.CheckResult": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "string",
+                    "example": "1.2ms"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "NotesService_internal_health.Report": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/NotesService_internal_health.CheckResult"
+                    }
+                },
+                "status": {
+                    "description": "ok, failed или draining (сервис останавливается, проверки не выполнялись)",
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "NotesService_internal_models.CreateLinkRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-12-31T23:59:59Z"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 4,
+                    "example": "open-sesame"
+                }
+            }
+        },
+        "NotesService_internal_models.DeleteResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
+                }
+            }
+        },
+        "NotesService_internal_models.DiffLine": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "type": "string",
+                    "enum": [
+                        "equal",
+                        "insert",
+                        "delete"
+                    ],
+                    "example": "insert"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "added line"
+                }
+            }
+        },
+        "NotesService_internal_models.EmptyTrashResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
+                }
+            }
+        },
+        "NotesService_internal_models.LinkItem": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-12-31T23:59:59Z"
+                },
+                "has_password": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "revoked": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "view_count": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "NotesService_internal_models.LinkListResponse": {
+            "type": "object",
+            "properties": {
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NotesService_internal_models.LinkItem"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "noteID": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
+                }
+            }
+        },
+        "NotesService_internal_models.LinkResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-12-31T23:59:59Z"
+                },
+                "has_password": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "revoked": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
+                },
+                "token": {
+                    "description": "Токен показывается только при создании ссылки",
+                    "type": "string",
+                    "example": "Zr4q2s0nXk3H9yV1dL8pQm7tB6wE5cA0uJ2fG4hK1iM"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "/public/notes/Zr4q2s0nXk3H9yV1dL8pQm7tB6wE5cA0uJ2fG4hK1iM"
+                },
+                "view_count": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "NotesService_internal_models.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "user_name"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "s3cr3t-passw0rd"
+                },
+                "user_name": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "NotesService_internal_models.LogoutRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string",
+                    "example": "q7Jd0d2b8lV3sWmZ..."
+                }
+            }
+        },
+        "NotesService_internal_models.NoteItem": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "note content"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "noteID": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "notebook_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "work",
+                        "meeting"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "note title"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "NotesService_internal_models.NoteListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NotesService_internal_models.NoteItem"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "next_cursor": {
+                    "description": "Курсоры соседних страниц; отсутствуют, если страницы нет",
+                    "type": "string",
+                    "example": "eyJ1IjoxLCJzIjoiY3JlYXRlZF9hdCJ9.kq3..."
+                },
+                "offset": {
+                    "description": "Смещение страницы; отсутствует при переходе по курсору",
+                    "type": "integer",
+                    "example": 0
+                },
+                "prev_cursor": {
+                    "type": "string",
+                    "example": "eyJ1IjoxLCJzIjoiY3JlYXRlZF9hdCJ9.Zx1..."
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
+                },
+                "total": {
+                    "description": "Число заметок, подходящих под фильтры, без учёта пагинации",
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "NotesService_internal_models.NoteResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "note content"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "noteID": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "notebook_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "work",
+                        "meeting"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "note title"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "NotesService_internal_models.NotebookItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Work"
+                },
+                "parent_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                }
+            }
+        },
+        "NotesService_internal_models.NotebookListResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "notebooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NotesService_internal_models.NotebookItem"
+                    }
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
+                }
+            }
+        },
+        "NotesService_internal_models.NotebookRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Work"
+                },
+                "parent_id": {
+                    "description": "Если поле не передано, блокнот становится блокнотом верхнего уровня",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                }
+            }
+        },
+        "NotesService_internal_models.NotebookResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Work"
+                },
+                "parent_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                }
+            }
+        },
+        "NotesService_internal_models.PublicNoteResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "note content"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "note title"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "view_count": {
+                    "type": "integer",
+                    "example": 13
+                }
+            }
+        },
+        "NotesService_internal_models.PutNoteRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "title"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "Updated note content"
+                },
+                "notebook_id": {
+                    "description": "Если поле не передано, заметка остаётся в своём блокноте; 0 переносит её из блокнота",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3
+                },
+                "tags": {
+                    "description": "Если поле не передано, теги заметки не меняются; [] удаляет все теги",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "work",
+                        "meeting"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "My new title"
+                }
+            }
+        },
+        "NotesService_internal_models.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string",
+                    "example": "q7Jd0d2b8lV3sWmZ..."
+                }
+            }
+        },
+        "NotesService_internal_models.RevisionDiffResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NotesService_internal_models.DiffLine"
+                    }
+                },
+                "from": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "noteID": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
+                },
+                "title": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NotesService_internal_models.DiffLine"
+                    }
+                },
+                "to": {
+                    "description": "Отсутствует, если сравнение идёт с текущей версией заметки",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "NotesService_internal_models.RevisionItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "title": {
+                    "type": "string",
+                    "example": "note title"
+                }
+            }
+        },
+        "NotesService_internal_models.RevisionListResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "noteID": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NotesService_internal_models.RevisionItem"
+                    }
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
+                }
+            }
+        },
+        "NotesService_internal_models.RevisionResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "note content"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "noteID": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "note title"
+                }
+            }
+        },
+        "NotesService_internal_models.SaveNoteRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "title"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "Updated note content"
+                },
+                "notebook_id": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 3
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "work",
+                        "meeting"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "My new title"
+                }
+            }
+        },
+        "NotesService_internal_models.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "\"release plan\" meet*"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NotesService_internal_models.SearchResultItem"
                     }
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
                 }
             }
-        }
-    },
-    "definitions": {
-        "NotesService_internal_models.DeleteResponse": {
+        },
+        "NotesService_internal_models.SearchResultItem": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "noteID": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "notebook_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "rank": {
+                    "type": "number",
+                    "example": 0.6079271
+                },
+                "snippet": {
+                    "type": "string",
+                    "example": "...discussed the \u003cmark\u003erelease\u003c/mark\u003e plan..."
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "work",
+                        "meeting"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Meeting \u003cmark\u003enotes\u003c/mark\u003e"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                }
+            }
+        },
+        "NotesService_internal_models.ShareItem": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "permission": {
+                    "type": "string",
+                    "example": "read"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "user_name": {
+                    "type": "string",
+                    "example": "jane_doe"
+                }
+            }
+        },
+        "NotesService_internal_models.ShareListResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string",
                     "example": "success"
                 },
+                "noteID": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "shares": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NotesService_internal_models.ShareItem"
+                    }
+                },
                 "status": {
                     "description": "Result of operation (OK, Created, Error)",
                     "type": "string",
@@ -374,13 +3156,30 @@ const docTemplate = `{
                 }
             }
         },
-        "NotesService_internal_models.NoteResponse": {
+        "NotesService_internal_models.ShareRequest": {
             "type": "object",
+            "required": [
+                "permission",
+                "user_name"
+            ],
             "properties": {
-                "content": {
+                "permission": {
                     "type": "string",
-                    "example": "note content"
+                    "enum": [
+                        "read",
+                        "write"
+                    ],
+                    "example": "read"
                 },
+                "user_name": {
+                    "type": "string",
+                    "example": "jane_doe"
+                }
+            }
+        },
+        "NotesService_internal_models.ShareResponse": {
+            "type": "object",
+            "properties": {
                 "createdAt": {
                     "type": "string",
                     "example": "2026-02-15T18:01:29.342814+02:00"
@@ -393,11 +3192,48 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1
                 },
+                "permission": {
+                    "type": "string",
+                    "example": "read"
+                },
                 "status": {
                     "description": "Result of operation (OK, Created, Error)",
                     "type": "string",
                     "example": "created"
                 },
+                "userId": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "user_name": {
+                    "type": "string",
+                    "example": "jane_doe"
+                }
+            }
+        },
+        "NotesService_internal_models.SharedNoteItem": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "note content"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "noteID": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "owner_name": {
+                    "type": "string",
+                    "example": "john_doe"
+                },
+                "permission": {
+                    "type": "string",
+                    "example": "write"
+                },
                 "title": {
                     "type": "string",
                     "example": "note title"
@@ -412,46 +3248,162 @@ const docTemplate = `{
                 }
             }
         },
-        "NotesService_internal_models.PutNoteRequest": {
+        "NotesService_internal_models.SharedNotesResponse": {
             "type": "object",
-            "required": [
-                "content",
-                "title"
-            ],
             "properties": {
-                "content": {
+                "message": {
                     "type": "string",
-                    "example": "Updated note content"
+                    "example": "success"
                 },
-                "title": {
+                "notes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NotesService_internal_models.SharedNoteItem"
+                    }
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
                     "type": "string",
-                    "example": "My new title"
+                    "example": "created"
                 }
             }
         },
-        "NotesService_internal_models.SaveNoteRequest": {
+        "NotesService_internal_models.TagItem": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "work"
+                },
+                "note_count": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "NotesService_internal_models.TagListResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NotesService_internal_models.TagItem"
+                    }
+                }
+            }
+        },
+        "NotesService_internal_models.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "q7Jd0d2b8lV3sWmZ..."
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                }
+            }
+        },
+        "NotesService_internal_models.TrashItem": {
             "type": "object",
-            "required": [
-                "content",
-                "title"
-            ],
             "properties": {
                 "content": {
                     "type": "string",
-                    "example": "Updated note content"
+                    "example": "note content"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                },
+                "deletedAt": {
+                    "type": "string",
+                    "example": "2026-02-16T09:12:00.000000+02:00"
+                },
+                "noteID": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "notebook_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "purgeAt": {
+                    "description": "Когда заметка будет удалена окончательно",
+                    "type": "string",
+                    "example": "2026-03-18T09:12:00.000000+02:00"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "work",
+                        "meeting"
+                    ]
                 },
                 "title": {
                     "type": "string",
-                    "example": "My new title"
+                    "example": "note title"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2026-02-15T18:01:29.342814+02:00"
+                }
+            }
+        },
+        "NotesService_internal_models.TrashResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "notes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NotesService_internal_models.TrashItem"
+                    }
+                },
+                "status": {
+                    "description": "Result of operation (OK, Created, Error)",
+                    "type": "string",
+                    "example": "created"
                 }
             }
         },
         "NotesService_internal_models.UserRequest": {
             "type": "object",
             "required": [
+                "password",
                 "user_name"
             ],
             "properties": {
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8,
+                    "example": "s3cr3t-passw0rd"
+                },
                 "user_name": {
                     "type": "string",
                     "minLength": 3,
@@ -474,6 +3426,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "success"
                 },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "q7Jd0d2b8lV3sWmZ..."
+                },
                 "status": {
                     "description": "Result of operation (OK, Created, Error)",
                     "type": "string",
@@ -0,0 +1,80 @@
+// Package cursor кодирует курсоры пагинации в непрозрачные строки,
+// подписанные HMAC-SHA256, чтобы клиент не мог их подделать или изменить
+package cursor
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalid — курсор повреждён или подписан другим ключом
+var ErrInvalid = errors.New("invalid cursor")
+
+type Codec struct {
+	secret []byte
+}
+
+// New создаёт кодек с ключом secret. Если ключ пуст, генерируется случайный:
+// курсоры тогда действуют только до перезапуска сервиса
+func New(secret string) (*Codec, error) {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("cursor: generate key: %w", err)
+		}
+	}
+
+	return &Codec{secret: key}, nil
+}
+
+// Encode сериализует v в JSON и возвращает строку вида payload.signature (base64url)
+func (c *Codec) Encode(v any) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("cursor: encode: %w", err)
+	}
+
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode проверяет подпись курсора и разбирает его в v
+func (c *Codec) Decode(s string, v any) error {
+	payloadPart, sigPart, ok := bytes.Cut([]byte(s), []byte("."))
+	if !ok {
+		return ErrInvalid
+	}
+
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(string(payloadPart))
+	if err != nil {
+		return ErrInvalid
+	}
+	sig, err := enc.DecodeString(string(sigPart))
+	if err != nil {
+		return ErrInvalid
+	}
+
+	if !hmac.Equal(sig, c.sign(payload)) {
+		return ErrInvalid
+	}
+
+	if err := json.Unmarshal(payload, v); err != nil {
+		return ErrInvalid
+	}
+
+	return nil
+}
+
+func (c *Codec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
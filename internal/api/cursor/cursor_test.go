@@ -0,0 +1,83 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type payload struct {
+	UserID int64  `json:"u"`
+	Sort   string `json:"s"`
+}
+
+func TestCodecDecode(t *testing.T) {
+	codec, err := New("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := New("another secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := payload{UserID: 1, Sort: "created_at"}
+	token, err := codec.Encode(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreign, err := other.Encode(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enc := base64.RawURLEncoding
+	payloadPart, sigPart, _ := strings.Cut(token, ".")
+	sig, _ := enc.DecodeString(sigPart)
+
+	// Подпись от исходного payload с подменённым пользователем
+	tampered := enc.EncodeToString([]byte(`{"u":2,"s":"created_at"}`)) + "." + sigPart
+	// Испорченный последний байт подписи
+	badSig := append([]byte(nil), sig...)
+	badSig[len(badSig)-1] ^= 1
+	// Корректно подписанный payload, который не разбирается в JSON
+	notJSON := enc.EncodeToString([]byte("not json")) + "." + enc.EncodeToString(codec.sign([]byte("not json")))
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid", token: token},
+		{name: "tampered payload", token: tampered, wantErr: true},
+		{name: "tampered signature", token: payloadPart + "." + enc.EncodeToString(badSig), wantErr: true},
+		{name: "wrong key", token: foreign, wantErr: true},
+		{name: "no signature", token: payloadPart, wantErr: true},
+		{name: "empty signature", token: payloadPart + ".", wantErr: true},
+		{name: "truncated signature", token: payloadPart + "." + enc.EncodeToString(sig[:16]), wantErr: true},
+		{name: "payload not base64", token: "!!!." + sigPart, wantErr: true},
+		{name: "signature not base64", token: payloadPart + ".!!!", wantErr: true},
+		{name: "payload not json", token: notJSON, wantErr: true},
+		{name: "empty", token: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got payload
+			err := codec.Decode(tt.token, &got)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("Decode() error = %v, want ErrInvalid", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if got != want {
+				t.Errorf("Decode() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
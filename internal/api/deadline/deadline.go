@@ -0,0 +1,24 @@
+// Package deadline ограничивает время обработки запроса
+package deadline
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// New задаёт контексту запроса срок timeout. По его истечении обращения к хранилищу
+// прерываются и обработчик отвечает 504. В отличие от middleware.Timeout из chi,
+// ответ за обработчика не пишется, поэтому статус не записывается дважды
+func New(timeout time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
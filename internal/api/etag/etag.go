@@ -0,0 +1,99 @@
+// Package etag реализует условные запросы (ETag, If-Match, If-None-Match)
+// для ресурсов, версия которых хранится как целое число
+package etag
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrMismatch — If-Match не совпадает с текущей версией ресурса
+var ErrMismatch = errors.New("etag mismatch")
+
+// Format возвращает сильный ETag для версии: "3"
+func Format(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// Set выставляет заголовок ETag ответа
+func Set(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", Format(version))
+}
+
+// IfMatch возвращает версию, которую хранилище должно атомарно сравнить с текущей,
+// и признак того, что заголовок If-Match передан. Для "*" возвращается 0 (подходит любая
+// версия), для одного ETag — его версия. Если ETag несколько, current читает текущую
+// версию ресурса: когда она есть в списке, возвращается она, и хранилище проверит, что
+// ресурс с тех пор не изменился. If-Match требует строгого сравнения, поэтому слабые
+// и некорректные ETag ни с чем не совпадают; если не совпал ни один, возвращается
+// ErrMismatch. Ошибка current возвращается как есть
+func IfMatch(r *http.Request, current func() (int64, error)) (int64, bool, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return 0, false, nil
+	}
+	if header == "*" {
+		return 0, true, nil
+	}
+
+	var versions []int64
+	for _, tag := range strings.Split(header, ",") {
+		if v, ok := parse(strings.TrimSpace(tag)); ok {
+			versions = append(versions, v)
+		}
+	}
+
+	switch len(versions) {
+	case 0:
+		return 0, true, ErrMismatch
+	case 1:
+		return versions[0], true, nil
+	}
+
+	version, err := current()
+	if err != nil {
+		return 0, true, err
+	}
+	if !slices.Contains(versions, version) {
+		return 0, true, ErrMismatch
+	}
+
+	return version, true, nil
+}
+
+// NoneMatch сообщает, совпадает ли версия с одним из ETag в If-None-Match,
+// т.е. можно ли ответить 304 Not Modified. Сравнение слабое
+func NoneMatch(r *http.Request, version int64) bool {
+	header := strings.TrimSpace(r.Header.Get("If-None-Match"))
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if v, ok := parse(tag); ok && v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parse(tag string) (int64, bool) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+
+	return version, true
+}
@@ -0,0 +1,104 @@
+package etag
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIfMatch(t *testing.T) {
+	errCurrent := errors.New("storage unavailable")
+
+	tests := []struct {
+		name       string
+		header     string
+		current    int64
+		currentErr error
+		wantVer    int64
+		wantHas    bool
+		wantErr    error
+		wantRead   bool
+	}{
+		{name: "absent", header: "", wantHas: false},
+		{name: "any", header: "*", wantVer: 0, wantHas: true},
+		{name: "single", header: `"3"`, wantVer: 3, wantHas: true},
+		{name: "single with spaces", header: ` "3" `, wantVer: 3, wantHas: true},
+		// Для If-Match сравнение строгое: слабый ETag не совпадает даже с той же версией
+		{name: "weak", header: `W/"3"`, wantHas: true, wantErr: ErrMismatch},
+		{name: "unquoted", header: `3`, wantHas: true, wantErr: ErrMismatch},
+		{name: "not a number", header: `"abc"`, wantHas: true, wantErr: ErrMismatch},
+		{name: "zero version", header: `"0"`, wantHas: true, wantErr: ErrMismatch},
+		{name: "negative version", header: `"-1"`, wantHas: true, wantErr: ErrMismatch},
+		{name: "unterminated quote", header: `"3`, wantHas: true, wantErr: ErrMismatch},
+		{name: "list contains current", header: `"2", "3"`, current: 3, wantVer: 3, wantHas: true, wantRead: true},
+		{name: "list without current", header: `"1","2"`, current: 3, wantHas: true, wantErr: ErrMismatch, wantRead: true},
+		// Слабые и некорректные ETag в списке пропускаются, остальные сравниваются
+		{name: "list with weak and malformed", header: `W/"5", "3", bogus`, wantVer: 3, wantHas: true},
+		{name: "list of weak only", header: `W/"3", W/"4"`, current: 3, wantHas: true, wantErr: ErrMismatch},
+		{name: "list with star", header: `*, "3"`, wantVer: 3, wantHas: true},
+		{name: "list read fails", header: `"2", "3"`, currentErr: errCurrent, wantHas: true, wantErr: errCurrent, wantRead: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("PUT", "/", nil)
+			if tt.header != "" {
+				r.Header.Set("If-Match", tt.header)
+			}
+
+			read := false
+			version, has, err := IfMatch(r, func() (int64, error) {
+				read = true
+				return tt.current, tt.currentErr
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("IfMatch() error = %v, want %v", err, tt.wantErr)
+			}
+			if has != tt.wantHas {
+				t.Errorf("IfMatch() has = %v, want %v", has, tt.wantHas)
+			}
+			if err == nil && version != tt.wantVer {
+				t.Errorf("IfMatch() version = %d, want %d", version, tt.wantVer)
+			}
+			if read != tt.wantRead {
+				t.Errorf("current() called = %v, want %v", read, tt.wantRead)
+			}
+		})
+	}
+}
+
+func TestNoneMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		version int64
+		want    bool
+	}{
+		{name: "absent", header: "", version: 3, want: false},
+		{name: "any", header: "*", version: 3, want: true},
+		{name: "same", header: `"3"`, version: 3, want: true},
+		{name: "different", header: `"2"`, version: 3, want: false},
+		// Для If-None-Match сравнение слабое
+		{name: "weak same", header: `W/"3"`, version: 3, want: true},
+		{name: "list contains", header: `"1", W/"2", "3"`, version: 3, want: true},
+		{name: "list without", header: `"1", "2"`, version: 3, want: false},
+		{name: "unquoted", header: `3`, version: 3, want: false},
+		{name: "not a number", header: `"abc"`, version: 3, want: false},
+		{name: "malformed and matching", header: `bogus, "3"`, version: 3, want: true},
+		{name: "lowercase weak prefix", header: `w/"3"`, version: 3, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				r.Header.Set("If-None-Match", tt.header)
+			}
+
+			if got := NoneMatch(r, tt.version); got != tt.want {
+				t.Errorf("NoneMatch(%q, %d) = %v, want %v", tt.header, tt.version, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Package linkheader формирует заголовок Link (RFC 8288) со ссылками
+// на соседние страницы списка
+package linkheader
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Link — ссылка с отношением rel (first, prev, next, last)
+type Link struct {
+	Rel    string
+	Target string
+}
+
+// PageURL возвращает адрес текущего запроса, в котором параметры set заменены,
+// а параметры del удалены. Пустое значение в set тоже удаляет параметр
+func PageURL(r *http.Request, set map[string]string, del ...string) string {
+	query := r.URL.Query()
+	for _, name := range del {
+		query.Del(name)
+	}
+	for name, value := range set {
+		if value == "" {
+			query.Del(name)
+			continue
+		}
+		query.Set(name, value)
+	}
+
+	u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	return u.String()
+}
+
+// Set выставляет заголовок Link. Пустые ссылки пропускаются
+func Set(w http.ResponseWriter, links ...Link) {
+	parts := make([]string, 0, len(links))
+	for _, l := range links {
+		if l.Target == "" {
+			continue
+		}
+		parts = append(parts, `<`+l.Target+`>; rel="`+l.Rel+`"`)
+	}
+
+	if len(parts) > 0 {
+		w.Header().Set("Link", strings.Join(parts, ", "))
+	}
+}
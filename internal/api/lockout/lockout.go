@@ -0,0 +1,72 @@
+// Package lockout ограничивает число неудачных попыток (например, паролей публичных ссылок):
+// после maxFailures неудач ключ блокируется до конца окна, и проверка пароля не выполняется
+package lockout
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter считает неудачные попытки в памяти процесса. При нескольких экземплярах сервиса
+// лимит действует в каждом из них отдельно
+type Limiter struct {
+	maxFailures int
+	window      time.Duration
+
+	mu       sync.Mutex
+	failures map[int64]*entry
+}
+
+type entry struct {
+	count int
+	// Окно начинается с первой попытки; по его истечении счётчик сбрасывается
+	until time.Time
+}
+
+// New создаёт ограничитель: не больше maxFailures неудач за window
+func New(maxFailures int, window time.Duration) *Limiter {
+	return &Limiter{
+		maxFailures: maxFailures,
+		window:      window,
+		failures:    make(map[int64]*entry),
+	}
+}
+
+// Allow резервирует попытку для key: она сразу считается неудачной, поэтому
+// одновременные запросы не превысят maxFailures. Если попыток не осталось, возвращает,
+// через сколько блокировка снимется. После успешной попытки нужно вызвать Reset
+func (l *Limiter) Allow(key int64) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	e, ok := l.failures[key]
+	if !ok || !now.Before(e.until) {
+		l.sweep(now)
+		e = &entry{until: now.Add(l.window)}
+		l.failures[key] = e
+	}
+	if e.count >= l.maxFailures {
+		return e.until.Sub(now), false
+	}
+	e.count++
+
+	return 0, true
+}
+
+// Reset сбрасывает счётчик key после успешной попытки
+func (l *Limiter) Reset(key int64) {
+	l.mu.Lock()
+	delete(l.failures, key)
+	l.mu.Unlock()
+}
+
+// sweep удаляет истёкшие окна, чтобы ключи, по которым больше не было попыток,
+// не копились в памяти
+func (l *Limiter) sweep(now time.Time) {
+	for key, e := range l.failures {
+		if !now.Before(e.until) {
+			delete(l.failures, key)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package lockout
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	l := New(3, time.Minute)
+
+	for i := range 3 {
+		if _, ok := l.Allow(1); !ok {
+			t.Fatalf("attempt %d: blocked, want allowed", i+1)
+		}
+	}
+	retryAfter, ok := l.Allow(1)
+	if ok {
+		t.Fatal("attempt 4: allowed, want blocked")
+	}
+	if retryAfter <= 0 || retryAfter > time.Minute {
+		t.Errorf("retry after = %v, want within the window", retryAfter)
+	}
+
+	if _, ok := l.Allow(2); !ok {
+		t.Error("other key: blocked, want allowed")
+	}
+
+	l.Reset(1)
+	if _, ok := l.Allow(1); !ok {
+		t.Error("after Reset: blocked, want allowed")
+	}
+}
+
+func TestLimiterWindow(t *testing.T) {
+	l := New(1, 10*time.Millisecond)
+
+	if _, ok := l.Allow(1); !ok {
+		t.Fatal("first attempt: blocked, want allowed")
+	}
+	if _, ok := l.Allow(1); ok {
+		t.Fatal("second attempt: allowed, want blocked")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := l.Allow(1); !ok {
+		t.Error("after window: blocked, want allowed")
+	}
+}
+
+func TestLimiterConcurrent(t *testing.T) {
+	const maxFailures = 5
+	l := New(maxFailures, time.Minute)
+
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Go(func() {
+			if _, ok := l.Allow(1); ok {
+				allowed.Add(1)
+			}
+		})
+	}
+	wg.Wait()
+
+	if got := allowed.Load(); got != maxFailures {
+		t.Errorf("allowed %d concurrent attempts, want %d", got, maxFailures)
+	}
+}
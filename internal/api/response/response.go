@@ -1,7 +1,10 @@
 package response
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -40,6 +43,20 @@ func Error(msg string) Response {
 	}
 }
 
+// ErrorStatus возвращает статус ответа на ошибку err: 504, если истёк срок запроса или
+// обращения к хранилищу, 503, если запрос отменён (клиент отключился или сервер
+// останавливается), и fallback для остальных ошибок
+func ErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	default:
+		return fallback
+	}
+}
+
 func ValidationError(errs validator.ValidationErrors) Response {
 	var errMsgs []string
 
@@ -1,50 +1,92 @@
 package auth
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
 
-	"NotesService/internal/models"
-
 	"github.com/golang-jwt/jwt/v5"
 )
 
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+// Claims — данные, которые хранятся в токене
+type Claims struct {
+	UserID     int64  `json:"id"`
+	Username   string `json:"user_name"`
+	Generation int64  `json:"gen"` // Поколение токенов пользователя, см. RevocationList
+	jwt.RegisteredClaims
+}
+
 // JWTManager управляет генерацией и проверкой токенов
 type JWTManager struct {
-	secret   []byte        // Секретный ключ (хранится в памяти, не в коде!)
-	duration time.Duration // Время жизни токена
+	keys            *KeySet         // Ключи подписи и проверки (хранятся в памяти, не в коде!)
+	duration        time.Duration   // Время жизни токена
+	refreshDuration time.Duration   // Время жизни refresh-токена
+	revocations     *RevocationList // Список отозванных токенов (может быть nil)
 }
 
 // NewJWTManager создаёт новый менеджер токенов
-// Секрет передаётся извне (из main.go) для безопасности
-func NewJWTManager(secret string, duration time.Duration) (*JWTManager, error) {
-	if secret == "" {
-		return nil, errors.New("secret key cannot be empty")
+// Ключи передаются извне (из main.go) для безопасности
+func NewJWTManager(keys *KeySet, duration time.Duration, refreshDuration time.Duration, revocations *RevocationList) (*JWTManager, error) {
+	if keys == nil {
+		return nil, errors.New("key set cannot be empty")
 	}
 
 	if duration <= 0 {
 		return nil, errors.New("token duration must be positive")
 	}
 
+	if refreshDuration <= 0 {
+		return nil, errors.New("refresh token duration must be positive")
+	}
+
 	return &JWTManager{
-		secret:   []byte(secret),
-		duration: duration,
+		keys:            keys,
+		duration:        duration,
+		refreshDuration: refreshDuration,
+		revocations:     revocations,
 	}, nil
 }
 
 // GenerateToken создаёт новый JWT токен для пользователя
-func (m *JWTManager) GenerateToken(userID int64, username string) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
+func (m *JWTManager) GenerateToken(ctx context.Context, userID int64, username string) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
 
-	claims := token.Claims.(jwt.MapClaims)
-	claims["id"] = userID
-	claims["user_name"] = username
-	claims["exp"] = time.Now().Add(m.duration).Unix()
-	claims["iat"] = time.Now().Unix() // время создания
+	var generation int64
+	if m.revocations != nil {
+		generation, err = m.revocations.Generation(ctx, userID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get token generation: %w", err)
+		}
+	}
 
-	// Подписываем токен секретным ключом из памяти
-	tokenString, err := token.SignedString(m.secret)
+	now := time.Now()
+	claims := &Claims{
+		UserID:     userID,
+		Username:   username,
+		Generation: generation,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.duration)),
+			IssuedAt:  jwt.NewNumericDate(now), // время создания
+		},
+	}
+
+	signing := m.keys.signing
+	token := jwt.NewWithClaims(signing.Method, claims)
+	if signing.ID != "" {
+		token.Header["kid"] = signing.ID
+	}
+
+	// Подписываем токен активным ключом из памяти
+	tokenString, err := token.SignedString(signing.signKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -53,14 +95,11 @@ func (m *JWTManager) GenerateToken(userID int64, username string) (string, error
 }
 
 // VerifyToken проверяет и валидирует токен
-func (m *JWTManager) VerifyToken(tokenString string) (*models.User, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// Проверяем алгоритм подписи
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return m.secret, nil
-	})
+func (m *JWTManager) VerifyToken(ctx context.Context, tokenString string) (*Claims, error) {
+	claims := &Claims{}
+
+	// Ключ выбирается по kid, алгоритм подписи проверяется в keyFunc
+	token, err := jwt.ParseWithClaims(tokenString, claims, m.keys.keyFunc, jwt.WithExpirationRequired())
 
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %w", err)
@@ -70,24 +109,70 @@ func (m *JWTManager) VerifyToken(tokenString string) (*models.User, error) {
 		return nil, errors.New("token is invalid")
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, errors.New("invalid token claims")
-	}
-
-	// Извлекаем данные из токена
-	userID, ok := claims["id"].(float64) // JSON числа → float64 в Go
-	if !ok {
+	if claims.UserID == 0 {
 		return nil, errors.New("invalid user id in token")
 	}
 
-	username, ok := claims["user_name"].(string)
-	if !ok {
+	if claims.Username == "" {
 		return nil, errors.New("invalid username in token")
 	}
 
-	return &models.User{
-		ID:       int64(userID),
-		Username: username,
-	}, nil
+	if claims.ID == "" {
+		return nil, errors.New("invalid token id")
+	}
+
+	if m.revocations != nil {
+		revoked, err := m.revocations.IsRevoked(ctx, claims.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check token revocation: %w", err)
+		}
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
+
+		// Токены старого поколения отозваны через "выход со всех устройств"
+		generation, err := m.revocations.Generation(ctx, claims.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token generation: %w", err)
+		}
+		if claims.Generation < generation {
+			return nil, ErrTokenRevoked
+		}
+	}
+
+	return claims, nil
+}
+
+// JWKS возвращает публичные ключи, которыми можно проверять выданные токены
+func (m *JWTManager) JWKS() JWKS {
+	return m.keys.JWKS()
+}
+
+// RevokeToken отзывает один токен до истечения его срока жизни
+func (m *JWTManager) RevokeToken(ctx context.Context, claims *Claims) error {
+	if m.revocations == nil {
+		return errors.New("token revocation is not configured")
+	}
+
+	return m.revocations.Revoke(ctx, claims.ID, claims.UserID, claims.ExpiresAt.Time)
+}
+
+// RevokeAllTokens отзывает все выданные пользователю токены
+func (m *JWTManager) RevokeAllTokens(ctx context.Context, userID int64) error {
+	if m.revocations == nil {
+		return errors.New("token revocation is not configured")
+	}
+
+	_, err := m.revocations.BumpGeneration(ctx, userID)
+	return err
+}
+
+// newTokenID создаёт уникальный идентификатор токена (claim jti)
+func newTokenID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+
+	return hex.EncodeToString(buf), nil
 }
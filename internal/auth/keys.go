@@ -0,0 +1,290 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSAKeyBits — минимальный допустимый размер RSA ключа
+const minRSAKeyBits = 2048
+
+// Key — ключ подписи или проверки токенов
+type Key struct {
+	ID        string            // kid, попадает в заголовок токена
+	Method    jwt.SigningMethod // Алгоритм подписи
+	signKey   interface{}       // Приватный ключ (nil для ключей только для проверки)
+	verifyKey interface{}       // Публичный ключ (для HMAC — сам секрет)
+}
+
+// KeySet — активный ключ подписи и все ключи, которыми можно проверять токены.
+// При ротации новый ключ становится ключом подписи, а старый остаётся
+// в списке проверки, пока не истекут выданные им токены
+type KeySet struct {
+	signing *Key
+	verify  map[string]*Key
+}
+
+// JWK — публичный ключ в формате RFC 7517
+type JWK struct {
+	Kty string `json:"kty" example:"RSA"`
+	Kid string `json:"kid" example:"NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"`
+	Use string `json:"use" example:"sig"`
+	Alg string `json:"alg" example:"RS256"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty" example:"AQAB"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS — набор публичных ключей (RFC 7517, раздел 5)
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewKeySet собирает набор ключей из конфигурации.
+// Если задан privateKeyFile, токены подписываются им (RS256 или EdDSA по типу ключа).
+// Секрет тогда принимается только при acceptHS256 — для проверки ранее выданных
+// HS256 токенов на время перехода, иначе он не используется.
+// Без privateKeyFile токены подписываются секретом по HS256.
+// publicKeyFiles — дополнительные ключи только для проверки (предыдущие ключи при ротации)
+func NewKeySet(secret string, privateKeyFile string, publicKeyFiles []string, acceptHS256 bool) (*KeySet, error) {
+	ks := &KeySet{verify: make(map[string]*Key)}
+
+	if privateKeyFile != "" {
+		key, err := loadPrivateKey(privateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		ks.verify[key.ID] = key
+		ks.signing = key
+	}
+
+	if secret != "" && (ks.signing == nil || acceptHS256) {
+		// У HMAC ключа нет kid: так подписаны токены, выданные до перехода на асимметричные ключи
+		hmacKey := &Key{
+			Method:    jwt.SigningMethodHS256,
+			signKey:   []byte(secret),
+			verifyKey: []byte(secret),
+		}
+		ks.verify[hmacKey.ID] = hmacKey
+		if ks.signing == nil {
+			ks.signing = hmacKey
+		}
+	} else if acceptHS256 && secret == "" {
+		return nil, errors.New("accepting HS256 tokens requires secret key")
+	}
+
+	for _, file := range publicKeyFiles {
+		if file == "" {
+			continue
+		}
+		key, err := loadPublicKey(file)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := ks.verify[key.ID]; !ok {
+			ks.verify[key.ID] = key
+		}
+	}
+
+	if ks.signing == nil {
+		return nil, errors.New("either secret key or private key file must be set")
+	}
+
+	return ks, nil
+}
+
+// JWKS возвращает публичные ключи проверки. HMAC секрет никогда не публикуется
+func (ks *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+
+	for _, key := range ks.verify {
+		jwk, ok := toJWK(key)
+		if ok {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].Kid < jwks.Keys[j].Kid
+	})
+
+	return jwks
+}
+
+// keyFunc выбирает ключ проверки по kid из заголовка токена
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := ks.verify[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id: %q", kid)
+	}
+
+	// Алгоритм берём из ключа, а не из токена — защита от подмены alg
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.verifyKey, nil
+}
+
+func loadPrivateKey(file string) (*Key, error) {
+	block, err := readPEM(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q in %s", block.Type, file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", file, err)
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type in %s", file)
+	}
+
+	key, err := newKey(signer.Public())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	key.signKey = signer
+
+	return key, nil
+}
+
+func loadPublicKey(file string) (*Key, error) {
+	block, err := readPEM(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var public interface{}
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		public, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		public, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q in %s", block.Type, file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", file, err)
+	}
+
+	key, err := newKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return key, nil
+}
+
+func readPEM(file string) (*pem.Block, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", file)
+	}
+
+	return block, nil
+}
+
+// newKey определяет алгоритм по типу публичного ключа и вычисляет kid
+func newKey(public interface{}) (*Key, error) {
+	key := &Key{verifyKey: public}
+
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+		}
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T (RSA or Ed25519 expected)", public)
+	}
+
+	jwk, _ := toJWK(key)
+	key.ID = thumbprint(jwk)
+
+	return key, nil
+}
+
+// toJWK переводит публичный ключ в JWK. Для HMAC возвращает false
+func toJWK(key *Key) (JWK, bool) {
+	switch pub := key.verifyKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: key.ID,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: key.ID,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+		}, true
+	default:
+		return JWK{}, false
+	}
+}
+
+// thumbprint вычисляет JWK Thumbprint (RFC 7638), который используется как kid.
+// Обязательные поля сериализуются в лексикографическом порядке без пробелов
+func thumbprint(jwk JWK) string {
+	var fields interface{}
+	switch jwk.Kty {
+	case "RSA":
+		fields = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "OKP":
+		fields = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	data, _ := json.Marshal(fields)
+	sum := sha256.Sum256(data)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
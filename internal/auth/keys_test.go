@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestKeySetKeyFunc(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edKey, err := newKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ks, err := NewKeySet("secret", "", nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks.verify[edKey.ID] = edKey
+
+	tests := []struct {
+		name    string
+		method  jwt.SigningMethod
+		kid     any
+		wantKey any
+		wantErr bool
+	}{
+		{name: "hmac without kid", method: jwt.SigningMethodHS256, wantKey: []byte("secret")},
+		{name: "ed25519 by kid", method: jwt.SigningMethodEdDSA, kid: edKey.ID, wantKey: pub},
+		{name: "unknown kid", method: jwt.SigningMethodEdDSA, kid: "unknown", wantErr: true},
+		// Токен, подписанный HMAC с публичным ключом в роли секрета, не должен проверяться этим ключом
+		{name: "hmac alg with asymmetric kid", method: jwt.SigningMethodHS256, kid: edKey.ID, wantErr: true},
+		{name: "asymmetric alg without kid", method: jwt.SigningMethodRS256, wantErr: true},
+		{name: "alg none", method: jwt.SigningMethodNone, wantErr: true},
+		{name: "non-string kid", method: jwt.SigningMethodEdDSA, kid: 42, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := jwt.New(tt.method)
+			if tt.kid != nil {
+				token.Header["kid"] = tt.kid
+			}
+
+			key, err := ks.keyFunc(token)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("keyFunc() = %v, want error", key)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("keyFunc() error = %v", err)
+			}
+
+			switch want := tt.wantKey.(type) {
+			case []byte:
+				if got, ok := key.([]byte); !ok || string(got) != string(want) {
+					t.Fatalf("keyFunc() = %v, want HMAC secret", key)
+				}
+			case ed25519.PublicKey:
+				if got, ok := key.(ed25519.PublicKey); !ok || !got.Equal(want) {
+					t.Fatalf("keyFunc() = %v, want Ed25519 public key", key)
+				}
+			}
+		})
+	}
+}
+
+func TestNewKeySetHS256(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "jwt.pem")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		secret      string
+		keyFile     string
+		acceptHS256 bool
+		wantSigning jwt.SigningMethod
+		wantHMAC    bool
+		wantErr     bool
+	}{
+		{name: "secret only", secret: "secret", wantSigning: jwt.SigningMethodHS256, wantHMAC: true},
+		{name: "private key only", keyFile: keyFile, wantSigning: jwt.SigningMethodEdDSA},
+		{name: "secret with private key", secret: "secret", keyFile: keyFile, wantSigning: jwt.SigningMethodEdDSA},
+		{name: "secret with private key, HS256 accepted", secret: "secret", keyFile: keyFile, acceptHS256: true, wantSigning: jwt.SigningMethodEdDSA, wantHMAC: true},
+		{name: "HS256 accepted without secret", keyFile: keyFile, acceptHS256: true, wantErr: true},
+		{name: "no keys", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ks, err := NewKeySet(tt.secret, tt.keyFile, nil, tt.acceptHS256)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("NewKeySet() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewKeySet() error = %v", err)
+			}
+
+			if ks.signing.Method != tt.wantSigning {
+				t.Errorf("signing method = %v, want %v", ks.signing.Method.Alg(), tt.wantSigning.Alg())
+			}
+			if _, ok := ks.verify[""]; ok != tt.wantHMAC {
+				t.Errorf("HMAC key in verification set = %v, want %v", ok, tt.wantHMAC)
+			}
+		})
+	}
+}
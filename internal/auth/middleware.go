@@ -1,6 +1,7 @@
 package auth
 
 import (
+	resp "NotesService/internal/api/response"
 	"context"
 	"net/http"
 	"strings"
@@ -12,6 +13,7 @@ type contextKey string
 
 const (
 	UserIDKey contextKey = "user_id" // Уникальный ключ
+	ClaimsKey contextKey = "claims"
 )
 
 // JWTAuth — middleware для проверки JWT токена
@@ -36,22 +38,22 @@ func JWTAuth(jwtManager *JWTManager) func(http.Handler) http.Handler {
 
 			tokenString := parts[1]
 
-			// 3. Проверяем токен
-			_, err := jwtManager.VerifyToken(tokenString)
+			// 3. Проверяем токен (подпись, срок жизни, отзыв)
+			claims, err := jwtManager.VerifyToken(r.Context(), tokenString)
 			if err != nil {
+				// Хранилище отозванных токенов не ответило: токен нельзя считать недействительным
+				if status := resp.ErrorStatus(err, http.StatusUnauthorized); status != http.StatusUnauthorized {
+					render.Status(r, status)
+					render.JSON(w, r, map[string]string{"error": http.StatusText(status)})
+					return
+				}
 				render.Status(r, http.StatusUnauthorized)
 				render.JSON(w, r, map[string]string{"error": "Invalid or expired token"})
 				return
 			}
 
-			user, err := jwtManager.VerifyToken(tokenString)
-			if err != nil {
-				render.Status(r, http.StatusUnauthorized)
-				render.JSON(w, r, map[string]string{"error": "Invalid token"})
-				return
-			}
-
-			ctx := context.WithValue(r.Context(), UserIDKey, user.ID)
+			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+			ctx = context.WithValue(ctx, ClaimsKey, claims)
 			r = r.WithContext(ctx)
 			next.ServeHTTP(w, r)
 
@@ -64,3 +66,9 @@ func GetUserID(r *http.Request) (int64, bool) {
 	userID, ok := r.Context().Value(UserIDKey).(int64)
 	return userID, ok
 }
+
+// GetClaims извлекает claims проверенного токена из контекста
+func GetClaims(r *http.Request) (*Claims, bool) {
+	claims, ok := r.Context().Value(ClaimsKey).(*Claims)
+	return claims, ok
+}
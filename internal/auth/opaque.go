@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// opaqueTokenSize — количество случайных байт в непрозрачном токене
+const opaqueTokenSize = 32
+
+// NewOpaqueToken создаёт случайный непрозрачный токен (refresh-токен, публичная ссылка)
+// и его хеш для хранения в базе
+func NewOpaqueToken() (token string, hash string, err error) {
+	buf := make([]byte, opaqueTokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(buf)
+
+	return token, HashToken(token), nil
+}
+
+// HashToken возвращает SHA-256 хеш непрозрачного токена для хранения в базе.
+// Токен содержит 256 бит случайности, поэтому медленный хеш (bcrypt) не нужен
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
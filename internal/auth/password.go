@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyHash используется, когда пользователь не найден,
+// чтобы время ответа не выдавало существование логина
+const dummyHash = "$2a$10$pqTCth/.g2bLCfDky9i3YO0rtWR10ckqUF8bF0CiG4hx3OldNgLum"
+
+// MaxPasswordBytes — предел bcrypt: длинный пароль HashPassword не примет. Тег
+// validate:"max=72" считает символы, а не байты, поэтому длину проверяют отдельно
+const MaxPasswordBytes = 72
+
+// HashPassword хеширует пароль с помощью bcrypt
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return string(hash), nil
+}
+
+// CheckPassword сравнивает пароль с хешем из базы
+func CheckPassword(hash string, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// CheckPasswordDummy выполняет сравнение с фиктивным хешем
+// (вызывается, если пользователь не найден)
+func CheckPasswordDummy(password string) {
+	_ = bcrypt.CompareHashAndPassword([]byte(dummyHash), []byte(password))
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// RefreshToken — непрозрачный refresh-токен.
+// Клиенту отдаётся Token, в базе хранится только Hash
+type RefreshToken struct {
+	Token     string
+	Hash      string
+	ExpiresAt time.Time
+}
+
+// GenerateRefreshToken создаёт новый случайный refresh-токен
+func (m *JWTManager) GenerateRefreshToken() (*RefreshToken, error) {
+	token, hash, err := NewOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	return &RefreshToken{
+		Token:     token,
+		Hash:      hash,
+		ExpiresAt: time.Now().Add(m.refreshDuration),
+	}, nil
+}
+
+// NewTokenFamily создаёт идентификатор семейства refresh-токенов.
+// Все токены, полученные ротацией из одного входа, принадлежат одному семейству
+func NewTokenFamily() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token family: %w", err)
+	}
+
+	return hex.EncodeToString(buf), nil
+}
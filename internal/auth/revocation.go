@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	sl "NotesService/pkg/logger/logSlog"
+)
+
+// RevocationStorage — хранилище отозванных токенов и поколений токенов пользователей
+type RevocationStorage interface {
+	RevokeToken(ctx context.Context, jti string, idUser int64, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	GetTokenGeneration(ctx context.Context, idUser int64) (int64, error)
+	IncrementTokenGeneration(ctx context.Context, idUser int64) (int64, error)
+	PurgeExpiredTokens(ctx context.Context, before time.Time) (int64, error)
+}
+
+// maxNotRevoked — сколько токенов, которые не отозваны, держать в кэше. Их число
+// растёт с каждым входом, поэтому заполненный кэш очищается целиком: потерянные
+// записи стоят лишь лишних запросов к хранилищу
+const maxNotRevoked = 100_000
+
+type generationEntry struct {
+	generation  int64
+	cachedUntil time.Time
+}
+
+// RevocationList проверяет отзыв токенов через хранилище и кэширует ответы в памяти на cacheTTL.
+// Отзыв на этом экземпляре сервиса действует сразу, на остальных — не позже чем через cacheTTL
+type RevocationList struct {
+	log      *slog.Logger
+	storage  RevocationStorage
+	cacheTTL time.Duration
+
+	mu sync.RWMutex
+	// Отозванные токены (до истечения токена) и не отозванные (на cacheTTL), по jti
+	revoked     map[string]time.Time
+	notRevoked  map[string]time.Time
+	generations map[int64]generationEntry
+}
+
+// NewRevocationList создаёт список отзыва поверх хранилища
+func NewRevocationList(log *slog.Logger, storage RevocationStorage, cacheTTL time.Duration) *RevocationList {
+	return &RevocationList{
+		log:         log.With(slog.String("component", "auth/revocation")),
+		storage:     storage,
+		cacheTTL:    cacheTTL,
+		revoked:     make(map[string]time.Time),
+		notRevoked:  make(map[string]time.Time),
+		generations: make(map[int64]generationEntry),
+	}
+}
+
+// Revoke отзывает токен с идентификатором jti до момента expiresAt
+func (l *RevocationList) Revoke(ctx context.Context, jti string, userID int64, expiresAt time.Time) error {
+	if err := l.storage.RevokeToken(ctx, jti, userID, expiresAt); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.revoked[jti] = expiresAt
+	delete(l.notRevoked, jti)
+	l.mu.Unlock()
+
+	return nil
+}
+
+// IsRevoked сообщает, отозван ли токен
+func (l *RevocationList) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	now := time.Now()
+
+	l.mu.RLock()
+	revokedUntil, revoked := l.revoked[jti]
+	cachedUntil, notRevoked := l.notRevoked[jti]
+	l.mu.RUnlock()
+	if revoked && now.Before(revokedUntil) {
+		return true, nil
+	}
+	if notRevoked && now.Before(cachedUntil) {
+		return false, nil
+	}
+
+	revoked, err := l.storage.IsTokenRevoked(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+
+	l.mu.Lock()
+	if revoked {
+		// Срок токена здесь неизвестен: кэшируем как обычный ответ
+		l.revoked[jti] = now.Add(l.cacheTTL)
+	} else {
+		if len(l.notRevoked) >= maxNotRevoked {
+			clear(l.notRevoked)
+		}
+		l.notRevoked[jti] = now.Add(l.cacheTTL)
+	}
+	l.mu.Unlock()
+
+	return revoked, nil
+}
+
+// Generation возвращает текущее поколение токенов пользователя
+func (l *RevocationList) Generation(ctx context.Context, userID int64) (int64, error) {
+	now := time.Now()
+
+	l.mu.RLock()
+	entry, ok := l.generations[userID]
+	l.mu.RUnlock()
+	if ok && now.Before(entry.cachedUntil) {
+		return entry.generation, nil
+	}
+
+	generation, err := l.storage.GetTokenGeneration(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	l.mu.Lock()
+	l.generations[userID] = generationEntry{generation: generation, cachedUntil: now.Add(l.cacheTTL)}
+	l.mu.Unlock()
+
+	return generation, nil
+}
+
+// BumpGeneration увеличивает поколение токенов пользователя,
+// делая недействительными все ранее выданные ему токены
+func (l *RevocationList) BumpGeneration(ctx context.Context, userID int64) (int64, error) {
+	generation, err := l.storage.IncrementTokenGeneration(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	l.mu.Lock()
+	l.generations[userID] = generationEntry{generation: generation, cachedUntil: time.Now().Add(l.cacheTTL)}
+	l.mu.Unlock()
+
+	return generation, nil
+}
+
+// Run периодически очищает кэш и удаляет из хранилища истёкшие токены.
+// Блокируется до отмены ctx
+func (l *RevocationList) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.purge(ctx)
+		}
+	}
+}
+
+func (l *RevocationList) purge(ctx context.Context) {
+	now := time.Now()
+
+	l.mu.Lock()
+	for jti, until := range l.revoked {
+		if now.After(until) {
+			delete(l.revoked, jti)
+		}
+	}
+	for jti, until := range l.notRevoked {
+		if now.After(until) {
+			delete(l.notRevoked, jti)
+		}
+	}
+	for userID, entry := range l.generations {
+		if now.After(entry.cachedUntil) {
+			delete(l.generations, userID)
+		}
+	}
+	l.mu.Unlock()
+
+	purged, err := l.storage.PurgeExpiredTokens(ctx, now)
+	if err != nil {
+		l.log.Error("failed to purge expired tokens", sl.Err(err))
+		return
+	}
+
+	l.log.Debug("expired tokens purged", slog.Int64("count", purged))
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// revocationStub хранит отозванные jti и считает обращения к IsTokenRevoked
+type revocationStub struct {
+	revoked map[string]bool
+	lookups int
+}
+
+func (s *revocationStub) RevokeToken(_ context.Context, jti string, _ int64, _ time.Time) error {
+	s.revoked[jti] = true
+	return nil
+}
+
+func (s *revocationStub) IsTokenRevoked(_ context.Context, jti string) (bool, error) {
+	s.lookups++
+	return s.revoked[jti], nil
+}
+
+func (s *revocationStub) GetTokenGeneration(context.Context, int64) (int64, error) { return 0, nil }
+
+func (s *revocationStub) IncrementTokenGeneration(context.Context, int64) (int64, error) {
+	return 1, nil
+}
+
+func (s *revocationStub) PurgeExpiredTokens(context.Context, time.Time) (int64, error) { return 0, nil }
+
+func TestRevocationListCache(t *testing.T) {
+	stub := &revocationStub{revoked: map[string]bool{"revoked": true}}
+	l := NewRevocationList(slog.New(slog.DiscardHandler), stub, time.Minute)
+
+	for range 2 {
+		if revoked, err := l.IsRevoked(t.Context(), "valid"); err != nil || revoked {
+			t.Fatalf("IsRevoked(valid) = %v, %v", revoked, err)
+		}
+		if revoked, err := l.IsRevoked(t.Context(), "revoked"); err != nil || !revoked {
+			t.Fatalf("IsRevoked(revoked) = %v, %v", revoked, err)
+		}
+	}
+	if stub.lookups != 2 {
+		t.Errorf("storage lookups = %d, want 2 (answers are cached)", stub.lookups)
+	}
+
+	// Отзыв на этом экземпляре заменяет закэшированный ответ «не отозван»
+	if err := l.Revoke(t.Context(), "valid", 1, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, err := l.IsRevoked(t.Context(), "valid"); err != nil || !revoked {
+		t.Fatalf("IsRevoked(valid) after Revoke = %v, %v", revoked, err)
+	}
+}
+
+func TestRevocationListBounded(t *testing.T) {
+	stub := &revocationStub{revoked: map[string]bool{}}
+	l := NewRevocationList(slog.New(slog.DiscardHandler), stub, time.Minute)
+
+	if err := l.Revoke(t.Context(), "revoked", 1, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range maxNotRevoked + 10 {
+		if _, err := l.IsRevoked(t.Context(), strconv.Itoa(i)); err != nil {
+			t.Fatal(err)
+		}
+		if len(l.notRevoked) > maxNotRevoked {
+			t.Fatalf("not revoked cache grew to %d entries, limit %d", len(l.notRevoked), maxNotRevoked)
+		}
+	}
+
+	// Отозванные токены не вытесняются
+	lookups := stub.lookups
+	if revoked, err := l.IsRevoked(t.Context(), "revoked"); err != nil || !revoked {
+		t.Fatalf("IsRevoked(revoked) = %v, %v", revoked, err)
+	}
+	if stub.lookups != lookups {
+		t.Error("revoked token was evicted from the cache")
+	}
+}
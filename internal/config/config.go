@@ -1,8 +1,10 @@
 package config
 
 import (
+	"NotesService/pkg/logger/redact"
 	"fmt"
 	"log"
+	"log/slog"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
@@ -13,14 +15,37 @@ type Config struct {
 	// Окружение: local, dev, prod
 	Env string `env:"ENV" env-default:"local"`
 
+	// Хранилище: postgres, sqlite (файл на диске, для одного экземпляра сервиса)
+	// или memory (данные в памяти процесса, для разработки и тестов)
+	Storage struct {
+		Driver string `env:"STORAGE_DRIVER" env-default:"postgres"`
+		// Предельное время одного обращения к postgres или sqlite. Запрос к хранилищу
+		// прерывается и раньше, если клиент отключился или истёк HTTP_TIMEOUT
+		QueryTimeout time.Duration `env:"DB_QUERY_TIMEOUT" env-default:"3s"`
+	}
+
+	// Логи
+	Log struct {
+		// Дополнительные ключи атрибутов, значения которых скрываются в логах. Учётные данные
+		// и токены скрываются всегда, содержимое заметок — во всех окружениях, кроме local
+		RedactKeys []string `env:"LOG_REDACT_KEYS" env-separator:","`
+	}
+
+	// SQLite
+	SQLite struct {
+		Path string `env:"SQLITE_PATH" env-default:"./data/notes.db"`
+	}
+
 	// PostgreSQL
 	DB struct {
 		Host     string `env:"DB_HOST" env-default:"localhost"`
 		Port     int    `env:"DB_PORT" env-default:"5432"`
 		User     string `env:"POSTGRES_USER" env-default:"postgres"`
-		Password string `env:"POSTGRES_PASSWORD" env-default:"postgres"`
+		Password string `env:"POSTGRES_PASSWORD" env-default:"postgres" log:"secret"`
 		Name     string `env:"DB_NAME" env-default:"notes_service"`
 		SSLMode  string `env:"DB_SSLMODE" env-default:"disable"`
+		// Применять миграции при старте (и для sqlite); при false схема обновляется командой migrate up
+		AutoMigrate bool `env:"DB_AUTO_MIGRATE" env-default:"true"`
 	}
 
 	// HTTP Server
@@ -29,7 +54,87 @@ type Config struct {
 		Timeout     time.Duration `env:"HTTP_TIMEOUT" env-default:"4s"`
 		IdleTimeout time.Duration `env:"HTTP_IDLE_TIMEOUT" env-default:"60s"`
 		User        string        `env:"HTTP_USER" env-default:"user"`
-		Password    string        `env:"HTTP_PASSWORD" env-default:"user"`
+		Password    string        `env:"HTTP_PASSWORD" env-default:"user" log:"secret"`
+		// Сколько ждать после снятия готовности (/readyz и /ping отвечают 503), прежде чем перестать
+		// принимать соединения: за это время балансировщик выводит экземпляр из ротации
+		ShutdownDelay time.Duration `env:"HTTP_SHUTDOWN_DELAY" env-default:"5s"`
+		// Сколько ждать завершения обрабатываемых запросов при остановке сервиса
+		ShutdownTimeout time.Duration `env:"HTTP_SHUTDOWN_TIMEOUT" env-default:"15s"`
+		// Требовать If-Match при изменении и удалении заметок (иначе 428 Precondition Required)
+		RequireIfMatch bool `env:"HTTP_REQUIRE_IF_MATCH" env-default:"false"`
+	}
+
+	// Проверка готовности (/readyz)
+	Health struct {
+		// Предельное время одной проверки зависимости
+		CheckTimeout time.Duration `env:"HEALTH_CHECK_TIMEOUT" env-default:"2s"`
+	}
+
+	// Трассировка OpenTelemetry
+	Tracing struct {
+		// Куда отправлять спаны: none (не записывать), otlp (коллектор по OTLP/HTTP),
+		// stdout или file (для локального запуска)
+		Exporter string `env:"TRACING_EXPORTER" env-default:"none"`
+		// Адрес коллектора, например http://otel-collector:4318. Если не задан,
+		// используется OTEL_EXPORTER_OTLP_ENDPOINT или http://localhost:4318
+		OTLPEndpoint string `env:"TRACING_OTLP_ENDPOINT"`
+		// Файл для TRACING_EXPORTER=file, спаны пишутся по одному JSON на строку
+		File string `env:"TRACING_FILE" env-default:"./data/traces.jsonl"`
+		// Доля записываемых трассировок (0..1); при входящем traceparent решение берётся из него
+		SampleRatio float64 `env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+		ServiceName string  `env:"TRACING_SERVICE_NAME" env-default:"notes-service"`
+	}
+
+	// JWT
+	JWT struct {
+		Secret string `env:"JWT_SECRET" log:"secret"`
+		// PEM файл с приватным ключом RSA или Ed25519. Если задан, токены подписываются им,
+		// а JWT_SECRET не используется, пока не включён JWT_ACCEPT_HS256
+		PrivateKeyFile string `env:"JWT_PRIVATE_KEY_FILE"`
+		// Вместе с JWT_PRIVATE_KEY_FILE: принимать ранее выданные HS256 токены, подписанные JWT_SECRET.
+		// Включается на время перехода на асимметричные ключи, не дольше JWT_TOKEN_TTL
+		AcceptHS256 bool `env:"JWT_ACCEPT_HS256" env-default:"false"`
+		// PEM файлы с публичными ключами, которые ещё принимаются при проверке (ротация ключей)
+		PublicKeyFiles []string      `env:"JWT_PUBLIC_KEY_FILES" env-separator:","`
+		TokenTTL       time.Duration `env:"JWT_TOKEN_TTL" env-default:"30m"`
+		RefreshTTL     time.Duration `env:"JWT_REFRESH_TTL" env-default:"720h"`
+		// Сколько кэшировать в памяти результат проверки отзыва токена
+		RevocationCacheTTL time.Duration `env:"JWT_REVOCATION_CACHE_TTL" env-default:"30s"`
+		// Как часто удалять из базы истёкшие отозванные и refresh-токены
+		PurgeInterval time.Duration `env:"JWT_PURGE_INTERVAL" env-default:"10m"`
+	}
+
+	// Публичные ссылки на заметки
+	Links struct {
+		// Сколько неверных паролей допускается для защищённой ссылки за LINK_PASSWORD_LOCKOUT.
+		// Дальше ссылка до конца этого окна отвечает 429, не проверяя пароль
+		PasswordAttempts int           `env:"LINK_PASSWORD_ATTEMPTS" env-default:"5"`
+		PasswordLockout  time.Duration `env:"LINK_PASSWORD_LOCKOUT" env-default:"15m"`
+	}
+
+	// Пагинация списков
+	Pagination struct {
+		// Ключ подписи курсоров. Если не задан, генерируется при запуске и курсоры
+		// перестают действовать после перезапуска (и не подходят другим экземплярам сервиса)
+		CursorSecret string `env:"PAGINATION_CURSOR_SECRET" log:"secret"`
+	}
+
+	// История версий заметок
+	Revisions struct {
+		// Сколько последних версий хранить для каждой заметки (0 — без ограничения)
+		MaxCount int `env:"REVISIONS_MAX_COUNT" env-default:"50"`
+		// Сколько хранить версию (0 — без ограничения)
+		MaxAge time.Duration `env:"REVISIONS_MAX_AGE" env-default:"2160h"`
+		// Как часто удалять версии, вышедшие за эти ограничения
+		PruneInterval time.Duration `env:"REVISIONS_PRUNE_INTERVAL" env-default:"1h"`
+	}
+
+	// Корзина
+	Trash struct {
+		// Сколько заметка хранится в корзине до окончательного удаления
+		Retention time.Duration `env:"TRASH_RETENTION" env-default:"720h"`
+		// Как часто удалять из корзины заметки с истёкшим сроком хранения
+		PurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL" env-default:"1h"`
 	}
 }
 
@@ -63,6 +168,18 @@ func validate(cfg *Config) {
 		log.Fatalf("Invalid ENV: %s (allowed: local, dev, prod)", cfg.Env)
 	}
 
+	allowedDrivers := map[string]bool{
+		"postgres": true,
+		"sqlite":   true,
+		"memory":   true,
+	}
+	if !allowedDrivers[cfg.Storage.Driver] {
+		log.Fatalf("Invalid STORAGE_DRIVER: %s (allowed: postgres, sqlite, memory)", cfg.Storage.Driver)
+	}
+	if cfg.Storage.Driver == "sqlite" && cfg.SQLite.Path == "" {
+		log.Fatal("SQLITE_PATH must be set for STORAGE_DRIVER=sqlite")
+	}
+
 	// Проверка порта БД
 	if cfg.DB.Port < 1 || cfg.DB.Port > 65535 {
 		log.Fatalf("Invalid DB_PORT: %d (must be 1-65535)", cfg.DB.Port)
@@ -75,6 +192,74 @@ func validate(cfg *Config) {
 	if cfg.HTTPServer.IdleTimeout <= 0 {
 		log.Fatal("HTTP_IDLE_TIMEOUT must be positive")
 	}
+	if cfg.HTTPServer.ShutdownDelay < 0 {
+		log.Fatal("HTTP_SHUTDOWN_DELAY must not be negative")
+	}
+	if cfg.HTTPServer.ShutdownTimeout <= 0 {
+		log.Fatal("HTTP_SHUTDOWN_TIMEOUT must be positive")
+	}
+	if cfg.Health.CheckTimeout <= 0 {
+		log.Fatal("HEALTH_CHECK_TIMEOUT must be positive")
+	}
+	if cfg.Storage.QueryTimeout <= 0 {
+		log.Fatal("DB_QUERY_TIMEOUT must be positive")
+	}
+	allowedExporters := map[string]bool{
+		"none":   true,
+		"otlp":   true,
+		"stdout": true,
+		"file":   true,
+	}
+	if !allowedExporters[cfg.Tracing.Exporter] {
+		log.Fatalf("Invalid TRACING_EXPORTER: %s (allowed: none, otlp, stdout, file)", cfg.Tracing.Exporter)
+	}
+	if cfg.Tracing.Exporter == "file" && cfg.Tracing.File == "" {
+		log.Fatal("TRACING_FILE must be set for TRACING_EXPORTER=file")
+	}
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		log.Fatalf("Invalid TRACING_SAMPLE_RATIO: %v (must be 0-1)", cfg.Tracing.SampleRatio)
+	}
+	if cfg.JWT.Secret == "" && cfg.JWT.PrivateKeyFile == "" {
+		log.Fatal("either JWT_SECRET or JWT_PRIVATE_KEY_FILE must be set")
+	}
+	if cfg.JWT.TokenTTL <= 0 {
+		log.Fatal("JWT_TOKEN_TTL must be positive")
+	}
+	if cfg.JWT.RefreshTTL <= cfg.JWT.TokenTTL {
+		log.Fatal("JWT_REFRESH_TTL must be greater than JWT_TOKEN_TTL")
+	}
+	if cfg.JWT.RevocationCacheTTL < 0 {
+		log.Fatal("JWT_REVOCATION_CACHE_TTL must not be negative")
+	}
+	if cfg.JWT.PurgeInterval <= 0 {
+		log.Fatal("JWT_PURGE_INTERVAL must be positive")
+	}
+	if cfg.Links.PasswordAttempts <= 0 {
+		log.Fatal("LINK_PASSWORD_ATTEMPTS must be positive")
+	}
+	if cfg.Links.PasswordLockout <= 0 {
+		log.Fatal("LINK_PASSWORD_LOCKOUT must be positive")
+	}
+	if cfg.Revisions.MaxCount < 0 {
+		log.Fatal("REVISIONS_MAX_COUNT must not be negative")
+	}
+	if cfg.Revisions.MaxAge < 0 {
+		log.Fatal("REVISIONS_MAX_AGE must not be negative")
+	}
+	if cfg.Revisions.PruneInterval <= 0 {
+		log.Fatal("REVISIONS_PRUNE_INTERVAL must be positive")
+	}
+	if cfg.Trash.Retention <= 0 {
+		log.Fatal("TRASH_RETENTION must be positive")
+	}
+	if cfg.Trash.PurgeInterval <= 0 {
+		log.Fatal("TRASH_PURGE_INTERVAL must be positive")
+	}
+}
+
+// LogValue выводит конфигурацию в лог без паролей и ключей (поля с тегом log:"secret")
+func (c Config) LogValue() slog.Value {
+	return redact.Struct(c)
 }
 
 func (c *Config) StoragePath() string {
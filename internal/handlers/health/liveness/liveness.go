@@ -0,0 +1,22 @@
+package liveness
+
+import (
+	"NotesService/internal/health"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// Liveness godoc
+// @Summary Liveness probe
+// @Description Reports that the process is running and serving HTTP. Dependencies are not checked, so a database outage does not make the orchestrator restart the service.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Router /healthz [get]
+func New() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, health.Report{Status: health.StatusOK})
+	}
+}
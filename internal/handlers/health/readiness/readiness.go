@@ -0,0 +1,53 @@
+package readiness
+
+import (
+	"NotesService/internal/health"
+	sl "NotesService/pkg/logger/logSlog"
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Checker interface {
+	Check(ctx context.Context, timeout time.Duration) health.Report
+}
+
+// Readiness godoc
+// @Summary Readiness probe
+// @Description Runs all registered dependency checks (database connectivity, applied migrations) and returns the status of each one. Error details are only written to the service log. Responds 503 if any check fails or the service is shutting down.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /readyz [get]
+func New(log *slog.Logger, checker Checker, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.readiness.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		report := checker.Check(r.Context(), timeout)
+
+		if report.Status != health.StatusOK {
+			for name, result := range report.Checks {
+				if result.Err != nil {
+					log.Warn("Readiness check failed", slog.String("check", name), slog.String("duration", result.Duration), sl.Err(result.Err))
+				}
+			}
+			log.Warn("Service is not ready", slog.String("status", report.Status))
+			render.Status(r, http.StatusServiceUnavailable)
+			render.JSON(w, r, report)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, report)
+	}
+}
@@ -0,0 +1,42 @@
+package getJWKS
+
+import (
+	"NotesService/internal/auth"
+	sl "NotesService/pkg/logger/logSlog"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type KeySet interface {
+	JWKS() auth.JWKS
+}
+
+// GetJWKS godoc
+// @Summary Get token verification keys
+// @Description Returns the public keys used to verify access tokens, in JWK Set format (RFC 7517). Keys are matched by the "kid" token header. HS256 secrets are never published.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} auth.JWKS
+// @Router /.well-known/jwks.json [get]
+func New(log *slog.Logger, keySet KeySet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.getJWKS.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		jwks := keySet.JWKS()
+
+		log.Debug("Success", slog.Int("keys", len(jwks.Keys)))
+
+		// Ключи меняются только при перезапуске, клиенты могут их кэшировать
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, jwks)
+	}
+}
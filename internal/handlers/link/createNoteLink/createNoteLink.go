@@ -0,0 +1,179 @@
+package createNoteLink
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	sl "NotesService/pkg/logger/logSlog"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type LinkStorage interface {
+	storage.LinkStorage
+}
+
+// CreateNoteLink godoc
+// @Summary Create a public link to a note
+// @Description Creates an unguessable read-only link to a note that can be opened without an account. The link can expire and can be protected with a password. The token is returned only once. Requires JWT authentication.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path int true "Owner user ID" minimum(1)
+// @Param note_id path int true "Note ID" minimum(1)
+// @Param request body models.CreateLinkRequest false "Link options"
+// @Success 201 {object} models.LinkResponse "Created link"
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/notes/{note_id}/links [post]
+func New(log *slog.Logger, createNoteLink LinkStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.createNoteLink.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		// Тело запроса необязательно: без него создаётся бессрочная ссылка без пароля
+		var req models.CreateLinkRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil && err != io.EOF {
+			if strings.Contains(err.Error(), "invalid character") {
+				log.Info("Invalid JSON format", slog.String("error", err.Error()))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid JSON format"))
+				return
+			}
+
+			log.Error("Failed to decode request body", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Failed to decode request body"))
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+			log.Error("Failed to validate request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+			return
+		}
+
+		if len(req.Password) > auth.MaxPasswordBytes {
+			log.Info("Link password is too long")
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(fmt.Sprintf("field Password must be at most %d bytes", auth.MaxPasswordBytes)))
+			return
+		}
+
+		if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+			log.Info("Expiry is in the past", slog.Time("expires_at", *req.ExpiresAt))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("expires_at must be in the future"))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		if authorizedUserID != idUser {
+			log.Warn("Unauthorized access attempt",
+				slog.Int64("authorized_user_id", authorizedUserID),
+				slog.Int64("requested_user_id", idUser),
+			)
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Not found"))
+			return
+		}
+
+		idNote, err := strconv.ParseInt(chi.URLParam(r, "note_id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		var passwordHash string
+		if req.Password != "" {
+			passwordHash, err = auth.HashPassword(req.Password)
+			if err != nil {
+				log.Error("Failed to hash password", sl.Err(err))
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Failed to create link"))
+				return
+			}
+		}
+
+		token, tokenHash, err := auth.NewOpaqueToken()
+		if err != nil {
+			log.Error("Failed to generate link token", sl.Err(err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Failed to create link"))
+			return
+		}
+
+		link, err := createNoteLink.CreateNoteLink(r.Context(), idUser, idNote, tokenHash, passwordHash, req.ExpiresAt)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrNoteNotFound) {
+				log.Error("Note not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Note not found"))
+				return
+			}
+			log.Error("Failed to create link", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to create link"))
+			return
+		}
+
+		log.Info("Success", slog.Int64("idNote", idNote), slog.Int64("idLink", link.ID))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, models.LinkResponse{
+			Response: resp.Created("Success"),
+			LinkItem: models.LinkItem{
+				ID:          link.ID,
+				HasPassword: link.PasswordHash != "",
+				ExpiresAt:   link.ExpiresAt,
+				ViewCount:   link.ViewCount,
+				CreatedAt:   link.CreatedAt,
+			},
+			Token: token,
+			URL:   "/public/notes/" + token,
+		})
+	}
+}
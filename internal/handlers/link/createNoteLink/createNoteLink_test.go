@@ -0,0 +1,63 @@
+package createNoteLink
+
+import (
+	"NotesService/internal/auth"
+	"NotesService/internal/storage/memory"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestPasswordLength(t *testing.T) {
+	s := memory.New()
+	u, err := s.RegisterUser(t.Context(), "owner", "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, _, err := s.SaveNotes(t.Context(), "title", "content", u.ID, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		want     int
+	}{
+		{name: "no password", want: http.StatusCreated},
+		{name: "72 bytes", password: strings.Repeat("a", 72), want: http.StatusCreated},
+		// 40 символов, но 80 байт: bcrypt такой пароль не примет
+		{name: "multibyte over limit", password: strings.Repeat("я", 40), want: http.StatusBadRequest},
+	}
+
+	router := chi.NewRouter()
+	router.Post("/users/{id}/notes/{note_id}/links", New(slog.New(slog.DiscardHandler), s))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(map[string]string{"password": tt.password})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			path := "/users/" + strconv.FormatInt(u.ID, 10) + "/notes/" + strconv.FormatInt(note.ID, 10) + "/links"
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(string(body)))
+			req.Header.Set("Content-Type", "application/json")
+			req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, u.ID))
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("got status %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package getNoteLinks
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	sl "NotesService/pkg/logger/logSlog"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type LinkStorage interface {
+	storage.LinkStorage
+}
+
+// GetNoteLinks godoc
+// @Summary List public links of a note
+// @Description Returns all public links of a note, including revoked and expired ones, with their view counters. Tokens are not returned. Requires JWT authentication.
+// @Tags links
+// @Produce json
+// @Param id path int true "Owner user ID" minimum(1)
+// @Param note_id path int true "Note ID" minimum(1)
+// @Success 200 {object} models.LinkListResponse
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/notes/{note_id}/links [get]
+func New(log *slog.Logger, getNoteLinks LinkStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.getNoteLinks.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		if authorizedUserID != idUser {
+			log.Warn("Unauthorized access attempt",
+				slog.Int64("authorized_user_id", authorizedUserID),
+				slog.Int64("requested_user_id", idUser),
+			)
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Not found"))
+			return
+		}
+
+		idNote, err := strconv.ParseInt(chi.URLParam(r, "note_id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		links, err := getNoteLinks.GetNoteLinks(r.Context(), idUser, idNote)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrNoteNotFound) {
+				log.Error("Note not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Note not found"))
+				return
+			}
+			log.Error("Failed to get links", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to get links"))
+			return
+		}
+
+		items := make([]models.LinkItem, 0, len(links))
+		for _, link := range links {
+			items = append(items, models.LinkItem{
+				ID:          link.ID,
+				HasPassword: link.PasswordHash != "",
+				ExpiresAt:   link.ExpiresAt,
+				Revoked:     link.RevokedAt != nil,
+				ViewCount:   link.ViewCount,
+				CreatedAt:   link.CreatedAt,
+			})
+		}
+
+		log.Info("Success", slog.Int64("idNote", idNote), slog.Int("count", len(items)))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, models.LinkListResponse{
+			Response: resp.OK("Success"),
+			NoteID:   idNote,
+			Links:    items,
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package getPublicNote
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	sl "NotesService/pkg/logger/logSlog"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// PasswordHeader — заголовок с паролем защищённой ссылки.
+// Пароль не передаётся в URL, чтобы он не попадал в логи прокси
+const PasswordHeader = "X-Link-Password"
+
+type LinkStorage interface {
+	storage.LinkStorage
+}
+
+// PasswordLimiter ограничивает число неверных паролей для одной ссылки.
+// Allow заранее учитывает попытку как неудачную, Reset снимает учёт после верного пароля
+type PasswordLimiter interface {
+	Allow(idLink int64) (time.Duration, bool)
+	Reset(idLink int64)
+}
+
+// GetPublicNote godoc
+// @Summary Open a public link
+// @Description Returns a note by its public link token. Does not require authentication. Password-protected links require the X-Link-Password header; after too many wrong passwords the link is locked for a while and answers 429 with Retry-After. Every successful view increments the link's view counter.
+// @Tags links
+// @Produce json
+// @Param token path string true "Link token"
+// @Param X-Link-Password header string false "Link password"
+// @Success 200 {object} models.PublicNoteResponse
+// @Failure 401
+// @Failure 404
+// @Failure 410
+// @Failure 429
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Router /public/notes/{token} [get]
+func New(log *slog.Logger, getPublicNote LinkStorage, limiter PasswordLimiter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.getPublicNote.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		token := chi.URLParam(r, "token")
+		if token == "" {
+			log.Info("Token is empty")
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, resp.Error("Link not found"))
+			return
+		}
+
+		link, err := getPublicNote.GetNoteLink(r.Context(), auth.HashToken(token))
+		if err != nil {
+			if errors.Is(err, storageErr.ErrLinkNotFound) {
+				log.Info("Link not found")
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Link not found"))
+				return
+			}
+			log.Error("Failed to get link", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to get note"))
+			return
+		}
+
+		log = log.With(slog.Int64("idLink", link.ID))
+
+		if link.RevokedAt != nil || (link.ExpiresAt != nil && !link.ExpiresAt.After(time.Now())) {
+			log.Info("Link is revoked or expired")
+			render.Status(r, http.StatusGone)
+			render.JSON(w, r, resp.Error("Link is no longer available"))
+			return
+		}
+
+		if link.PasswordHash != "" {
+			// Заблокированная ссылка не проверяет пароль вовсе: перебор не тратит CPU на bcrypt
+			if retryAfter, ok := limiter.Allow(link.ID); !ok {
+				log.Warn("Link is locked after failed password attempts")
+				w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Round(time.Second).Seconds())))
+				render.Status(r, http.StatusTooManyRequests)
+				render.JSON(w, r, resp.Error("Too many password attempts, try again later"))
+				return
+			}
+
+			if !auth.CheckPassword(link.PasswordHash, r.Header.Get(PasswordHeader)) {
+				log.Info("Invalid link password")
+				render.Status(r, http.StatusUnauthorized)
+				render.JSON(w, r, resp.Error("Password required"))
+				return
+			}
+			limiter.Reset(link.ID)
+		}
+
+		note, views, err := getPublicNote.ViewNoteLink(r.Context(), link.ID)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrLinkNotFound) {
+				// Ссылку отозвали или она истекла между двумя запросами
+				log.Info("Link is no longer available")
+				render.Status(r, http.StatusGone)
+				render.JSON(w, r, resp.Error("Link is no longer available"))
+				return
+			}
+			log.Error("Failed to get note", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to get note"))
+			return
+		}
+
+		log.Info("Success", slog.Int64("idNote", note.ID))
+
+		// Заметка может измениться или ссылку могут отозвать — не кэшируем
+		w.Header().Set("Cache-Control", "no-store")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, models.PublicNoteResponse{
+			Response:  resp.OK("Success"),
+			Title:     note.Title,
+			Content:   note.Content,
+			CreatedAt: note.CreatedAt,
+			UpdatedAt: note.UpdatedAt,
+			ViewCount: views,
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package revokeNoteLink
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	sl "NotesService/pkg/logger/logSlog"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type LinkStorage interface {
+	storage.LinkStorage
+}
+
+// RevokeNoteLink godoc
+// @Summary Revoke a public link
+// @Description Revokes a public link so it can no longer be opened. Requires JWT authentication.
+// @Tags links
+// @Produce json
+// @Param id path int true "Owner user ID" minimum(1)
+// @Param note_id path int true "Note ID" minimum(1)
+// @Param link_id path int true "Link ID" minimum(1)
+// @Success 200 {object} models.DeleteResponse
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/notes/{note_id}/links/{link_id} [delete]
+func New(log *slog.Logger, revokeNoteLink LinkStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.revokeNoteLink.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		if authorizedUserID != idUser {
+			log.Warn("Unauthorized access attempt",
+				slog.Int64("authorized_user_id", authorizedUserID),
+				slog.Int64("requested_user_id", idUser),
+			)
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Not found"))
+			return
+		}
+
+		idNote, err := strconv.ParseInt(chi.URLParam(r, "note_id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		idLink, err := strconv.ParseInt(chi.URLParam(r, "link_id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		err = revokeNoteLink.RevokeNoteLink(r.Context(), idUser, idNote, idLink)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrLinkNotFound) {
+				log.Info("Link not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Link not found"))
+				return
+			}
+			log.Error("Failed to revoke link", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to revoke link"))
+			return
+		}
+
+		log.Info("Success", slog.Int64("idNote", idNote), slog.Int64("idLink", idLink))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, models.DeleteResponse{Response: resp.OK("Link revoked")})
+	}
+}
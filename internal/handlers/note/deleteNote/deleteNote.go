@@ -1,6 +1,7 @@
 package deleteNote
 
 import (
+	"NotesService/internal/api/etag"
 	resp "NotesService/internal/api/response"
 	"NotesService/internal/auth"
 	"NotesService/internal/models"
@@ -23,23 +24,28 @@ type NoteStorage interface {
 
 // DeleteNote godoc
 // @Summary Delete a note
-// @Description Delete a specific note by ID. Requires JWT authentication.
+// @Description Moves a specific note to the trash. Notes in the trash can be restored until they are permanently removed after the retention period or when the trash is emptied. Requires JWT authentication.
 // @Tags notes
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID" minimum(1)
 // @Param note_id path int true "Note ID" minimum(1)
+// @Param If-Match header string false "ETag of the note version being deleted"
 // @Success 200 {object} models.DeleteResponse
 // @Failure 400
 // @Failure 401
 // @Failure 404
+// @Failure 412
+// @Failure 428
 // @Failure 500
+// @Failure 503
+// @Failure 504
 // @Security ApiKeyAuth
 // @Router /users/{id}/notes/{note_id} [delete]
-func New(log *slog.Logger, deleteNote NoteStorage) http.HandlerFunc {
+func New(log *slog.Logger, deleteNote NoteStorage, requireIfMatch bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handler.url.deleteNote.New"
-		log = log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		log := sl.WithContext(log, r.Context()).With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
 		authorizedUserID, ok := auth.GetUserID(r)
 		if !ok {
 			log.Error("user_id not found in context")
@@ -81,8 +87,46 @@ func New(log *slog.Logger, deleteNote NoteStorage) http.HandlerFunc {
 			render.JSON(w, r, resp.Error("Not found"))
 			return
 		}
-		err = deleteNote.DeleteNote(idUser, idNote)
+		// Условный запрос: If-Match защищает от удаления заметки, изменённой с другого устройства
+		version, hasIfMatch, err := etag.IfMatch(r, func() (int64, error) {
+			note, err := deleteNote.GetOneNote(r.Context(), idUser, idNote)
+			if err != nil {
+				return 0, err
+			}
+			return note.Version, nil
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, etag.ErrMismatch):
+				log.Info("If-Match does not match", slog.String("if_match", r.Header.Get("If-Match")))
+				render.Status(r, http.StatusPreconditionFailed)
+				render.JSON(w, r, resp.Error("Note has been modified"))
+			case errors.Is(err, storageErr.ErrNoteNotFound):
+				log.Info("Note not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Note not found"))
+			default:
+				log.Error("Failed to get note", "error", sl.Err(err))
+				render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+				render.JSON(w, r, resp.Error("Failed to get note"))
+			}
+			return
+		}
+		if !hasIfMatch && requireIfMatch {
+			log.Info("If-Match header is missing")
+			render.Status(r, http.StatusPreconditionRequired)
+			render.JSON(w, r, resp.Error("If-Match header is required"))
+			return
+		}
+
+		err = deleteNote.DeleteNote(r.Context(), idUser, idNote, version)
 		if err != nil {
+			if errors.Is(err, storageErr.ErrVersionMismatch) {
+				log.Info("Note version mismatch", slog.Int64("if_match", version))
+				render.Status(r, http.StatusPreconditionFailed)
+				render.JSON(w, r, resp.Error("Note has been modified"))
+				return
+			}
 			if errors.Is(err, storageErr.ErrNoteNotFound) {
 				log.Error("Note not found", "error", sl.Err(err))
 				render.Status(r, http.StatusNotFound)
@@ -90,7 +134,7 @@ func New(log *slog.Logger, deleteNote NoteStorage) http.HandlerFunc {
 				return
 			} else {
 				log.Error("Failed to get Note", "error", sl.Err(err))
-				render.Status(r, http.StatusBadRequest)
+				render.Status(r, resp.ErrorStatus(err, http.StatusBadRequest))
 				render.JSON(w, r, resp.Error("Failed to get Note"))
 				return
 			}
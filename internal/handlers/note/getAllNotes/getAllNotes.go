@@ -1,45 +1,83 @@
 package getAllNotes
 
 import (
+	"NotesService/internal/api/cursor"
+	"NotesService/internal/api/linkheader"
 	resp "NotesService/internal/api/response"
 	"NotesService/internal/auth"
 	"NotesService/internal/models"
 	"NotesService/internal/storage"
 	sl "NotesService/pkg/logger/logSlog"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 )
 
+const defaultLimit = 10
+
 type NoteStorage interface {
 	storage.NoteStorage
 }
 
+// cursorPayload — содержимое курсора: позиция граничной заметки, порядок сортировки
+// и направление. Курсор привязан к пользователю, которому выдан
+type cursorPayload struct {
+	UserID   int64            `json:"u"`
+	Sort     storage.NoteSort `json:"s"`
+	Desc     bool             `json:"d,omitempty"`
+	Backward bool             `json:"b,omitempty"`
+	Time     time.Time        `json:"t,omitzero"`
+	Title    string           `json:"k,omitempty"`
+	ID       int64            `json:"i"`
+}
+
 // GetAllNotes godoc
 // @Summary Get all notes for a user
-// @Description Returns all notes for a specific user. Requires JWT authentication.
+// @Description Returns a page of notes for a specific user. Pages are selected either by an opaque cursor (next_cursor / prev_cursor from the previous response) or by offset. With a cursor the sort order is taken from the cursor. Requires JWT authentication.
 // @Tags notes
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID" minimum(1)
-// @Param limit query int false "Limit number of notes" default(10)
-// @Param offset query int false "Offset for pagination" default(0)
-// @Param sort query string false "Sort by field (createdAt)"
-// @Success 200 {array} models.NoteResponse "List of notes"
+// @Param limit query int false "Limit number of notes" default(10) maximum(100)
+// @Param offset query int false "Offset for pagination, cannot be combined with cursor" default(0)
+// @Param cursor query string false "Cursor from next_cursor or prev_cursor of the previous page"
+// @Param sort_by query string false "Sort field" Enums(created_at, updated_at, title) default(created_at)
+// @Param sort query string false "Sort order" Enums(asc, desc) default(desc)
+// @Param tags query string false "Comma-separated list of tags to filter by"
+// @Param tag_mode query string false "Tag match mode: or (any tag) or and (all tags)" Enums(or, and) default(or)
+// @Param notebook_id query int false "Notebook ID to filter by" minimum(1)
+// @Param recursive query bool false "Include notes from nested notebooks" default(false)
+// @Param created_from query string false "Created at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created before (RFC 3339 or YYYY-MM-DD)"
+// @Param updated_from query string false "Updated at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param updated_to query string false "Updated before (RFC 3339 or YYYY-MM-DD)"
+// @Param title_prefix query string false "Title starts with (case-insensitive)" maxlength(200)
+// @Param title_contains query string false "Title contains (case-insensitive)" maxlength(200)
+// @Param min_length query int false "Minimum content length in characters" minimum(0)
+// @Param max_length query int false "Maximum content length in characters" minimum(0)
+// @Success 200 {object} models.NoteListResponse "Page of notes"
+// @Header 200 {integer} X-Total-Count "Total number of notes matching the filters"
+// @Header 200 {string} Link "RFC 8288 links to the first, prev, next and last pages"
 // @Failure 400
 // @Failure 401
 // @Failure 500
+// @Failure 503
+// @Failure 504
 // @Security ApiKeyAuth
 // @Router /users/{id}/notes [get]
-func New(log *slog.Logger, getAllNotes NoteStorage) http.HandlerFunc {
+func New(log *slog.Logger, getAllNotes NoteStorage, cursors *cursor.Codec) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handler.url.getAllNotes.New"
 
-		log = log.With(
+		log := sl.WithContext(log, r.Context()).With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
@@ -79,33 +117,313 @@ func New(log *slog.Logger, getAllNotes NoteStorage) http.HandlerFunc {
 			return
 		}
 
-		limit := r.URL.Query().Get("limit")
-		offset := r.URL.Query().Get("offset")
-		sort := r.URL.Query().Get("sort")
+		var filter storage.NoteFilter
+
+		if tags := r.URL.Query().Get("tags"); tags != "" {
+			filter.Tags = storage.NormalizeTags(strings.Split(tags, ","))
+		}
+
+		switch tagMode := r.URL.Query().Get("tag_mode"); tagMode {
+		case "", "or":
+		case "and":
+			filter.MatchAllTags = true
+		default:
+			log.Info("Invalid tag_mode", slog.String("tag_mode", tagMode))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid tag_mode: must be or, and"))
+			return
+		}
+
+		if notebookStr := r.URL.Query().Get("notebook_id"); notebookStr != "" {
+			idNotebook, err := strconv.ParseInt(notebookStr, 10, 64)
+			if err != nil {
+				log.Info("Invalid notebook_id", slog.String("notebook_id", notebookStr))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid notebook_id format: must be integer"))
+				return
+			}
+			filter.NotebookID = &idNotebook
+		}
+
+		if recursiveStr := r.URL.Query().Get("recursive"); recursiveStr != "" {
+			filter.Recursive, err = strconv.ParseBool(recursiveStr)
+			if err != nil {
+				log.Info("Invalid recursive", slog.String("recursive", recursiveStr))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid recursive: must be true or false"))
+				return
+			}
+		}
 
-		notes, err := getAllNotes.GetAllNotes(idUser, limit, offset, sort)
+		if err := parseFilter(r, &filter); err != nil {
+			log.Info("Invalid filter", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		page, cur, err := parsePage(r, cursors, idUser)
 		if err != nil {
-			log.Error("Failed to get all notes", "error", sl.Err(err))
+			log.Info("Invalid pagination parameters", "error", sl.Err(err))
 			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		limit := page.Limit
+		// Лишняя заметка показывает, есть ли ещё страница в направлении выборки
+		page.Limit++
+
+		notes, err := getAllNotes.GetAllNotes(r.Context(), idUser, page, filter)
+		if err != nil {
+			log.Error("Failed to get all notes", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusBadRequest))
+			render.JSON(w, r, resp.Error("Failed to get all notes"))
+			return
+		}
+
+		total, err := getAllNotes.CountNotes(r.Context(), idUser, filter)
+		if err != nil {
+			log.Error("Failed to count notes", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
 			render.JSON(w, r, resp.Error("Failed to get all notes"))
 			return
 		}
 
-		log.Info("Success", slog.Int64("id", idUser))
+		hasMore := len(notes) > limit
+		if hasMore {
+			if page.Backward {
+				notes = notes[len(notes)-limit:]
+			} else {
+				notes = notes[:limit]
+			}
+		}
+
+		hasNext, hasPrev := hasMore, cur != nil || page.Offset > 0
+		if page.Backward {
+			hasNext, hasPrev = true, hasMore
+		}
+
+		response := models.NoteListResponse{
+			Response: resp.OK("Success"),
+			Items:    make([]models.NoteItem, 0, len(notes)),
+			Total:    total,
+			Limit:    limit,
+		}
+		if cur == nil {
+			response.Offset = &page.Offset
+		}
 
 		for _, note := range notes {
-			render.Status(r, http.StatusOK)
-			render.JSON(w, r, models.NoteResponse{
-				Response:  resp.Created("Success"),
-				NoteID:    note.ID,
-				UserId:    note.UserID,
-				Title:     note.Title,
-				Content:   note.Content,
-				CreatedAt: note.CreatedAt,
-				UpdatedAt: note.UpdatedAt,
+			response.Items = append(response.Items, models.NoteItem{
+				NoteID:     note.ID,
+				UserId:     note.UserID,
+				Title:      note.Title,
+				Content:    note.Content,
+				Tags:       note.Tags,
+				NotebookID: note.NotebookID,
+				Version:    note.Version,
+				CreatedAt:  note.CreatedAt,
+				UpdatedAt:  note.UpdatedAt,
 			})
 		}
 
+		if len(notes) > 0 {
+			if hasNext {
+				response.NextCursor, err = encodeCursor(cursors, idUser, page, notes[len(notes)-1], false)
+			}
+			if err == nil && hasPrev {
+				response.PrevCursor, err = encodeCursor(cursors, idUser, page, notes[0], true)
+			}
+			if err != nil {
+				log.Error("Failed to encode cursor", "error", sl.Err(err))
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Failed to get all notes"))
+				return
+			}
+		}
+
+		setLinks(w, r, page, limit, total, hasNext, hasPrev, response)
+
+		log.Info("Success", slog.Int64("id", idUser), slog.Int("count", len(notes)), slog.Int64("total", total))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, response)
+	}
+}
+
+// parseFilter разбирает диапазоны дат, отбор по заголовку и длине содержимого
+// и проверяет их согласованность
+func parseFilter(r *http.Request, filter *storage.NoteFilter) error {
+	query := r.URL.Query()
+
+	dates := []struct {
+		param string
+		dst   **time.Time
+	}{
+		{"created_from", &filter.CreatedFrom},
+		{"created_to", &filter.CreatedTo},
+		{"updated_from", &filter.UpdatedFrom},
+		{"updated_to", &filter.UpdatedTo},
+	}
+	for _, d := range dates {
+		value := query.Get(d.param)
+		if value == "" {
+			continue
+		}
+		t, err := parseTime(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: must be RFC 3339 timestamp or YYYY-MM-DD", d.param)
+		}
+		*d.dst = &t
+	}
+
+	filter.TitlePrefix = strings.TrimSpace(query.Get("title_prefix"))
+	filter.TitleContains = strings.TrimSpace(query.Get("title_contains"))
+
+	lengths := []struct {
+		param string
+		dst   **int
+	}{
+		{"min_length", &filter.MinContentLength},
+		{"max_length", &filter.MaxContentLength},
 	}
+	for _, l := range lengths {
+		value := query.Get(l.param)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: must be integer", l.param)
+		}
+		*l.dst = &n
+	}
+
+	return filter.Validate()
+}
+
+// parseTime принимает метку времени RFC 3339 или дату (полночь UTC)
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// parsePage разбирает параметры пагинации. С cursor порядок сортировки берётся из курсора,
+// без него — из sort_by и sort, а страница выбирается по offset
+func parsePage(r *http.Request, cursors *cursor.Codec, idUser int64) (storage.NotePage, *cursorPayload, error) {
+	query := r.URL.Query()
+
+	page := storage.NotePage{
+		Limit: defaultLimit,
+		Sort:  storage.NoteSortCreatedAt,
+		Desc:  true,
+	}
+
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 {
+		page.Limit = min(l, storage.MaxPageLimit)
+	}
+
+	if sortBy := query.Get("sort_by"); sortBy != "" {
+		page.Sort = storage.NoteSort(sortBy)
+		if !page.Sort.Valid() {
+			return page, nil, errors.New("invalid sort_by: must be created_at, updated_at or title")
+		}
+	}
+
+	// Прежнее поведение: неизвестный порядок сортировки означает desc
+	if query.Get("sort") == "asc" {
+		page.Desc = false
+	}
+
+	token := query.Get("cursor")
+	if token == "" {
+		if of, err := strconv.Atoi(query.Get("offset")); err == nil && of >= 0 {
+			page.Offset = of
+		}
+		return page, nil, nil
+	}
+
+	if query.Has("offset") {
+		return page, nil, errors.New("cursor and offset cannot be used together")
+	}
+
+	var cur cursorPayload
+	if err := cursors.Decode(token, &cur); err != nil || cur.UserID != idUser || !cur.Sort.Valid() {
+		return page, nil, errors.New("invalid cursor")
+	}
+
+	if (query.Has("sort_by") && page.Sort != cur.Sort) || (query.Has("sort") && page.Desc != cur.Desc) {
+		return page, nil, errors.New("cursor was issued for a different sort order")
+	}
+
+	page.Sort, page.Desc, page.Backward = cur.Sort, cur.Desc, cur.Backward
+	page.After = &storage.NoteKeyset{Time: cur.Time, Title: cur.Title, ID: cur.ID}
+
+	return page, &cur, nil
+}
+
+// setLinks выставляет X-Total-Count и Link. В режиме offset соседние страницы задаются
+// смещением, в режиме курсора — курсорами из ответа. first и last всегда задаются смещением
+func setLinks(w http.ResponseWriter, r *http.Request, page storage.NotePage, limit int, total int64, hasNext, hasPrev bool, response models.NoteListResponse) {
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+
+	order := "desc"
+	if !page.Desc {
+		order = "asc"
+	}
+	// Порядок сортировки при переходе по курсору берётся из курсора, поэтому в ссылках он задаётся явно
+	pageURL := func(offset int, token string) string {
+		set := map[string]string{
+			"sort_by": string(page.Sort),
+			"sort":    order,
+			"cursor":  token,
+			"offset":  "",
+		}
+		if offset > 0 {
+			set["offset"] = strconv.Itoa(offset)
+		}
+		return linkheader.PageURL(r, set)
+	}
+
+	links := []linkheader.Link{{Rel: "first", Target: pageURL(0, "")}}
+
+	if response.Offset != nil {
+		if hasPrev {
+			links = append(links, linkheader.Link{Rel: "prev", Target: pageURL(max(page.Offset-limit, 0), "")})
+		}
+		if hasNext {
+			links = append(links, linkheader.Link{Rel: "next", Target: pageURL(page.Offset+limit, "")})
+		}
+	} else {
+		if response.PrevCursor != "" {
+			links = append(links, linkheader.Link{Rel: "prev", Target: pageURL(0, response.PrevCursor)})
+		}
+		if response.NextCursor != "" {
+			links = append(links, linkheader.Link{Rel: "next", Target: pageURL(0, response.NextCursor)})
+		}
+	}
+
+	if total > 0 {
+		last := int((total - 1) / int64(limit) * int64(limit))
+		links = append(links, linkheader.Link{Rel: "last", Target: pageURL(last, "")})
+	}
+
+	linkheader.Set(w, links...)
+}
+
+func encodeCursor(cursors *cursor.Codec, idUser int64, page storage.NotePage, note *models.Note, backward bool) (string, error) {
+	key := storage.KeysetOf(note, page.Sort)
 
+	return cursors.Encode(cursorPayload{
+		UserID:   idUser,
+		Sort:     page.Sort,
+		Desc:     page.Desc,
+		Backward: backward,
+		Time:     key.Time,
+		Title:    key.Title,
+		ID:       key.ID,
+	})
 }
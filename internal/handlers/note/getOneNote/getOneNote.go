@@ -1,6 +1,7 @@
 package getOneNote
 
 import (
+	"NotesService/internal/api/etag"
 	resp "NotesService/internal/api/response"
 	"NotesService/internal/auth"
 	"NotesService/internal/models"
@@ -19,28 +20,34 @@ import (
 
 type NoteStorage interface {
 	storage.NoteStorage
+	storage.ShareStorage
 }
 
 // GetOneNote godoc
 // @Summary Get one note by ID
-// @Description Returns a single note for a specific user. Requires JWT authentication.
+// @Description Returns a single note for a specific user. Notes of other users are returned if the owner has shared them with the caller. Requires JWT authentication.
 // @Tags notes
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID" minimum(1)
 // @Param note_id path int true "Note ID" minimum(1)
+// @Param If-None-Match header string false "ETag of a cached note version"
 // @Success 200 {object} models.NoteResponse "Single note"
+// @Header 200 {string} ETag "Note version"
+// @Success 304 "Note has not changed since the given ETag"
 // @Failure 400
 // @Failure 401
 // @Failure 404
 // @Failure 500
+// @Failure 503
+// @Failure 504
 // @Security ApiKeyAuth
 // @Router /users/{id}/notes/{note_id} [get]
 func New(log *slog.Logger, getOneNote NoteStorage) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handler.url.getOneNote.New"
 
-		log = log.With(
+		log := sl.WithContext(log, r.Context()).With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
@@ -69,17 +76,6 @@ func New(log *slog.Logger, getOneNote NoteStorage) http.HandlerFunc {
 			return
 		}
 
-		if authorizedUserID != idUser {
-			log.Warn("Unauthorized access attempt",
-				slog.Int64("authorized_user_id", authorizedUserID),
-				slog.Int64("requested_user_id", idUser),
-			)
-
-			render.Status(r, http.StatusUnauthorized)
-			render.JSON(w, r, resp.Error("Not found"))
-			return
-		}
-
 		idNoteStr := chi.URLParam(r, "note_id")
 		if idNoteStr == "" {
 			log.Info("Note id is empty")
@@ -95,7 +91,29 @@ func New(log *slog.Logger, getOneNote NoteStorage) http.HandlerFunc {
 			return
 		}
 
-		note, err := getOneNote.GetOneNote(idUser, idNote)
+		// Чужую заметку можно получить только по выданному владельцем доступу
+		if authorizedUserID != idUser {
+			permission, err := getOneNote.GetSharePermission(r.Context(), idNote, authorizedUserID)
+			if err != nil {
+				if errors.Is(err, storageErr.ErrShareNotFound) {
+					log.Warn("Unauthorized access attempt",
+						slog.Int64("authorized_user_id", authorizedUserID),
+						slog.Int64("requested_user_id", idUser),
+					)
+
+					render.Status(r, http.StatusUnauthorized)
+					render.JSON(w, r, resp.Error("Not found"))
+					return
+				}
+				log.Error("Failed to check note access", "error", sl.Err(err))
+				render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+				render.JSON(w, r, resp.Error("Failed to check note access"))
+				return
+			}
+			log.Info("Access to shared note", slog.Int64("authorized_user_id", authorizedUserID), slog.String("permission", permission))
+		}
+
+		note, err := getOneNote.GetOneNote(r.Context(), idUser, idNote)
 		if err != nil {
 			if errors.Is(err, storageErr.ErrNoteNotFound) {
 				log.Error("Note not found", "error", sl.Err(err))
@@ -104,7 +122,7 @@ func New(log *slog.Logger, getOneNote NoteStorage) http.HandlerFunc {
 				return
 			} else {
 				log.Error("Failed to get Note", "error", sl.Err(err))
-				render.Status(r, http.StatusBadRequest)
+				render.Status(r, resp.ErrorStatus(err, http.StatusBadRequest))
 				render.JSON(w, r, resp.Error("Failed to get Note"))
 				return
 			}
@@ -113,15 +131,24 @@ func New(log *slog.Logger, getOneNote NoteStorage) http.HandlerFunc {
 
 		log.Info("Success", slog.Int64("idUser", idUser), slog.Int64("idNote", idNote))
 
+		etag.Set(w, note.Version)
+		if etag.NoneMatch(r, note.Version) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
 		render.Status(r, http.StatusOK)
 		render.JSON(w, r, models.NoteResponse{
-			Response:  resp.OK("Success"),
-			NoteID:    note.ID,
-			UserId:    note.UserID,
-			Title:     note.Title,
-			Content:   note.Content,
-			CreatedAt: note.CreatedAt,
-			UpdatedAt: note.UpdatedAt,
+			Response:   resp.OK("Success"),
+			NoteID:     note.ID,
+			UserId:     note.UserID,
+			Title:      note.Title,
+			Content:    note.Content,
+			Tags:       note.Tags,
+			NotebookID: note.NotebookID,
+			Version:    note.Version,
+			CreatedAt:  note.CreatedAt,
+			UpdatedAt:  note.UpdatedAt,
 		})
 
 	}
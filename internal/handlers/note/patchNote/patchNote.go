@@ -0,0 +1,357 @@
+package patchNote
+
+import (
+	"NotesService/internal/api/etag"
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	sl "NotesService/pkg/logger/logSlog"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	mergePatchType = "application/merge-patch+json"
+	jsonPatchType  = "application/json-patch+json"
+
+	maxBodySize = 1 << 20
+)
+
+type NoteStorage interface {
+	storage.NoteStorage
+	storage.ShareStorage
+}
+
+// PatchNote godoc
+// @Summary Partially update a note by ID
+// @Description Applies a JSON Merge Patch (RFC 7386, application/merge-patch+json) or a JSON Patch (RFC 6902, application/json-patch+json) to the note document (title, content, tags, notebook_id). The resulting document is validated and only changed fields are stored. Notes of other users can be patched if the owner has shared them with the caller with write permission; only the owner can change tags and notebook_id (403). Requires JWT authentication.
+// @Tags notes
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path int true "User ID" minimum(1)
+// @Param note_id path int true "Note ID" minimum(1)
+// @Param If-Match header string false "ETag of the note version being edited"
+// @Param request body object true "Merge patch document or array of JSON Patch operations"
+// @Success 200 {object} models.NoteResponse "Updated note"
+// @Header 200 {string} ETag "Note version"
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Failure 412
+// @Failure 415
+// @Failure 422
+// @Failure 428
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/notes/{note_id} [patch]
+func New(log *slog.Logger, patchNote NoteStorage, requireIfMatch bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.patchNote.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || (mediaType != mergePatchType && mediaType != jsonPatchType) {
+			log.Info("Unsupported content type", slog.String("content_type", r.Header.Get("Content-Type")))
+			w.Header().Set("Accept-Patch", mergePatchType+", "+jsonPatchType)
+			render.Status(r, http.StatusUnsupportedMediaType)
+			render.JSON(w, r, resp.Error("Content-Type must be "+mergePatchType+" or "+jsonPatchType))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		idNote, err := strconv.ParseInt(chi.URLParam(r, "note_id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert note id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid note id format: must be integer"))
+			return
+		}
+
+		// Чужую заметку можно получить только по выданному владельцем доступу
+		if authorizedUserID != idUser {
+			permission, err := patchNote.GetSharePermission(r.Context(), idNote, authorizedUserID)
+			if err != nil {
+				if errors.Is(err, storageErr.ErrShareNotFound) {
+					log.Warn("Unauthorized access attempt",
+						slog.Int64("authorized_user_id", authorizedUserID),
+						slog.Int64("requested_user_id", idUser),
+					)
+
+					render.Status(r, http.StatusUnauthorized)
+					render.JSON(w, r, resp.Error("Not found"))
+					return
+				}
+				log.Error("Failed to check note access", "error", sl.Err(err))
+				render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+				render.JSON(w, r, resp.Error("Failed to check note access"))
+				return
+			}
+
+			if permission != models.PermissionWrite {
+				log.Warn("Write attempt with read-only access", slog.Int64("authorized_user_id", authorizedUserID))
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error("Read-only access"))
+				return
+			}
+
+			log.Info("Access to shared note", slog.Int64("authorized_user_id", authorizedUserID), slog.String("permission", permission))
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			log.Info("Failed to read request body", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Failed to read request body"))
+			return
+		}
+		if len(bytes.TrimSpace(body)) == 0 {
+			log.Info("Request body is empty")
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Request body cannot be empty"))
+			return
+		}
+
+		note, err := patchNote.GetOneNote(r.Context(), idUser, idNote)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrNoteNotFound) {
+				log.Info("Note not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Note not found"))
+				return
+			}
+			log.Error("Failed to get note", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to get note"))
+			return
+		}
+
+		// Условный запрос: If-Match защищает от перезаписи изменений, сделанных с другого устройства
+		version, hasIfMatch, err := etag.IfMatch(r, func() (int64, error) { return note.Version, nil })
+		if err != nil {
+			log.Info("If-Match does not match", slog.String("if_match", r.Header.Get("If-Match")))
+			render.Status(r, http.StatusPreconditionFailed)
+			render.JSON(w, r, resp.Error("Note has been modified"))
+			return
+		}
+		if !hasIfMatch && requireIfMatch {
+			log.Info("If-Match header is missing")
+			render.Status(r, http.StatusPreconditionRequired)
+			render.JSON(w, r, resp.Error("If-Match header is required"))
+			return
+		}
+
+		if version != 0 && version != note.Version {
+			log.Info("Note version mismatch", slog.Int64("if_match", version), slog.Int64("version", note.Version))
+			render.Status(r, http.StatusPreconditionFailed)
+			render.JSON(w, r, resp.Error("Note has been modified"))
+			return
+		}
+
+		doc, err := json.Marshal(storage.NoteDocument(note))
+		if err != nil {
+			log.Error("Failed to encode note document", "error", sl.Err(err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Failed to patch note"))
+			return
+		}
+
+		var patched []byte
+		if mediaType == mergePatchType {
+			patched, err = jsonpatch.MergePatch(doc, body)
+			if err != nil {
+				log.Info("Invalid merge patch", "error", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid merge patch document"))
+				return
+			}
+		} else {
+			patch, err := jsonpatch.DecodePatch(body)
+			if err != nil {
+				log.Info("Invalid JSON patch", "error", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid JSON patch document"))
+				return
+			}
+
+			patched, err = patch.Apply(doc)
+			if err != nil {
+				if errors.Is(err, jsonpatch.ErrBadJSONPatch) {
+					log.Info("Invalid JSON patch", "error", sl.Err(err))
+					render.Status(r, http.StatusBadRequest)
+					render.JSON(w, r, resp.Error("Invalid JSON patch document"))
+					return
+				}
+				// Неудачный test или путь, которого нет в документе — RFC 5789 предлагает 409
+				log.Info("Failed to apply JSON patch", "error", sl.Err(err))
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.Error("Failed to apply patch: "+err.Error()))
+				return
+			}
+		}
+
+		changes, err := diffDocument(note, patched)
+		if err != nil {
+			log.Info("Patched document is invalid", "error", sl.Err(err))
+			render.Status(r, http.StatusUnprocessableEntity)
+			render.JSON(w, r, resp.Error("Patched note is invalid: "+err.Error()))
+			return
+		}
+
+		// Пользователь с доступом на запись меняет только содержимое заметки, но не её теги и блокнот
+		if authorizedUserID != idUser {
+			if fields := changes.OwnerOnly(); len(fields) > 0 {
+				log.Warn("Owner-only fields changed with shared access",
+					slog.Int64("authorized_user_id", authorizedUserID),
+					slog.Any("fields", fields),
+				)
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error("Only the owner can change fields: "+strings.Join(fields, ", ")))
+				return
+			}
+		}
+
+		if changes.Empty() {
+			log.Info("Patch does not change the note", slog.Int64("idUser", idUser), slog.Int64("idNote", idNote))
+
+			etag.Set(w, note.Version)
+			render.Status(r, http.StatusOK)
+			render.JSON(w, r, noteResponse(note))
+			return
+		}
+
+		// Патч применён к прочитанной версии, поэтому сохраняем его только поверх неё
+		note, err = patchNote.PatchNote(r.Context(), idUser, idNote, changes, note.Version)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrVersionMismatch) {
+				if hasIfMatch {
+					log.Info("Note version mismatch", slog.Int64("if_match", version))
+					render.Status(r, http.StatusPreconditionFailed)
+					render.JSON(w, r, resp.Error("Note has been modified"))
+					return
+				}
+				log.Info("Note modified concurrently")
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.Error("Note has been modified concurrently, retry the request"))
+				return
+			}
+			if errors.Is(err, storageErr.ErrNoteNotFound) {
+				log.Info("Note not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Note not found"))
+				return
+			}
+			if errors.Is(err, storageErr.ErrNotebookNotFound) {
+				log.Info("Notebook not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Notebook not found"))
+				return
+			}
+			log.Error("Failed to patch note", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to patch note"))
+			return
+		}
+
+		log.Info("Success", slog.Int64("idUser", idUser), slog.Int64("idNote", idNote))
+
+		etag.Set(w, note.Version)
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, noteResponse(note))
+	}
+}
+
+// diffDocument разбирает изменённый документ по storage.NoteFields, проверяет
+// каждое поле и оставляет в изменениях только отличающиеся от заметки
+func diffDocument(note *models.Note, patched []byte) (storage.NoteChanges, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(patched, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, errors.New("document must be an object")
+	}
+
+	for name := range doc {
+		if _, ok := storage.LookupNoteField(name); !ok {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+	}
+
+	validate := validator.New()
+	changes := storage.NoteChanges{}
+	for _, f := range storage.NoteFields {
+		value, err := f.Decode(doc[f.Name])
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+
+		if err := validate.Var(value, f.Validate); err != nil {
+			var validateErr validator.ValidationErrors
+			if errors.As(err, &validateErr) && validateErr[0].ActualTag() == "required" {
+				return nil, fmt.Errorf("field %s is a required field", f.Name)
+			}
+			return nil, fmt.Errorf("field %s is not valid", f.Name)
+		}
+
+		if !f.Equal(value, f.Get(note)) {
+			changes[f.Name] = value
+		}
+	}
+
+	return changes, nil
+}
+
+func noteResponse(note *models.Note) models.NoteResponse {
+	return models.NoteResponse{
+		Response:   resp.OK("Success"),
+		NoteID:     note.ID,
+		UserId:     note.UserID,
+		Title:      note.Title,
+		Content:    note.Content,
+		Tags:       note.Tags,
+		NotebookID: note.NotebookID,
+		Version:    note.Version,
+		CreatedAt:  note.CreatedAt,
+		UpdatedAt:  note.UpdatedAt,
+	}
+}
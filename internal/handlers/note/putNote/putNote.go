@@ -1,6 +1,7 @@
 package putNote
 
 import (
+	"NotesService/internal/api/etag"
 	resp "NotesService/internal/api/response"
 	"NotesService/internal/auth"
 	"NotesService/internal/models"
@@ -22,29 +23,37 @@ import (
 
 type NoteStorage interface {
 	storage.NoteStorage
+	storage.ShareStorage
 }
 
 // PutNote godoc
 // @Summary Update a note by ID
-// @Description Updates the title and/or content of a note for a specific user. Requires JWT authentication.
+// @Description Updates the title and/or content of a note for a specific user. Notes of other users can be updated if the owner has shared them with the caller with write permission; only the owner can pass tags and notebook_id (403). Requires JWT authentication.
 // @Tags notes
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID" minimum(1)
 // @Param note_id path int true "Note ID" minimum(1)
+// @Param If-Match header string false "ETag of the note version being edited"
 // @Param request body models.PutNoteRequest true "Note update payload"
 // @Success 200 {object} models.NoteResponse "Updated note"
+// @Header 200 {string} ETag "Note version"
 // @Failure 400
 // @Failure 401
+// @Failure 403
 // @Failure 404
+// @Failure 412
+// @Failure 428
 // @Failure 500
+// @Failure 503
+// @Failure 504
 // @Security ApiKeyAuth
 // @Router /users/{id}/notes/{note_id} [put]
-func New(log *slog.Logger, putNote NoteStorage) http.HandlerFunc {
+func New(log *slog.Logger, putNote NoteStorage, requireIfMatch bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handler.url.putNote.New"
 
-		log = log.With(
+		log := sl.WithContext(log, r.Context()).With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
@@ -112,17 +121,6 @@ func New(log *slog.Logger, putNote NoteStorage) http.HandlerFunc {
 			return
 		}
 
-		if authorizedUserID != idUser {
-			log.Warn("Unauthorized access attempt",
-				slog.Int64("authorized_user_id", authorizedUserID),
-				slog.Int64("requested_user_id", idUser),
-			)
-
-			render.Status(r, http.StatusUnauthorized)
-			render.JSON(w, r, resp.Error("Not found"))
-			return
-		}
-
 		idNoteStr := chi.URLParam(r, "note_id")
 		if idNoteStr == "" {
 			log.Info("Note id is empty")
@@ -138,6 +136,44 @@ func New(log *slog.Logger, putNote NoteStorage) http.HandlerFunc {
 			return
 		}
 
+		// Чужую заметку можно получить только по выданному владельцем доступу
+		if authorizedUserID != idUser {
+			permission, err := putNote.GetSharePermission(r.Context(), idNote, authorizedUserID)
+			if err != nil {
+				if errors.Is(err, storageErr.ErrShareNotFound) {
+					log.Warn("Unauthorized access attempt",
+						slog.Int64("authorized_user_id", authorizedUserID),
+						slog.Int64("requested_user_id", idUser),
+					)
+
+					render.Status(r, http.StatusUnauthorized)
+					render.JSON(w, r, resp.Error("Not found"))
+					return
+				}
+				log.Error("Failed to check note access", "error", sl.Err(err))
+				render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+				render.JSON(w, r, resp.Error("Failed to check note access"))
+				return
+			}
+
+			if permission != models.PermissionWrite {
+				log.Warn("Write attempt with read-only access", slog.Int64("authorized_user_id", authorizedUserID))
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error("Read-only access"))
+				return
+			}
+
+			// Пользователь с доступом на запись меняет только содержимое заметки, но не её теги и блокнот
+			if req.Tags != nil || req.NotebookID != nil {
+				log.Warn("Owner-only fields changed with shared access", slog.Int64("authorized_user_id", authorizedUserID))
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error("Only the owner can change tags and notebook_id"))
+				return
+			}
+
+			log.Info("Access to shared note", slog.Int64("authorized_user_id", authorizedUserID), slog.String("permission", permission))
+		}
+
 		if req.TitleNote == "" && req.ContentNote == "" || req.TitleNote == " " && req.ContentNote == " " {
 			log.Error("The fields cannot be empty.")
 			render.Status(r, http.StatusBadRequest)
@@ -161,31 +197,78 @@ func New(log *slog.Logger, putNote NoteStorage) http.HandlerFunc {
 		Title := strings.TrimSpace(req.TitleNote)
 		Content := strings.TrimSpace(req.ContentNote)
 
-		note, err := putNote.PutNote(idUser, idNote, Title, Content)
+		// Условный запрос: If-Match защищает от перезаписи изменений, сделанных с другого устройства
+		version, hasIfMatch, err := etag.IfMatch(r, func() (int64, error) {
+			note, err := putNote.GetOneNote(r.Context(), idUser, idNote)
+			if err != nil {
+				return 0, err
+			}
+			return note.Version, nil
+		})
 		if err != nil {
+			switch {
+			case errors.Is(err, etag.ErrMismatch):
+				log.Info("If-Match does not match", slog.String("if_match", r.Header.Get("If-Match")))
+				render.Status(r, http.StatusPreconditionFailed)
+				render.JSON(w, r, resp.Error("Note has been modified"))
+			case errors.Is(err, storageErr.ErrNoteNotFound):
+				log.Info("Note not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Note not found"))
+			default:
+				log.Error("Failed to get note", "error", sl.Err(err))
+				render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+				render.JSON(w, r, resp.Error("Failed to get note"))
+			}
+			return
+		}
+		if !hasIfMatch && requireIfMatch {
+			log.Info("If-Match header is missing")
+			render.Status(r, http.StatusPreconditionRequired)
+			render.JSON(w, r, resp.Error("If-Match header is required"))
+			return
+		}
+
+		note, err := putNote.PutNote(r.Context(), idUser, idNote, Title, Content, storage.NormalizeTags(req.Tags), req.NotebookID, version)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrVersionMismatch) {
+				log.Info("Note version mismatch", slog.Int64("if_match", version))
+				render.Status(r, http.StatusPreconditionFailed)
+				render.JSON(w, r, resp.Error("Note has been modified"))
+				return
+			}
 			if errors.Is(err, storageErr.ErrNoteNotFound) {
 				log.Error("Note not found", "error", sl.Err(err))
 				render.Status(r, http.StatusNotFound)
 				render.JSON(w, r, resp.Error("Note not found"))
 				return
+			} else if errors.Is(err, storageErr.ErrNotebookNotFound) {
+				log.Info("Notebook not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Notebook not found"))
+				return
 			} else {
 				log.Error("Failed to put note", "error", sl.Err(err))
-				render.Status(r, http.StatusBadRequest)
+				render.Status(r, resp.ErrorStatus(err, http.StatusBadRequest))
 				render.JSON(w, r, resp.Error("Failed to put note"))
 				return
 			}
 		}
 		log.Info("Success", slog.Int64("idUser", idUser), slog.Int64("idNote", idNote))
 
+		etag.Set(w, note.Version)
 		render.Status(r, http.StatusOK)
 		render.JSON(w, r, models.NoteResponse{
-			Response:  resp.OK("Success"),
-			NoteID:    note.ID,
-			UserId:    note.UserID,
-			Title:     note.Title,
-			Content:   note.Content,
-			CreatedAt: note.CreatedAt,
-			UpdatedAt: note.UpdatedAt,
+			Response:   resp.OK("Success"),
+			NoteID:     note.ID,
+			UserId:     note.UserID,
+			Title:      note.Title,
+			Content:    note.Content,
+			Tags:       note.Tags,
+			NotebookID: note.NotebookID,
+			Version:    note.Version,
+			CreatedAt:  note.CreatedAt,
+			UpdatedAt:  note.UpdatedAt,
 		})
 
 	}
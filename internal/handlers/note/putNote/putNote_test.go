@@ -0,0 +1,77 @@
+package putNote
+
+import (
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage/memory"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestSharedWriteAccess(t *testing.T) {
+	s := memory.New()
+	owner, err := s.RegisterUser(t.Context(), "owner", "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer, err := s.RegisterUser(t.Context(), "writer", "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nb, err := s.CreateNotebook(t.Context(), owner.ID, "nb", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, _, err := s.SaveNotes(t.Context(), "title", "content", owner.ID, []string{"a"}, &nb.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ShareNote(t.Context(), owner.ID, note.ID, writer.ID, models.PermissionWrite); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		userID int64
+		body   string
+		want   int
+	}{
+		{name: "writer changes text", userID: writer.ID, body: `{"title":"by writer","content":"by writer"}`, want: http.StatusOK},
+		{name: "writer changes tags", userID: writer.ID, body: `{"title":"t","content":"c","tags":[]}`, want: http.StatusForbidden},
+		{name: "writer moves note", userID: writer.ID, body: `{"title":"t","content":"c","notebook_id":0}`, want: http.StatusForbidden},
+	}
+
+	router := chi.NewRouter()
+	router.Put("/users/{id}/notes/{note_id}", New(slog.New(slog.DiscardHandler), s, false))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := "/users/" + strconv.FormatInt(owner.ID, 10) + "/notes/" + strconv.FormatInt(note.ID, 10)
+			req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, tt.userID))
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("got status %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+
+	got, err := s.GetOneNote(t.Context(), owner.ID, note.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "by writer" || got.NotebookID == nil || *got.NotebookID != nb.ID || len(got.Tags) != 1 || got.Tags[0] != "a" {
+		t.Errorf("note after updates: %+v", got)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"NotesService/internal/auth"
 	"NotesService/internal/models"
 	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
 	sl "NotesService/pkg/logger/logSlog"
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
@@ -33,14 +35,17 @@ type NoteStorage interface {
 // @Success 201 {object} models.NoteResponse "Created note"
 // @Failure 400
 // @Failure 401
+// @Failure 404
 // @Failure 500
+// @Failure 503
+// @Failure 504
 // @Security ApiKeyAuth
 // @Router /users/{id}/notes [post]
 func New(log *slog.Logger, saveNotes NoteStorage) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handler.url.saveNotes.New"
 
-		log = log.With(
+		log := sl.WithContext(log, r.Context()).With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
@@ -143,11 +148,17 @@ func New(log *slog.Logger, saveNotes NoteStorage) http.HandlerFunc {
 		Title := strings.TrimSpace(req.TitleNote)
 		Content := strings.TrimSpace(req.ContentNote)
 
-		note, _, err := saveNotes.SaveNotes(Title, Content, idUser)
+		note, _, err := saveNotes.SaveNotes(r.Context(), Title, Content, idUser, storage.NormalizeTags(req.Tags), req.NotebookID)
 		if err != nil {
+			if errors.Is(err, storageErr.ErrNotebookNotFound) {
+				log.Info("Notebook not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Notebook not found"))
+				return
+			}
 
 			log.Info("Failed to save notes", "error", sl.Err(err))
-			render.Status(r, http.StatusBadRequest)
+			render.Status(r, resp.ErrorStatus(err, http.StatusBadRequest))
 			render.JSON(w, r, resp.Error("Failed to save notes"))
 			return
 		}
@@ -156,13 +167,16 @@ func New(log *slog.Logger, saveNotes NoteStorage) http.HandlerFunc {
 
 		render.Status(r, http.StatusCreated)
 		render.JSON(w, r, models.NoteResponse{
-			Response:  resp.Created("Success"),
-			NoteID:    note.ID,
-			UserId:    note.UserID,
-			Title:     note.Title,
-			Content:   note.Content,
-			CreatedAt: note.CreatedAt,
-			UpdatedAt: note.UpdatedAt,
+			Response:   resp.Created("Success"),
+			NoteID:     note.ID,
+			UserId:     note.UserID,
+			Title:      note.Title,
+			Content:    note.Content,
+			Tags:       note.Tags,
+			NotebookID: note.NotebookID,
+			Version:    note.Version,
+			CreatedAt:  note.CreatedAt,
+			UpdatedAt:  note.UpdatedAt,
 		})
 	}
 
@@ -0,0 +1,167 @@
+package searchNotes
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	sl "NotesService/pkg/logger/logSlog"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// maxQueryLength ограничивает длину поисковой строки
+const maxQueryLength = 200
+
+const defaultLimit = 10
+
+type NoteStorage interface {
+	storage.NoteStorage
+}
+
+// SearchNotes godoc
+// @Summary Full-text search over notes
+// @Description Searches the user's notes by title and content. Words are combined with AND; use "quoted text" for a phrase, a trailing * for a prefix match (e.g. meet*), -word to exclude a word and OR between words for alternatives. Results are ordered by relevance; title and snippet are HTML-escaped note text in which matches are wrapped in <mark></mark>, so they can be inserted into a page as HTML. Requires JWT authentication.
+// @Tags notes
+// @Produce json
+// @Param id path int true "User ID" minimum(1)
+// @Param q query string true "Search query" maxlength(200)
+// @Param limit query int false "Limit number of results" default(10) minimum(1) maximum(100)
+// @Param offset query int false "Offset for pagination" default(0) minimum(0)
+// @Success 200 {object} models.SearchResponse
+// @Failure 400
+// @Failure 401
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/notes/search [get]
+func New(log *slog.Logger, searchNotes NoteStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.searchNotes.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		if authorizedUserID != idUser {
+			log.Warn("Unauthorized access attempt",
+				slog.Int64("authorized_user_id", authorizedUserID),
+				slog.Int64("requested_user_id", idUser),
+			)
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Not found"))
+			return
+		}
+
+		query := strings.TrimSpace(r.URL.Query().Get("q"))
+		if query == "" {
+			log.Info("Search query is empty")
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Search query cannot be empty"))
+			return
+		}
+		if utf8.RuneCountInString(query) > maxQueryLength {
+			log.Info("Search query is too long")
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Search query is too long"))
+			return
+		}
+
+		limit, offset, err := parsePage(r)
+		if err != nil {
+			log.Info("Invalid pagination parameters", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		results, err := searchNotes.SearchNotes(r.Context(), idUser, query, limit, offset)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrEmptySearchQuery) {
+				log.Info("Search query has no words", slog.String("q", query))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Search query must contain at least one word"))
+				return
+			}
+			log.Error("Failed to search notes", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to search notes"))
+			return
+		}
+
+		items := make([]models.SearchResultItem, 0, len(results))
+		for _, result := range results {
+			items = append(items, models.SearchResultItem{
+				NoteID:     result.ID,
+				Title:      storage.Highlight(result.TitleHighlight),
+				Snippet:    storage.Highlight(result.Snippet),
+				Rank:       result.Rank,
+				Tags:       result.Tags,
+				NotebookID: result.NotebookID,
+				CreatedAt:  result.CreatedAt,
+				UpdatedAt:  result.UpdatedAt,
+			})
+		}
+
+		log.Info("Success", slog.Int64("id", idUser), slog.Int("count", len(items)))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, models.SearchResponse{
+			Response: resp.OK("Success"),
+			Query:    query,
+			Results:  items,
+		})
+	}
+}
+
+// parsePage разбирает limit и offset. limit больше storage.MaxPageLimit уменьшается до него,
+// как в списке заметок
+func parsePage(r *http.Request) (limit, offset int, err error) {
+	query := r.URL.Query()
+
+	limit = defaultLimit
+	if query.Has("limit") {
+		limit, err = strconv.Atoi(query.Get("limit"))
+		if err != nil || limit <= 0 {
+			return 0, 0, errors.New("invalid limit: must be a positive integer")
+		}
+		limit = min(limit, storage.MaxPageLimit)
+	}
+
+	if query.Has("offset") {
+		offset, err = strconv.Atoi(query.Get("offset"))
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("invalid offset: must be a non-negative integer")
+		}
+	}
+
+	return limit, offset, nil
+}
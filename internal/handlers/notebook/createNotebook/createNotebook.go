@@ -0,0 +1,149 @@
+package createNotebook
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	sl "NotesService/pkg/logger/logSlog"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type NotebookStorage interface {
+	storage.NotebookStorage
+}
+
+// CreateNotebook godoc
+// @Summary Create a notebook
+// @Description Creates a notebook for a specific user. Pass parent_id to nest it inside another notebook of the same user. Requires JWT authentication.
+// @Tags notebooks
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID" minimum(1)
+// @Param request body models.NotebookRequest true "Notebook payload"
+// @Success 201 {object} models.NotebookResponse "Created notebook"
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/notebooks [post]
+func New(log *slog.Logger, createNotebook NotebookStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.createNotebook.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		var req models.NotebookRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			if err == io.EOF {
+				log.Info("Request body is empty (EOF)")
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Request body cannot be empty"))
+				return
+			}
+
+			if strings.Contains(err.Error(), "invalid character") {
+				log.Info("Invalid JSON format", slog.String("error", err.Error()))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid JSON format"))
+				return
+			}
+
+			log.Error("Failed to decode request body", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Failed to decode request body"))
+			return
+		}
+
+		log.Info("Request body decoded", slog.Any("request", req))
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+			log.Error("Failed to validate request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+			return
+		}
+
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			log.Info("The field name cannot be empty.")
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("The field name cannot be empty."))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		if authorizedUserID != idUser {
+			log.Warn("Unauthorized access attempt",
+				slog.Int64("authorized_user_id", authorizedUserID),
+				slog.Int64("requested_user_id", idUser),
+			)
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Not found"))
+			return
+		}
+
+		notebook, err := createNotebook.CreateNotebook(r.Context(), idUser, name, req.ParentID)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrNotebookNotFound) {
+				log.Info("Parent notebook not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Parent notebook not found"))
+				return
+			}
+			log.Error("Failed to create notebook", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to create notebook"))
+			return
+		}
+
+		log.Info("Success", slog.Int64("idUser", idUser), slog.Int64("idNotebook", notebook.ID))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, models.NotebookResponse{
+			Response: resp.Created("Success"),
+			NotebookItem: models.NotebookItem{
+				ID:        notebook.ID,
+				ParentID:  notebook.ParentID,
+				Name:      notebook.Name,
+				CreatedAt: notebook.CreatedAt,
+				UpdatedAt: notebook.UpdatedAt,
+			},
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package getNotebook
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	sl "NotesService/pkg/logger/logSlog"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type NotebookStorage interface {
+	storage.NotebookStorage
+}
+
+// GetNotebook godoc
+// @Summary Get a notebook by ID
+// @Description Returns a notebook of a specific user. Requires JWT authentication.
+// @Tags notebooks
+// @Produce json
+// @Param id path int true "User ID" minimum(1)
+// @Param notebook_id path int true "Notebook ID" minimum(1)
+// @Success 200 {object} models.NotebookResponse
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/notebooks/{notebook_id} [get]
+func New(log *slog.Logger, getNotebook NotebookStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.getNotebook.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		if authorizedUserID != idUser {
+			log.Warn("Unauthorized access attempt",
+				slog.Int64("authorized_user_id", authorizedUserID),
+				slog.Int64("requested_user_id", idUser),
+			)
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Not found"))
+			return
+		}
+
+		idNotebook, err := strconv.ParseInt(chi.URLParam(r, "notebook_id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		notebook, err := getNotebook.GetNotebook(r.Context(), idUser, idNotebook)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrNotebookNotFound) {
+				log.Info("Notebook not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Notebook not found"))
+				return
+			}
+			log.Error("Failed to get notebook", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to get notebook"))
+			return
+		}
+
+		log.Info("Success", slog.Int64("idUser", idUser), slog.Int64("idNotebook", idNotebook))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, models.NotebookResponse{
+			Response: resp.OK("Success"),
+			NotebookItem: models.NotebookItem{
+				ID:        notebook.ID,
+				ParentID:  notebook.ParentID,
+				Name:      notebook.Name,
+				CreatedAt: notebook.CreatedAt,
+				UpdatedAt: notebook.UpdatedAt,
+			},
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package getNotebooks
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	sl "NotesService/pkg/logger/logSlog"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type NotebookStorage interface {
+	storage.NotebookStorage
+}
+
+// GetNotebooks godoc
+// @Summary List notebooks
+// @Description Returns all notebooks of the user as a flat list. The hierarchy is described by parent_id (null for top-level notebooks). Requires JWT authentication.
+// @Tags notebooks
+// @Produce json
+// @Param id path int true "User ID" minimum(1)
+// @Success 200 {object} models.NotebookListResponse
+// @Failure 400
+// @Failure 401
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/notebooks [get]
+func New(log *slog.Logger, getNotebooks NotebookStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.getNotebooks.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		if authorizedUserID != idUser {
+			log.Warn("Unauthorized access attempt",
+				slog.Int64("authorized_user_id", authorizedUserID),
+				slog.Int64("requested_user_id", idUser),
+			)
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Not found"))
+			return
+		}
+
+		notebooks, err := getNotebooks.GetNotebooks(r.Context(), idUser)
+		if err != nil {
+			log.Error("Failed to get notebooks", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to get notebooks"))
+			return
+		}
+
+		items := make([]models.NotebookItem, 0, len(notebooks))
+		for _, notebook := range notebooks {
+			items = append(items, models.NotebookItem{
+				ID:        notebook.ID,
+				ParentID:  notebook.ParentID,
+				Name:      notebook.Name,
+				CreatedAt: notebook.CreatedAt,
+				UpdatedAt: notebook.UpdatedAt,
+			})
+		}
+
+		log.Info("Success", slog.Int64("id", idUser), slog.Int("count", len(items)))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, models.NotebookListResponse{
+			Response:  resp.OK("Success"),
+			Notebooks: items,
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package diffNoteRevisions
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	"NotesService/pkg/diff"
+	sl "NotesService/pkg/logger/logSlog"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type RevisionStorage interface {
+	storage.RevisionStorage
+	storage.NoteStorage
+}
+
+// DiffNoteRevisions godoc
+// @Summary Diff two note revisions
+// @Description Returns a line-based diff of the title and content between two saved versions of a note. Without the to parameter the from version is compared with the current note. Requires JWT authentication.
+// @Tags revisions
+// @Produce json
+// @Param id path int true "User ID" minimum(1)
+// @Param note_id path int true "Note ID" minimum(1)
+// @Param from query int true "Base revision number" minimum(1)
+// @Param to query int false "Target revision number (defaults to the current note)" minimum(1)
+// @Success 200 {object} models.RevisionDiffResponse
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/notes/{note_id}/revisions/diff [get]
+func New(log *slog.Logger, diffNoteRevisions RevisionStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.diffNoteRevisions.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		if authorizedUserID != idUser {
+			log.Warn("Unauthorized access attempt",
+				slog.Int64("authorized_user_id", authorizedUserID),
+				slog.Int64("requested_user_id", idUser),
+			)
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Not found"))
+			return
+		}
+
+		idNote, err := strconv.ParseInt(chi.URLParam(r, "note_id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		from, err := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+		if err != nil {
+			log.Info("Invalid from revision", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid from: must be integer"))
+			return
+		}
+
+		var to *int64
+		if toStr := r.URL.Query().Get("to"); toStr != "" {
+			toRevision, err := strconv.ParseInt(toStr, 10, 64)
+			if err != nil {
+				log.Info("Invalid to revision", "error", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid to: must be integer"))
+				return
+			}
+			to = &toRevision
+		}
+
+		base, err := diffNoteRevisions.GetNoteRevision(r.Context(), idUser, idNote, from)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrRevisionNotFound) {
+				log.Info("Revision not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Revision not found"))
+				return
+			}
+			log.Error("Failed to get note revision", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to diff note revisions"))
+			return
+		}
+
+		var title, content string
+		if to != nil {
+			target, err := diffNoteRevisions.GetNoteRevision(r.Context(), idUser, idNote, *to)
+			if err != nil {
+				if errors.Is(err, storageErr.ErrRevisionNotFound) {
+					log.Info("Revision not found", "error", sl.Err(err))
+					render.Status(r, http.StatusNotFound)
+					render.JSON(w, r, resp.Error("Revision not found"))
+					return
+				}
+				log.Error("Failed to get note revision", "error", sl.Err(err))
+				render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+				render.JSON(w, r, resp.Error("Failed to diff note revisions"))
+				return
+			}
+			title, content = target.Title, target.Content
+		} else {
+			note, err := diffNoteRevisions.GetOneNote(r.Context(), idUser, idNote)
+			if err != nil {
+				if errors.Is(err, storageErr.ErrNoteNotFound) {
+					log.Info("Note not found", "error", sl.Err(err))
+					render.Status(r, http.StatusNotFound)
+					render.JSON(w, r, resp.Error("Note not found"))
+					return
+				}
+				log.Error("Failed to get note", "error", sl.Err(err))
+				render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+				render.JSON(w, r, resp.Error("Failed to diff note revisions"))
+				return
+			}
+			title, content = note.Title, note.Content
+		}
+
+		log.Info("Success", slog.Int64("idNote", idNote), slog.Int64("from", from))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, models.RevisionDiffResponse{
+			Response: resp.OK("Success"),
+			NoteID:   idNote,
+			From:     from,
+			To:       to,
+			Title:    diffLines(base.Title, title),
+			Content:  diffLines(base.Content, content),
+		})
+	}
+}
+
+func diffLines(a, b string) []models.DiffLine {
+	lines := diff.Lines(a, b)
+
+	result := make([]models.DiffLine, 0, len(lines))
+	for _, line := range lines {
+		result = append(result, models.DiffLine{
+			Op:   string(line.Op),
+			Text: line.Text,
+		})
+	}
+
+	return result
+}
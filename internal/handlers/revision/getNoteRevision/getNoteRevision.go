@@ -0,0 +1,121 @@
+package getNoteRevision
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	sl "NotesService/pkg/logger/logSlog"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type RevisionStorage interface {
+	storage.RevisionStorage
+}
+
+// GetNoteRevision godoc
+// @Summary Get a note revision
+// @Description Returns the title and content of a saved version of a note. Requires JWT authentication.
+// @Tags revisions
+// @Produce json
+// @Param id path int true "User ID" minimum(1)
+// @Param note_id path int true "Note ID" minimum(1)
+// @Param revision path int true "Revision number" minimum(1)
+// @Success 200 {object} models.RevisionResponse
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/notes/{note_id}/revisions/{revision} [get]
+func New(log *slog.Logger, getNoteRevision RevisionStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.getNoteRevision.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		if authorizedUserID != idUser {
+			log.Warn("Unauthorized access attempt",
+				slog.Int64("authorized_user_id", authorizedUserID),
+				slog.Int64("requested_user_id", idUser),
+			)
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Not found"))
+			return
+		}
+
+		idNote, err := strconv.ParseInt(chi.URLParam(r, "note_id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		revision, err := strconv.ParseInt(chi.URLParam(r, "revision"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert revision to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid revision format: must be integer"))
+			return
+		}
+
+		rev, err := getNoteRevision.GetNoteRevision(r.Context(), idUser, idNote, revision)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrRevisionNotFound) {
+				log.Info("Revision not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Revision not found"))
+				return
+			}
+			log.Error("Failed to get note revision", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to get note revision"))
+			return
+		}
+
+		log.Info("Success", slog.Int64("idNote", idNote), slog.Int64("revision", revision))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, models.RevisionResponse{
+			Response: resp.OK("Success"),
+			NoteID:   rev.NoteID,
+			RevisionItem: models.RevisionItem{
+				Revision:  rev.Revision,
+				Title:     rev.Title,
+				CreatedAt: rev.CreatedAt,
+			},
+			Content: rev.Content,
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package getNoteRevisions
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	sl "NotesService/pkg/logger/logSlog"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type RevisionStorage interface {
+	storage.RevisionStorage
+}
+
+// GetNoteRevisions godoc
+// @Summary List note revisions
+// @Description Returns the saved previous versions of a note, newest first. A version is saved every time the note's title or content changes. Requires JWT authentication.
+// @Tags revisions
+// @Produce json
+// @Param id path int true "User ID" minimum(1)
+// @Param note_id path int true "Note ID" minimum(1)
+// @Success 200 {object} models.RevisionListResponse
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/notes/{note_id}/revisions [get]
+func New(log *slog.Logger, getNoteRevisions RevisionStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.getNoteRevisions.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		if authorizedUserID != idUser {
+			log.Warn("Unauthorized access attempt",
+				slog.Int64("authorized_user_id", authorizedUserID),
+				slog.Int64("requested_user_id", idUser),
+			)
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Not found"))
+			return
+		}
+
+		idNote, err := strconv.ParseInt(chi.URLParam(r, "note_id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		revisions, err := getNoteRevisions.GetNoteRevisions(r.Context(), idUser, idNote)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrNoteNotFound) {
+				log.Info("Note not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Note not found"))
+				return
+			}
+			log.Error("Failed to get note revisions", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to get note revisions"))
+			return
+		}
+
+		items := make([]models.RevisionItem, 0, len(revisions))
+		for _, revision := range revisions {
+			items = append(items, models.RevisionItem{
+				Revision:  revision.Revision,
+				Title:     revision.Title,
+				CreatedAt: revision.CreatedAt,
+			})
+		}
+
+		log.Info("Success", slog.Int64("idNote", idNote), slog.Int("count", len(items)))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, models.RevisionListResponse{
+			Response:  resp.OK("Success"),
+			NoteID:    idNote,
+			Revisions: items,
+		})
+	}
+}
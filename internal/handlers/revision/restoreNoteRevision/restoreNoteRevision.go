@@ -0,0 +1,123 @@
+package restoreNoteRevision
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	sl "NotesService/pkg/logger/logSlog"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type RevisionStorage interface {
+	storage.RevisionStorage
+}
+
+// RestoreNoteRevision godoc
+// @Summary Restore a note revision
+// @Description Replaces the note's title and content with a saved version. The current version is saved to the history first, so a restore can be undone. Requires JWT authentication.
+// @Tags revisions
+// @Produce json
+// @Param id path int true "User ID" minimum(1)
+// @Param note_id path int true "Note ID" minimum(1)
+// @Param revision path int true "Revision number" minimum(1)
+// @Success 200 {object} models.NoteResponse "Restored note"
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/notes/{note_id}/revisions/{revision}/restore [post]
+func New(log *slog.Logger, restoreNoteRevision RevisionStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.restoreNoteRevision.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		if authorizedUserID != idUser {
+			log.Warn("Unauthorized access attempt",
+				slog.Int64("authorized_user_id", authorizedUserID),
+				slog.Int64("requested_user_id", idUser),
+			)
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Not found"))
+			return
+		}
+
+		idNote, err := strconv.ParseInt(chi.URLParam(r, "note_id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		revision, err := strconv.ParseInt(chi.URLParam(r, "revision"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert revision to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid revision format: must be integer"))
+			return
+		}
+
+		note, err := restoreNoteRevision.RestoreNoteRevision(r.Context(), idUser, idNote, revision)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrRevisionNotFound) || errors.Is(err, storageErr.ErrNoteNotFound) {
+				log.Info("Revision not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Revision not found"))
+				return
+			}
+			log.Error("Failed to restore note revision", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to restore note revision"))
+			return
+		}
+
+		log.Info("Success", slog.Int64("idNote", idNote), slog.Int64("revision", revision))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, models.NoteResponse{
+			Response:   resp.OK("Success"),
+			NoteID:     note.ID,
+			UserId:     note.UserID,
+			Title:      note.Title,
+			Content:    note.Content,
+			Tags:       note.Tags,
+			NotebookID: note.NotebookID,
+			Version:    note.Version,
+			CreatedAt:  note.CreatedAt,
+			UpdatedAt:  note.UpdatedAt,
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package shareNote
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	sl "NotesService/pkg/logger/logSlog"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type ShareStorage interface {
+	storage.ShareStorage
+	storage.UserStorage
+}
+
+// ShareNote godoc
+// @Summary Share a note with another user
+// @Description Grants another user read or write access to a note. Sharing the note again with the same user replaces the permission. Only the owner can share a note. Requires JWT authentication.
+// @Tags shares
+// @Accept json
+// @Produce json
+// @Param id path int true "Owner user ID" minimum(1)
+// @Param note_id path int true "Note ID" minimum(1)
+// @Param request body models.ShareRequest true "User and permission"
+// @Success 201 {object} models.ShareResponse "Granted access"
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/notes/{note_id}/shares [post]
+func New(log *slog.Logger, shareNote ShareStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.shareNote.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		var req models.ShareRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			if err == io.EOF {
+				log.Info("Request body is empty (EOF)")
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Request body cannot be empty"))
+				return
+			}
+
+			if strings.Contains(err.Error(), "invalid character") {
+				log.Info("Invalid JSON format", slog.String("error", err.Error()))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid JSON format"))
+				return
+			}
+
+			log.Error("Failed to decode request body", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Failed to decode request body"))
+			return
+		}
+
+		log.Info("Request body decoded", slog.Any("request", req))
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+			log.Error("Failed to validate request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		if authorizedUserID != idUser {
+			log.Warn("Unauthorized access attempt",
+				slog.Int64("authorized_user_id", authorizedUserID),
+				slog.Int64("requested_user_id", idUser),
+			)
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Not found"))
+			return
+		}
+
+		idNote, err := strconv.ParseInt(chi.URLParam(r, "note_id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		grantee, err := shareNote.GetUserByName(r.Context(), strings.TrimSpace(req.Username))
+		if err != nil {
+			if errors.Is(err, storageErr.ErrUserNotFound) {
+				log.Info("User not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("User not found"))
+				return
+			}
+			log.Error("Failed to get user", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to share note"))
+			return
+		}
+
+		if grantee.ID == idUser {
+			log.Info("Attempt to share a note with its owner")
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Cannot share a note with yourself"))
+			return
+		}
+
+		share, err := shareNote.ShareNote(r.Context(), idUser, idNote, grantee.ID, req.Permission)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrNoteNotFound) {
+				log.Error("Note not found", "error", sl.Err(err))
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Note not found"))
+				return
+			}
+			log.Error("Failed to share note", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to share note"))
+			return
+		}
+
+		log.Info("Success", slog.Int64("idNote", idNote), slog.Int64("grantee", grantee.ID), slog.String("permission", share.Permission))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, models.ShareResponse{
+			Response: resp.Created("Success"),
+			NoteID:   share.NoteID,
+			ShareItem: models.ShareItem{
+				UserID:     share.UserID,
+				Username:   grantee.Username,
+				Permission: share.Permission,
+				CreatedAt:  share.CreatedAt,
+			},
+		})
+	}
+}
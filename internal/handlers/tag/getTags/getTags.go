@@ -0,0 +1,96 @@
+package getTags
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	sl "NotesService/pkg/logger/logSlog"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type NoteStorage interface {
+	storage.NoteStorage
+}
+
+// GetTags godoc
+// @Summary List user tags
+// @Description Returns all tags of the user with the number of notes for each tag. Requires JWT authentication.
+// @Tags tags
+// @Produce json
+// @Param id path int true "User ID" minimum(1)
+// @Success 200 {object} models.TagListResponse
+// @Failure 400
+// @Failure 401
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/tags [get]
+func New(log *slog.Logger, getTags NoteStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.getTags.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		if authorizedUserID != idUser {
+			log.Warn("Unauthorized access attempt",
+				slog.Int64("authorized_user_id", authorizedUserID),
+				slog.Int64("requested_user_id", idUser),
+			)
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Not found"))
+			return
+		}
+
+		tags, err := getTags.GetTags(r.Context(), idUser)
+		if err != nil {
+			log.Error("Failed to get tags", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to get tags"))
+			return
+		}
+
+		items := make([]models.TagItem, 0, len(tags))
+		for _, tag := range tags {
+			items = append(items, models.TagItem{
+				Name:      tag.Name,
+				NoteCount: tag.NoteCount,
+			})
+		}
+
+		log.Info("Success", slog.Int64("id", idUser), slog.Int("count", len(items)))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, models.TagListResponse{
+			Response: resp.OK("Success"),
+			Tags:     items,
+		})
+	}
+}
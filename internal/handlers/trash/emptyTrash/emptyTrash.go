@@ -0,0 +1,88 @@
+package emptyTrash
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	sl "NotesService/pkg/logger/logSlog"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type TrashStorage interface {
+	storage.TrashStorage
+}
+
+// EmptyTrash godoc
+// @Summary Empty the trash
+// @Description Permanently deletes all notes in the user's trash. This cannot be undone. Requires JWT authentication.
+// @Tags trash
+// @Produce json
+// @Param id path int true "User ID" minimum(1)
+// @Success 200 {object} models.EmptyTrashResponse
+// @Failure 400
+// @Failure 401
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/trash [delete]
+func New(log *slog.Logger, emptyTrash TrashStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.emptyTrash.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		if authorizedUserID != idUser {
+			log.Warn("Unauthorized access attempt",
+				slog.Int64("authorized_user_id", authorizedUserID),
+				slog.Int64("requested_user_id", idUser),
+			)
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Not found"))
+			return
+		}
+
+		deleted, err := emptyTrash.EmptyTrash(r.Context(), idUser)
+		if err != nil {
+			log.Error("Failed to empty trash", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to empty trash"))
+			return
+		}
+
+		log.Info("Success", slog.Int64("id", idUser), slog.Int64("deleted", deleted))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, models.EmptyTrashResponse{
+			Response: resp.OK("Success"),
+			Deleted:  deleted,
+		})
+	}
+}
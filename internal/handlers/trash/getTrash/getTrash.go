@@ -0,0 +1,104 @@
+package getTrash
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	sl "NotesService/pkg/logger/logSlog"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type TrashStorage interface {
+	storage.TrashStorage
+}
+
+// GetTrash godoc
+// @Summary List notes in the trash
+// @Description Returns deleted notes of the user, most recently deleted first, with the time each note will be permanently removed. Requires JWT authentication.
+// @Tags trash
+// @Produce json
+// @Param id path int true "User ID" minimum(1)
+// @Success 200 {object} models.TrashResponse
+// @Failure 400
+// @Failure 401
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /users/{id}/trash [get]
+func New(log *slog.Logger, getTrash TrashStorage, retention time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.getTrash.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		idUser, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("Failed to convert id to int64", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid id format: must be integer"))
+			return
+		}
+
+		if authorizedUserID != idUser {
+			log.Warn("Unauthorized access attempt",
+				slog.Int64("authorized_user_id", authorizedUserID),
+				slog.Int64("requested_user_id", idUser),
+			)
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Not found"))
+			return
+		}
+
+		notes, err := getTrash.GetTrash(r.Context(), idUser)
+		if err != nil {
+			log.Error("Failed to get trash", "error", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to get trash"))
+			return
+		}
+
+		items := make([]models.TrashItem, 0, len(notes))
+		for _, note := range notes {
+			items = append(items, models.TrashItem{
+				NoteID:     note.ID,
+				Title:      note.Title,
+				Content:    note.Content,
+				Tags:       note.Tags,
+				NotebookID: note.NotebookID,
+				CreatedAt:  note.CreatedAt,
+				UpdatedAt:  note.UpdatedAt,
+				DeletedAt:  *note.DeletedAt,
+				PurgeAt:    note.DeletedAt.Add(retention),
+			})
+		}
+
+		log.Info("Success", slog.Int64("id", idUser), slog.Int("count", len(items)))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, models.TrashResponse{
+			Response: resp.OK("Success"),
+			Notes:    items,
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package loginUser
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	sl "NotesService/pkg/logger/logSlog"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type JWTManager interface {
+	GenerateToken(ctx context.Context, userID int64, username string) (string, error)
+	GenerateRefreshToken() (*auth.RefreshToken, error)
+}
+
+type UserStorage interface {
+	storage.UserStorage
+	storage.TokenStorage
+}
+
+// LoginUser godoc
+// @Summary Log in
+// @Description Checks user credentials and returns user info with new JWT access and refresh tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body models.LoginRequest true "User credentials"
+// @Success 200 {object} models.UserResponse
+// @Failure 400
+// @Failure 401
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Router /auth/login [post]
+func New(log *slog.Logger, userStorage UserStorage, jwtManager JWTManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.loginUser.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		//1.Read body request
+		var req models.LoginRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			if err == io.EOF {
+				log.Info("Request body is empty (EOF)")
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Request body cannot be empty"))
+				return
+			}
+
+			if strings.Contains(err.Error(), "invalid character") {
+				log.Info("Invalid JSON format", slog.String("error", err.Error()))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid JSON format"))
+				return
+			}
+
+			if strings.Contains(err.Error(), "syntax error") {
+				log.Info("JSON syntax error", slog.String("error", err.Error()))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("JSON syntax error"))
+				return
+			}
+
+			log.Error("Failed to decode request body", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Failed to decode request body"))
+			return
+		}
+
+		log.Info("Request body decoded", slog.String("user_name", req.Username))
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+			log.Error("Failed to validate request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+			return
+		}
+
+		UserName := strings.TrimSpace(req.Username)
+
+		user, err := userStorage.GetUserByName(r.Context(), UserName)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrUserNotFound) {
+				// Сравниваем с фиктивным хешем, чтобы не выдавать существование логина по времени ответа
+				auth.CheckPasswordDummy(req.Password)
+				log.Info("User not found", slog.String("user_name", UserName))
+				render.Status(r, http.StatusUnauthorized)
+				render.JSON(w, r, resp.Error("Invalid user name or password"))
+				return
+			}
+			log.Error("Failed to get user", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to log in"))
+			return
+		}
+
+		if !auth.CheckPassword(user.PasswordHash, req.Password) {
+			log.Info("Invalid password", slog.Int64("id", user.ID))
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Invalid user name or password"))
+			return
+		}
+
+		token, err := jwtManager.GenerateToken(r.Context(), user.ID, user.Username)
+		if err != nil {
+			log.Error("failed to generate token", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to generate token"))
+			return
+		}
+
+		refreshToken, err := jwtManager.GenerateRefreshToken()
+		if err != nil {
+			log.Error("failed to generate refresh token", sl.Err(err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Failed to generate token"))
+			return
+		}
+
+		familyID, err := auth.NewTokenFamily()
+		if err != nil {
+			log.Error("failed to generate token family", sl.Err(err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Failed to generate token"))
+			return
+		}
+
+		err = userStorage.SaveRefreshToken(r.Context(), user.ID, refreshToken.Hash, familyID, refreshToken.ExpiresAt)
+		if err != nil {
+			log.Error("failed to save refresh token", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to generate token"))
+			return
+		}
+
+		log.Info("Success", slog.Int64("id", user.ID))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, models.UserResponse{
+			Response:     resp.OK("Success"),
+			ID:           user.ID,
+			Username:     user.Username,
+			CreatedAt:    user.CreatedAt,
+			Token:        token,
+			RefreshToken: refreshToken.Token,
+		})
+	}
+}
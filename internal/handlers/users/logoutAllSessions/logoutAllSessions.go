@@ -0,0 +1,72 @@
+package logoutAllSessions
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/storage"
+	sl "NotesService/pkg/logger/logSlog"
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type JWTManager interface {
+	RevokeAllTokens(ctx context.Context, userID int64) error
+}
+
+type TokenStorage interface {
+	storage.TokenStorage
+}
+
+// LogoutAllSessions godoc
+// @Summary Log out from all sessions
+// @Description Revokes every access and refresh token issued to the user, on all devices. Requires JWT authentication.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} resp.Response
+// @Failure 401
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /auth/logout/all [post]
+func New(log *slog.Logger, tokenStorage TokenStorage, jwtManager JWTManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.logoutAllSessions.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		authorizedUserID, ok := auth.GetUserID(r)
+		if !ok {
+			log.Error("user_id not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		if err := tokenStorage.RevokeUserRefreshTokens(r.Context(), authorizedUserID); err != nil {
+			log.Error("Failed to revoke refresh tokens", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to log out"))
+			return
+		}
+
+		if err := jwtManager.RevokeAllTokens(r.Context(), authorizedUserID); err != nil {
+			log.Error("Failed to revoke tokens", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to log out"))
+			return
+		}
+
+		log.Info("Success", slog.Int64("id", authorizedUserID))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK("Logged out from all sessions"))
+	}
+}
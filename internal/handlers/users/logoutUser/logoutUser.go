@@ -0,0 +1,100 @@
+package logoutUser
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	sl "NotesService/pkg/logger/logSlog"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type JWTManager interface {
+	RevokeToken(ctx context.Context, claims *auth.Claims) error
+}
+
+type TokenStorage interface {
+	storage.TokenStorage
+}
+
+// LogoutUser godoc
+// @Summary Log out
+// @Description Revokes the access token used for this request. If a refresh token is passed, every refresh token issued from the same login is revoked too. Requires JWT authentication.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body models.LogoutRequest false "Refresh token to revoke"
+// @Success 200 {object} resp.Response
+// @Failure 400
+// @Failure 401
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Security ApiKeyAuth
+// @Router /auth/logout [post]
+func New(log *slog.Logger, tokenStorage TokenStorage, jwtManager JWTManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.logoutUser.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		claims, ok := auth.GetClaims(r)
+		if !ok {
+			log.Error("claims not found in context")
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("Unauthorized"))
+			return
+		}
+
+		// Тело запроса необязательно
+		var req models.LogoutRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil && err != io.EOF {
+			if strings.Contains(err.Error(), "invalid character") {
+				log.Info("Invalid JSON format", slog.String("error", err.Error()))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid JSON format"))
+				return
+			}
+
+			log.Error("Failed to decode request body", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Failed to decode request body"))
+			return
+		}
+
+		if req.RefreshToken != "" {
+			err = tokenStorage.RevokeRefreshTokenFamily(r.Context(), claims.UserID, auth.HashToken(req.RefreshToken))
+			if err != nil && !errors.Is(err, storageErr.ErrRefreshTokenNotFound) {
+				log.Error("Failed to revoke refresh token", sl.Err(err))
+				render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+				render.JSON(w, r, resp.Error("Failed to log out"))
+				return
+			}
+		}
+
+		if err := jwtManager.RevokeToken(r.Context(), claims); err != nil {
+			log.Error("Failed to revoke token", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to log out"))
+			return
+		}
+
+		log.Info("Success", slog.Int64("id", claims.UserID))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK("Logged out"))
+	}
+}
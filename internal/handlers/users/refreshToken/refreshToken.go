@@ -0,0 +1,155 @@
+package refreshToken
+
+import (
+	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	sl "NotesService/pkg/logger/logSlog"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type JWTManager interface {
+	GenerateToken(ctx context.Context, userID int64, username string) (string, error)
+	GenerateRefreshToken() (*auth.RefreshToken, error)
+}
+
+type UserStorage interface {
+	storage.UserStorage
+	storage.TokenStorage
+}
+
+// RefreshToken godoc
+// @Summary Refresh tokens
+// @Description Exchanges a refresh token for a new access token and a new refresh token. Each refresh token can be used only once: replaying a used token revokes every token issued from the same login.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body models.RefreshRequest true "Refresh token"
+// @Success 200 {object} models.TokenResponse
+// @Failure 400
+// @Failure 401
+// @Failure 500
+// @Failure 503
+// @Failure 504
+// @Router /auth/refresh [post]
+func New(log *slog.Logger, userStorage UserStorage, jwtManager JWTManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handler.url.refreshToken.New"
+
+		log := sl.WithContext(log, r.Context()).With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		//1.Read body request
+		var req models.RefreshRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			if err == io.EOF {
+				log.Info("Request body is empty (EOF)")
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Request body cannot be empty"))
+				return
+			}
+
+			if strings.Contains(err.Error(), "invalid character") {
+				log.Info("Invalid JSON format", slog.String("error", err.Error()))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid JSON format"))
+				return
+			}
+
+			if strings.Contains(err.Error(), "syntax error") {
+				log.Info("JSON syntax error", slog.String("error", err.Error()))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("JSON syntax error"))
+				return
+			}
+
+			log.Error("Failed to decode request body", "error", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Failed to decode request body"))
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+			log.Error("Failed to validate request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+			return
+		}
+
+		newRefreshToken, err := jwtManager.GenerateRefreshToken()
+		if err != nil {
+			log.Error("failed to generate refresh token", sl.Err(err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Failed to generate token"))
+			return
+		}
+
+		rotated, err := userStorage.RotateRefreshToken(r.Context(), auth.HashToken(req.RefreshToken), newRefreshToken.Hash, newRefreshToken.ExpiresAt)
+		if err != nil {
+			switch {
+			case errors.Is(err, storageErr.ErrRefreshTokenReused):
+				log.Warn("Refresh token reuse detected, token family revoked", sl.Err(err))
+				render.Status(r, http.StatusUnauthorized)
+				render.JSON(w, r, resp.Error("Invalid refresh token"))
+			case errors.Is(err, storageErr.ErrRefreshTokenNotFound):
+				log.Info("Refresh token not found", sl.Err(err))
+				render.Status(r, http.StatusUnauthorized)
+				render.JSON(w, r, resp.Error("Invalid refresh token"))
+			case errors.Is(err, storageErr.ErrRefreshTokenExpired):
+				log.Info("Refresh token expired", sl.Err(err))
+				render.Status(r, http.StatusUnauthorized)
+				render.JSON(w, r, resp.Error("Refresh token expired"))
+			default:
+				log.Error("Failed to rotate refresh token", sl.Err(err))
+				render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+				render.JSON(w, r, resp.Error("Failed to refresh token"))
+			}
+			return
+		}
+
+		user, err := userStorage.GetUserByID(r.Context(), rotated.UserID)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrUserNotFound) {
+				log.Info("User not found", slog.Int64("id", rotated.UserID))
+				render.Status(r, http.StatusUnauthorized)
+				render.JSON(w, r, resp.Error("Invalid refresh token"))
+				return
+			}
+			log.Error("Failed to get user", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to refresh token"))
+			return
+		}
+
+		token, err := jwtManager.GenerateToken(r.Context(), user.ID, user.Username)
+		if err != nil {
+			log.Error("failed to generate token", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to generate token"))
+			return
+		}
+
+		log.Info("Success", slog.Int64("id", user.ID))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, models.TokenResponse{
+			Response:     resp.OK("Success"),
+			Token:        token,
+			RefreshToken: newRefreshToken.Token,
+		})
+	}
+}
@@ -2,9 +2,14 @@ package registUser
 
 import (
 	resp "NotesService/internal/api/response"
+	"NotesService/internal/auth"
 	"NotesService/internal/models"
 	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
 	sl "NotesService/pkg/logger/logSlog"
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
@@ -16,29 +21,34 @@ import (
 )
 
 type JWTManager interface {
-	GenerateToken(userID int64, username string) (string, error)
+	GenerateToken(ctx context.Context, userID int64, username string) (string, error)
+	GenerateRefreshToken() (*auth.RefreshToken, error)
 }
 
 type UserStorage interface {
 	storage.UserStorage
+	storage.TokenStorage
 }
 
 // RegisterUser godoc
 // @Summary Register new user
-// @Description Creates a new user and returns user info with JWT token
+// @Description Creates a new user and returns user info with JWT access and refresh tokens
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param input body models.UserRequest true "User registration data"
 // @Success 201 {object} models.UserResponse
 // @Failure 400
+// @Failure 409
 // @Failure 500
+// @Failure 503
+// @Failure 504
 // @Router /users [post]
 func New(log *slog.Logger, userStorage UserStorage, jwtManager JWTManager) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handler.url.registerUser.New"
 
-		log = log.With(
+		log := sl.WithContext(log, r.Context()).With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
@@ -73,15 +83,23 @@ func New(log *slog.Logger, userStorage UserStorage, jwtManager JWTManager) http.
 			return
 		}
 
-		log.Info("Request body decoded", slog.Any("request", req))
+		log.Info("Request body decoded", slog.String("user_name", req.Username))
 
 		if err := validator.New().Struct(req); err != nil {
 			validateErr := err.(validator.ValidationErrors)
 			log.Error("Failed to validate request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
 			render.JSON(w, r, resp.ValidationError(validateErr))
 			return
 		}
 
+		if len(req.Password) > auth.MaxPasswordBytes {
+			log.Info("Password is too long")
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(fmt.Sprintf("field Password must be at most %d bytes", auth.MaxPasswordBytes)))
+			return
+		}
+
 		if req.Username == "" {
 			log.Error("The fields cannot be empty.")
 			render.Status(r, http.StatusBadRequest)
@@ -91,32 +109,71 @@ func New(log *slog.Logger, userStorage UserStorage, jwtManager JWTManager) http.
 
 		UserName := strings.TrimSpace(req.Username)
 
-		user, err := userStorage.RegisterUser(UserName)
+		passwordHash, err := auth.HashPassword(req.Password)
 		if err != nil {
+			log.Error("failed to hash password", sl.Err(err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Failed to save user"))
+			return
+		}
+
+		user, err := userStorage.RegisterUser(r.Context(), UserName, passwordHash)
+		if err != nil {
+			if errors.Is(err, storageErr.ErrUserExists) {
+				log.Info("User already exists", slog.String("user_name", UserName))
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.Error("User already exists"))
+				return
+			}
 			log.Info("Failed to save user", "error", sl.Err(err))
-			render.Status(r, http.StatusBadRequest)
+			render.Status(r, resp.ErrorStatus(err, http.StatusBadRequest))
 			render.JSON(w, r, resp.Error("Failed to save user"))
 			return
 		}
 
 		// 4. Генерация JWT токена
-		token, err := jwtManager.GenerateToken(user.ID, user.Username)
+		token, err := jwtManager.GenerateToken(r.Context(), user.ID, user.Username)
 		if err != nil {
 			log.Error("failed to generate token", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to generate token"))
+			return
+		}
+
+		refreshToken, err := jwtManager.GenerateRefreshToken()
+		if err != nil {
+			log.Error("failed to generate refresh token", sl.Err(err))
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, resp.Error("Failed to generate token"))
 			return
 		}
 
+		familyID, err := auth.NewTokenFamily()
+		if err != nil {
+			log.Error("failed to generate token family", sl.Err(err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Failed to generate token"))
+			return
+		}
+
+		err = userStorage.SaveRefreshToken(r.Context(), user.ID, refreshToken.Hash, familyID, refreshToken.ExpiresAt)
+		if err != nil {
+			log.Error("failed to save refresh token", sl.Err(err))
+			render.Status(r, resp.ErrorStatus(err, http.StatusInternalServerError))
+			render.JSON(w, r, resp.Error("Failed to generate token"))
+			return
+		}
+
 		log.Info("Success", slog.Int64("id", user.ID))
 
 		render.Status(r, http.StatusCreated)
 		render.JSON(w, r, models.UserResponse{
-			Response:  resp.Created("Success"),
-			ID:        user.ID,
-			Username:  user.Username,
-			CreatedAt: user.CreatedAt,
-			Token:     token,
+			Response:     resp.Created("Success"),
+			ID:           user.ID,
+			Username:     user.Username,
+			CreatedAt:    user.CreatedAt,
+			Token:        token,
+			RefreshToken: refreshToken.Token,
 		})
 
 	}
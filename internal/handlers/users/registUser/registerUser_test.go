@@ -0,0 +1,58 @@
+package registUser
+
+import (
+	"NotesService/internal/auth"
+	"NotesService/internal/storage/memory"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type jwtStub struct{}
+
+func (jwtStub) GenerateToken(context.Context, int64, string) (string, error) { return "token", nil }
+
+func (jwtStub) GenerateRefreshToken() (*auth.RefreshToken, error) {
+	return &auth.RefreshToken{Token: "refresh", Hash: "hash", ExpiresAt: time.Now().Add(time.Hour)}, nil
+}
+
+func TestPasswordLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		want     int
+	}{
+		{name: "72 bytes", password: strings.Repeat("a", 72), want: http.StatusCreated},
+		{name: "73 bytes", password: strings.Repeat("a", 73), want: http.StatusBadRequest},
+		// 40 символов, но 80 байт: bcrypt такой пароль не примет
+		{name: "multibyte over limit", password: strings.Repeat("я", 40), want: http.StatusBadRequest},
+		{name: "multibyte within limit", password: strings.Repeat("я", 36), want: http.StatusCreated},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(map[string]string{
+				"user_name": "user_" + string(rune('a'+i)),
+				"password":  tt.password,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(string(body)))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			New(slog.New(slog.DiscardHandler), memory.New(), jwtStub{}).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("got status %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Package health сообщает балансировщику, готов ли экземпляр сервиса принимать запросы
+package health
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Статусы отчёта и отдельных проверок
+const (
+	StatusOK       = "ok"
+	StatusFailed   = "failed"
+	StatusDraining = "draining"
+)
+
+// HealthChecker — проверка зависимости, без которой экземпляр не может обслуживать запросы
+type HealthChecker interface {
+	// Name — ключ проверки в отчёте
+	Name() string
+	// Check возвращает ошибку, если зависимость недоступна. ctx ограничен таймаутом проверки
+	Check(ctx context.Context) error
+}
+
+type checkFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func (c checkFunc) Name() string                    { return c.name }
+func (c checkFunc) Check(ctx context.Context) error { return c.fn(ctx) }
+
+// CheckFunc делает HealthChecker из имени и функции проверки
+func CheckFunc(name string, fn func(ctx context.Context) error) HealthChecker {
+	return checkFunc{name: name, fn: fn}
+}
+
+// Report — результат проверки готовности
+type Report struct {
+	// ok, failed или draining (сервис останавливается, проверки не выполнялись)
+	Status string                 `json:"status" example:"ok"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// CheckResult — результат одной проверки. Ошибка в ответ не попадает: /readyz открыт
+// без авторизации, а текст ошибки драйвера раскрывает адреса и устройство зависимостей
+type CheckResult struct {
+	Status   string `json:"status" example:"ok"`
+	Err      error  `json:"-"`
+	Duration string `json:"duration" example:"1.2ms"`
+}
+
+// State — готовность экземпляра принимать трафик. При остановке сервиса готовность
+// снимается первой, чтобы балансировщик перестал направлять сюда новые запросы
+// до того, как сервер закроет соединения
+type State struct {
+	draining atomic.Bool
+
+	mu       sync.RWMutex
+	checkers []HealthChecker
+}
+
+// Register добавляет проверки, которые должны проходить, чтобы экземпляр считался готовым
+func (s *State) Register(checkers ...HealthChecker) {
+	s.mu.Lock()
+	s.checkers = append(s.checkers, checkers...)
+	s.mu.Unlock()
+}
+
+// Check выполняет все проверки параллельно, каждую не дольше timeout.
+// Во время остановки сервиса проверки не выполняются
+func (s *State) Check(ctx context.Context, timeout time.Duration) Report {
+	if s.Draining() {
+		return Report{Status: StatusDraining}
+	}
+
+	s.mu.RLock()
+	checkers := s.checkers
+	s.mu.RUnlock()
+
+	results := make([]CheckResult, len(checkers))
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Go(func() {
+			results[i] = run(ctx, checker, timeout)
+		})
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(checkers))}
+	for i, checker := range checkers {
+		report.Checks[checker.Name()] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFailed
+		}
+	}
+
+	return report
+}
+
+func run(ctx context.Context, checker HealthChecker, timeout time.Duration) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+	result := CheckResult{Status: StatusOK, Duration: time.Since(start).String()}
+	if err != nil {
+		result.Status = StatusFailed
+		result.Err = err
+	}
+
+	return result
+}
+
+// Drain снимает готовность: дальше проверка отвечает 503
+func (s *State) Drain() {
+	s.draining.Store(true)
+}
+
+// Draining сообщает, что сервис останавливается
+func (s *State) Draining() bool {
+	return s.draining.Load()
+}
+
+// Heartbeat отвечает на GET и HEAD запросы к endpoint, как middleware.Heartbeat из chi,
+// но после State.Drain возвращает 503
+func Heartbeat(endpoint string, state *State) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if (r.Method != http.MethodGet && r.Method != http.MethodHead) || !strings.EqualFold(r.URL.Path, endpoint) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Content-Type", "text/plain")
+			if state.Draining() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte("shutting down"))
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("."))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
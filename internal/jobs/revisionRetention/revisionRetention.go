@@ -0,0 +1,64 @@
+package revisionRetention
+
+import (
+	sl "NotesService/pkg/logger/logSlog"
+	"context"
+	"log/slog"
+	"time"
+)
+
+type RevisionStorage interface {
+	PruneNoteRevisions(ctx context.Context, maxCount int, before time.Time) (int64, error)
+}
+
+// Retention периодически удаляет старые версии заметок: сверх maxCount
+// последних для каждой заметки и старше maxAge. Нулевое значение отключает ограничение
+type Retention struct {
+	log      *slog.Logger
+	storage  RevisionStorage
+	maxCount int
+	maxAge   time.Duration
+}
+
+func New(log *slog.Logger, storage RevisionStorage, maxCount int, maxAge time.Duration) *Retention {
+	return &Retention{
+		log:      log.With(slog.String("op", "jobs.revisionRetention")),
+		storage:  storage,
+		maxCount: maxCount,
+		maxAge:   maxAge,
+	}
+}
+
+func (r *Retention) Run(ctx context.Context, interval time.Duration) {
+	if r.maxCount <= 0 && r.maxAge <= 0 {
+		r.log.Info("revision retention disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.prune(ctx)
+		}
+	}
+}
+
+func (r *Retention) prune(ctx context.Context) {
+	var before time.Time
+	if r.maxAge > 0 {
+		before = time.Now().Add(-r.maxAge)
+	}
+
+	pruned, err := r.storage.PruneNoteRevisions(ctx, r.maxCount, before)
+	if err != nil {
+		r.log.Error("failed to prune note revisions", sl.Err(err))
+		return
+	}
+
+	r.log.Debug("note revisions pruned", slog.Int64("count", pruned))
+}
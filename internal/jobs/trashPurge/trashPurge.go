@@ -0,0 +1,51 @@
+package trashPurge
+
+import (
+	sl "NotesService/pkg/logger/logSlog"
+	"context"
+	"log/slog"
+	"time"
+)
+
+type TrashStorage interface {
+	PurgeTrash(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Purger периодически окончательно удаляет заметки, пролежавшие в корзине дольше retention
+type Purger struct {
+	log       *slog.Logger
+	storage   TrashStorage
+	retention time.Duration
+}
+
+func New(log *slog.Logger, storage TrashStorage, retention time.Duration) *Purger {
+	return &Purger{
+		log:       log.With(slog.String("op", "jobs.trashPurge")),
+		storage:   storage,
+		retention: retention,
+	}
+}
+
+func (p *Purger) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.purge(ctx)
+		}
+	}
+}
+
+func (p *Purger) purge(ctx context.Context) {
+	purged, err := p.storage.PurgeTrash(ctx, time.Now().Add(-p.retention))
+	if err != nil {
+		p.log.Error("failed to purge trash", sl.Err(err))
+		return
+	}
+
+	p.log.Debug("trash purged", slog.Int64("count", purged))
+}
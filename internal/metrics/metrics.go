@@ -0,0 +1,104 @@
+// Package metrics собирает метрики сервиса и отдаёт их в формате Prometheus
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "notes_service"
+
+// Metrics — реестр метрик сервиса: HTTP запросы, вызовы хранилища, пул соединений
+// с базой и бизнес-счётчики
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	storageCalls    *prometheus.CounterVec
+	storageDuration *prometheus.HistogramVec
+
+	notesCreated    prometheus.Counter
+	notesUpdated    prometheus.Counter
+	notesDeleted    prometheus.Counter
+	usersRegistered prometheus.Counter
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by method, chi route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by method, chi route pattern and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+
+		storageCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "storage",
+			Name:      "calls_total",
+			Help:      "Storage calls by method and result (ok, error).",
+		}, []string{"method", "result"}),
+		storageDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "storage",
+			Name:      "call_duration_seconds",
+			Help:      "Storage call latency by method.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"method"}),
+
+		notesCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notes_created_total",
+			Help:      "Notes created.",
+		}),
+		notesUpdated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notes_updated_total",
+			Help:      "Notes updated, including restores of earlier revisions.",
+		}),
+		notesDeleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notes_deleted_total",
+			Help:      "Notes moved to trash.",
+		}),
+		usersRegistered: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "users_registered_total",
+			Help:      "Users registered.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.storageCalls,
+		m.storageDuration,
+		m.notesCreated,
+		m.notesUpdated,
+		m.notesDeleted,
+		m.usersRegistered,
+	)
+
+	return m
+}
+
+// Handler отдаёт метрики в текстовом формате Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
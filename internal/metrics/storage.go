@@ -0,0 +1,385 @@
+package metrics
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"context"
+	"time"
+)
+
+// Storage — хранилище, которое измеряет длительность и исход каждого вызова
+// и считает бизнес-события: созданные, изменённые и удалённые заметки, регистрации
+type Storage struct {
+	next    storage.Storage
+	metrics *Metrics
+}
+
+var _ storage.Storage = (*Storage)(nil)
+
+// NewStorage оборачивает хранилище next метриками m
+func NewStorage(m *Metrics, next storage.Storage) *Storage {
+	return &Storage{next: next, metrics: m}
+}
+
+func (s *Storage) observe(method string, start time.Time, err error) {
+	s.metrics.storageDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	s.metrics.storageCalls.WithLabelValues(method, result).Inc()
+}
+
+func (s *Storage) Close() error {
+	return s.next.Close()
+}
+
+// NoteStorage
+
+func (s *Storage) SaveNotes(ctx context.Context, title string, content string, idUser int64, tags []string, notebookID *int64) (*models.Note, int64, error) {
+	start := time.Now()
+	note, id, err := s.next.SaveNotes(ctx, title, content, idUser, tags, notebookID)
+	s.observe("SaveNotes", start, err)
+	if err == nil {
+		s.metrics.notesCreated.Inc()
+	}
+	return note, id, err
+}
+
+func (s *Storage) GetAllNotes(ctx context.Context, idUser int64, page storage.NotePage, filter storage.NoteFilter) ([]*models.Note, error) {
+	start := time.Now()
+	notes, err := s.next.GetAllNotes(ctx, idUser, page, filter)
+	s.observe("GetAllNotes", start, err)
+	return notes, err
+}
+
+func (s *Storage) CountNotes(ctx context.Context, idUser int64, filter storage.NoteFilter) (int64, error) {
+	start := time.Now()
+	total, err := s.next.CountNotes(ctx, idUser, filter)
+	s.observe("CountNotes", start, err)
+	return total, err
+}
+
+func (s *Storage) GetOneNote(ctx context.Context, idUser int64, idNote int64) (*models.Note, error) {
+	start := time.Now()
+	note, err := s.next.GetOneNote(ctx, idUser, idNote)
+	s.observe("GetOneNote", start, err)
+	return note, err
+}
+
+func (s *Storage) PutNote(ctx context.Context, idUser int64, idNote int64, title string, content string, tags []string, notebookID *int64, version int64) (*models.Note, error) {
+	start := time.Now()
+	note, err := s.next.PutNote(ctx, idUser, idNote, title, content, tags, notebookID, version)
+	s.observe("PutNote", start, err)
+	if err == nil {
+		s.metrics.notesUpdated.Inc()
+	}
+	return note, err
+}
+
+func (s *Storage) PatchNote(ctx context.Context, idUser int64, idNote int64, changes storage.NoteChanges, version int64) (*models.Note, error) {
+	start := time.Now()
+	note, err := s.next.PatchNote(ctx, idUser, idNote, changes, version)
+	s.observe("PatchNote", start, err)
+	if err == nil {
+		s.metrics.notesUpdated.Inc()
+	}
+	return note, err
+}
+
+func (s *Storage) DeleteNote(ctx context.Context, idUser int64, idNote int64, version int64) error {
+	start := time.Now()
+	err := s.next.DeleteNote(ctx, idUser, idNote, version)
+	s.observe("DeleteNote", start, err)
+	if err == nil {
+		s.metrics.notesDeleted.Inc()
+	}
+	return err
+}
+
+func (s *Storage) GetTags(ctx context.Context, idUser int64) ([]*models.Tag, error) {
+	start := time.Now()
+	tags, err := s.next.GetTags(ctx, idUser)
+	s.observe("GetTags", start, err)
+	return tags, err
+}
+
+func (s *Storage) SearchNotes(ctx context.Context, idUser int64, query string, limit, offset int) ([]*models.NoteSearchResult, error) {
+	start := time.Now()
+	results, err := s.next.SearchNotes(ctx, idUser, query, limit, offset)
+	s.observe("SearchNotes", start, err)
+	return results, err
+}
+
+// TrashStorage
+
+func (s *Storage) GetTrash(ctx context.Context, idUser int64) ([]*models.Note, error) {
+	start := time.Now()
+	notes, err := s.next.GetTrash(ctx, idUser)
+	s.observe("GetTrash", start, err)
+	return notes, err
+}
+
+func (s *Storage) RestoreNote(ctx context.Context, idUser int64, idNote int64) (*models.Note, error) {
+	start := time.Now()
+	note, err := s.next.RestoreNote(ctx, idUser, idNote)
+	s.observe("RestoreNote", start, err)
+	return note, err
+}
+
+func (s *Storage) EmptyTrash(ctx context.Context, idUser int64) (int64, error) {
+	start := time.Now()
+	deleted, err := s.next.EmptyTrash(ctx, idUser)
+	s.observe("EmptyTrash", start, err)
+	return deleted, err
+}
+
+func (s *Storage) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	start := time.Now()
+	purged, err := s.next.PurgeTrash(ctx, before)
+	s.observe("PurgeTrash", start, err)
+	return purged, err
+}
+
+// RevisionStorage
+
+func (s *Storage) GetNoteRevisions(ctx context.Context, idUser int64, idNote int64) ([]*models.NoteRevision, error) {
+	start := time.Now()
+	revisions, err := s.next.GetNoteRevisions(ctx, idUser, idNote)
+	s.observe("GetNoteRevisions", start, err)
+	return revisions, err
+}
+
+func (s *Storage) GetNoteRevision(ctx context.Context, idUser int64, idNote int64, revision int64) (*models.NoteRevision, error) {
+	start := time.Now()
+	noteRevision, err := s.next.GetNoteRevision(ctx, idUser, idNote, revision)
+	s.observe("GetNoteRevision", start, err)
+	return noteRevision, err
+}
+
+func (s *Storage) RestoreNoteRevision(ctx context.Context, idUser int64, idNote int64, revision int64) (*models.Note, error) {
+	start := time.Now()
+	note, err := s.next.RestoreNoteRevision(ctx, idUser, idNote, revision)
+	s.observe("RestoreNoteRevision", start, err)
+	if err == nil {
+		s.metrics.notesUpdated.Inc()
+	}
+	return note, err
+}
+
+func (s *Storage) PruneNoteRevisions(ctx context.Context, maxCount int, before time.Time) (int64, error) {
+	start := time.Now()
+	pruned, err := s.next.PruneNoteRevisions(ctx, maxCount, before)
+	s.observe("PruneNoteRevisions", start, err)
+	return pruned, err
+}
+
+// NotebookStorage
+
+func (s *Storage) CreateNotebook(ctx context.Context, idUser int64, name string, parentID *int64) (*models.Notebook, error) {
+	start := time.Now()
+	notebook, err := s.next.CreateNotebook(ctx, idUser, name, parentID)
+	s.observe("CreateNotebook", start, err)
+	return notebook, err
+}
+
+func (s *Storage) GetNotebooks(ctx context.Context, idUser int64) ([]*models.Notebook, error) {
+	start := time.Now()
+	notebooks, err := s.next.GetNotebooks(ctx, idUser)
+	s.observe("GetNotebooks", start, err)
+	return notebooks, err
+}
+
+func (s *Storage) GetNotebook(ctx context.Context, idUser int64, idNotebook int64) (*models.Notebook, error) {
+	start := time.Now()
+	notebook, err := s.next.GetNotebook(ctx, idUser, idNotebook)
+	s.observe("GetNotebook", start, err)
+	return notebook, err
+}
+
+func (s *Storage) PutNotebook(ctx context.Context, idUser int64, idNotebook int64, name string, parentID *int64) (*models.Notebook, error) {
+	start := time.Now()
+	notebook, err := s.next.PutNotebook(ctx, idUser, idNotebook, name, parentID)
+	s.observe("PutNotebook", start, err)
+	return notebook, err
+}
+
+func (s *Storage) DeleteNotebook(ctx context.Context, idUser int64, idNotebook int64) error {
+	start := time.Now()
+	err := s.next.DeleteNotebook(ctx, idUser, idNotebook)
+	s.observe("DeleteNotebook", start, err)
+	return err
+}
+
+// ShareStorage
+
+func (s *Storage) ShareNote(ctx context.Context, idOwner int64, idNote int64, idUser int64, permission string) (*models.NoteShare, error) {
+	start := time.Now()
+	share, err := s.next.ShareNote(ctx, idOwner, idNote, idUser, permission)
+	s.observe("ShareNote", start, err)
+	return share, err
+}
+
+func (s *Storage) UnshareNote(ctx context.Context, idOwner int64, idNote int64, idUser int64) error {
+	start := time.Now()
+	err := s.next.UnshareNote(ctx, idOwner, idNote, idUser)
+	s.observe("UnshareNote", start, err)
+	return err
+}
+
+func (s *Storage) GetNoteShares(ctx context.Context, idOwner int64, idNote int64) ([]*models.NoteShare, error) {
+	start := time.Now()
+	shares, err := s.next.GetNoteShares(ctx, idOwner, idNote)
+	s.observe("GetNoteShares", start, err)
+	return shares, err
+}
+
+func (s *Storage) GetSharedNotes(ctx context.Context, idUser int64) ([]*models.SharedNote, error) {
+	start := time.Now()
+	notes, err := s.next.GetSharedNotes(ctx, idUser)
+	s.observe("GetSharedNotes", start, err)
+	return notes, err
+}
+
+func (s *Storage) GetSharePermission(ctx context.Context, idNote int64, idUser int64) (string, error) {
+	start := time.Now()
+	permission, err := s.next.GetSharePermission(ctx, idNote, idUser)
+	s.observe("GetSharePermission", start, err)
+	return permission, err
+}
+
+// LinkStorage
+
+func (s *Storage) CreateNoteLink(ctx context.Context, idOwner int64, idNote int64, tokenHash string, passwordHash string, expiresAt *time.Time) (*models.NoteLink, error) {
+	start := time.Now()
+	link, err := s.next.CreateNoteLink(ctx, idOwner, idNote, tokenHash, passwordHash, expiresAt)
+	s.observe("CreateNoteLink", start, err)
+	return link, err
+}
+
+func (s *Storage) GetNoteLinks(ctx context.Context, idOwner int64, idNote int64) ([]*models.NoteLink, error) {
+	start := time.Now()
+	links, err := s.next.GetNoteLinks(ctx, idOwner, idNote)
+	s.observe("GetNoteLinks", start, err)
+	return links, err
+}
+
+func (s *Storage) RevokeNoteLink(ctx context.Context, idOwner int64, idNote int64, idLink int64) error {
+	start := time.Now()
+	err := s.next.RevokeNoteLink(ctx, idOwner, idNote, idLink)
+	s.observe("RevokeNoteLink", start, err)
+	return err
+}
+
+func (s *Storage) GetNoteLink(ctx context.Context, tokenHash string) (*models.NoteLink, error) {
+	start := time.Now()
+	link, err := s.next.GetNoteLink(ctx, tokenHash)
+	s.observe("GetNoteLink", start, err)
+	return link, err
+}
+
+func (s *Storage) ViewNoteLink(ctx context.Context, idLink int64) (*models.Note, int64, error) {
+	start := time.Now()
+	note, views, err := s.next.ViewNoteLink(ctx, idLink)
+	s.observe("ViewNoteLink", start, err)
+	return note, views, err
+}
+
+// UserStorage
+
+func (s *Storage) RegisterUser(ctx context.Context, userName string, passwordHash string) (*models.User, error) {
+	start := time.Now()
+	user, err := s.next.RegisterUser(ctx, userName, passwordHash)
+	s.observe("RegisterUser", start, err)
+	if err == nil {
+		s.metrics.usersRegistered.Inc()
+	}
+	return user, err
+}
+
+func (s *Storage) GetUserByName(ctx context.Context, userName string) (*models.User, error) {
+	start := time.Now()
+	user, err := s.next.GetUserByName(ctx, userName)
+	s.observe("GetUserByName", start, err)
+	return user, err
+}
+
+func (s *Storage) GetUserByID(ctx context.Context, idUser int64) (*models.User, error) {
+	start := time.Now()
+	user, err := s.next.GetUserByID(ctx, idUser)
+	s.observe("GetUserByID", start, err)
+	return user, err
+}
+
+func (s *Storage) SetUserPassword(ctx context.Context, idUser int64, passwordHash string) error {
+	start := time.Now()
+	err := s.next.SetUserPassword(ctx, idUser, passwordHash)
+	s.observe("SetUserPassword", start, err)
+	return err
+}
+
+// TokenStorage
+
+func (s *Storage) SaveRefreshToken(ctx context.Context, idUser int64, tokenHash string, familyID string, expiresAt time.Time) error {
+	start := time.Now()
+	err := s.next.SaveRefreshToken(ctx, idUser, tokenHash, familyID, expiresAt)
+	s.observe("SaveRefreshToken", start, err)
+	return err
+}
+
+func (s *Storage) RotateRefreshToken(ctx context.Context, oldHash string, newHash string, expiresAt time.Time) (*models.RefreshToken, error) {
+	start := time.Now()
+	token, err := s.next.RotateRefreshToken(ctx, oldHash, newHash, expiresAt)
+	s.observe("RotateRefreshToken", start, err)
+	return token, err
+}
+
+func (s *Storage) RevokeRefreshTokenFamily(ctx context.Context, idUser int64, tokenHash string) error {
+	start := time.Now()
+	err := s.next.RevokeRefreshTokenFamily(ctx, idUser, tokenHash)
+	s.observe("RevokeRefreshTokenFamily", start, err)
+	return err
+}
+
+func (s *Storage) RevokeUserRefreshTokens(ctx context.Context, idUser int64) error {
+	start := time.Now()
+	err := s.next.RevokeUserRefreshTokens(ctx, idUser)
+	s.observe("RevokeUserRefreshTokens", start, err)
+	return err
+}
+
+func (s *Storage) RevokeToken(ctx context.Context, jti string, idUser int64, expiresAt time.Time) error {
+	start := time.Now()
+	err := s.next.RevokeToken(ctx, jti, idUser, expiresAt)
+	s.observe("RevokeToken", start, err)
+	return err
+}
+
+func (s *Storage) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	start := time.Now()
+	revoked, err := s.next.IsTokenRevoked(ctx, jti)
+	s.observe("IsTokenRevoked", start, err)
+	return revoked, err
+}
+
+func (s *Storage) GetTokenGeneration(ctx context.Context, idUser int64) (int64, error) {
+	start := time.Now()
+	generation, err := s.next.GetTokenGeneration(ctx, idUser)
+	s.observe("GetTokenGeneration", start, err)
+	return generation, err
+}
+
+func (s *Storage) IncrementTokenGeneration(ctx context.Context, idUser int64) (int64, error) {
+	start := time.Now()
+	generation, err := s.next.IncrementTokenGeneration(ctx, idUser)
+	s.observe("IncrementTokenGeneration", start, err)
+	return generation, err
+}
+
+func (s *Storage) PurgeExpiredTokens(ctx context.Context, before time.Time) (int64, error) {
+	start := time.Now()
+	purged, err := s.next.PurgeExpiredTokens(ctx, before)
+	s.observe("PurgeExpiredTokens", start, err)
+	return purged, err
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"NotesService/pkg/logger/redact"
+	"log/slog"
+)
+
+// Модели с учётными данными и содержимым заметок выводятся в лог через redact.Struct:
+// поля с тегом log:"secret" скрываются всегда, заголовки и текст заметок — обработчиком
+// логгера по ключам title и content в зависимости от окружения
+
+func (n Note) LogValue() slog.Value               { return redact.Struct(n) }
+func (r NoteRevision) LogValue() slog.Value       { return redact.Struct(r) }
+func (r NoteSearchResult) LogValue() slog.Value   { return redact.Struct(r) }
+func (n SharedNote) LogValue() slog.Value         { return redact.Struct(n) }
+func (l NoteLink) LogValue() slog.Value           { return redact.Struct(l) }
+func (u User) LogValue() slog.Value               { return redact.Struct(u) }
+func (t RefreshToken) LogValue() slog.Value       { return redact.Struct(t) }
+func (r UserRequest) LogValue() slog.Value        { return redact.Struct(r) }
+func (r LoginRequest) LogValue() slog.Value       { return redact.Struct(r) }
+func (r RefreshRequest) LogValue() slog.Value     { return redact.Struct(r) }
+func (r LogoutRequest) LogValue() slog.Value      { return redact.Struct(r) }
+func (r UserResponse) LogValue() slog.Value       { return redact.Struct(r) }
+func (r TokenResponse) LogValue() slog.Value      { return redact.Struct(r) }
+func (r PutNoteRequest) LogValue() slog.Value     { return redact.Struct(r) }
+func (r SaveNoteRequest) LogValue() slog.Value    { return redact.Struct(r) }
+func (r NotebookRequest) LogValue() slog.Value    { return redact.Struct(r) }
+func (r CreateLinkRequest) LogValue() slog.Value  { return redact.Struct(r) }
+func (r LinkResponse) LogValue() slog.Value       { return redact.Struct(r) }
+func (r PublicNoteResponse) LogValue() slog.Value { return redact.Struct(r) }
@@ -5,51 +5,374 @@ import (
 	"time"
 )
 
+// Note — заметка. Теги json совпадают с ключами документа PATCH: поля, которые
+// можно изменить, перечислены в storage.NoteFields
 type Note struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Title      string     `json:"title"`
+	Content    string     `json:"content"`
+	Tags       []string   `json:"tags"`
+	NotebookID *int64     `json:"notebook_id"` // nil — заметка лежит вне блокнотов
+	Version    int64      `json:"version"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	DeletedAt  *time.Time `json:"deleted_at"` // не nil — заметка в корзине
+}
+
+type Notebook struct {
 	ID        int64
 	UserID    int64
+	ParentID  *int64 // nil — блокнот верхнего уровня
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type NoteRevision struct {
+	NoteID    int64
+	Revision  int64
 	Title     string
 	Content   string
 	CreatedAt time.Time
-	UpdatedAt time.Time
+}
+
+type NoteSearchResult struct {
+	Note
+	Rank float64
+	// Заголовок и фрагмент текста без экранирования; найденные слова обёрнуты
+	// в storage.HighlightStart и storage.HighlightStop
+	TitleHighlight string
+	Snippet        string
+}
+
+type Tag struct {
+	ID        int64
+	Name      string
+	NoteCount int64
+}
+
+// Права доступа к чужой заметке
+const (
+	PermissionRead  = "read"
+	PermissionWrite = "write"
+)
+
+type NoteShare struct {
+	NoteID     int64
+	UserID     int64
+	Username   string
+	Permission string
+	CreatedAt  time.Time
+}
+
+// SharedNote — заметка другого пользователя, к которой выдан доступ
+type SharedNote struct {
+	Note
+	OwnerName  string
+	Permission string
+}
+
+// NoteLink — публичная ссылка на заметку для чтения без аккаунта
+type NoteLink struct {
+	ID           int64
+	NoteID       int64
+	TokenHash    string `log:"secret"`
+	PasswordHash string `log:"secret"`
+	ExpiresAt    *time.Time
+	RevokedAt    *time.Time
+	ViewCount    int64
+	CreatedAt    time.Time
 }
 
 type User struct {
+	ID           int64
+	Username     string
+	PasswordHash string `log:"secret"`
+	CreatedAt    time.Time
+}
+type RefreshToken struct {
 	ID        int64
-	Username  string
+	UserID    int64
+	TokenHash string `log:"secret"`
+	FamilyID  string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
 	CreatedAt time.Time
 }
+
 type UserRequest struct {
 	Username string `json:"user_name" validate:"required,min=3" example:"john_doe"`
+	Password string `json:"password" validate:"required,min=8,max=72" example:"s3cr3t-passw0rd" log:"secret"`
+}
+
+type LoginRequest struct {
+	Username string `json:"user_name" validate:"required" example:"john_doe"`
+	Password string `json:"password" validate:"required" example:"s3cr3t-passw0rd" log:"secret"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required" example:"q7Jd0d2b8lV3sWmZ..." log:"secret"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty" example:"q7Jd0d2b8lV3sWmZ..." log:"secret"`
 }
 
 type UserResponse struct {
 	resp.Response
-	ID        int64     `json:"id" example:"1"`
-	Username  string    `json:"user_name" example:"john_doe"`
-	CreatedAt time.Time `json:"created_at" example:"2025-01-01T12:00:00Z"`
-	Token     string    `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ID           int64     `json:"id" example:"1"`
+	Username     string    `json:"user_name" example:"john_doe"`
+	CreatedAt    time.Time `json:"created_at" example:"2025-01-01T12:00:00Z"`
+	Token        string    `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." log:"secret"`
+	RefreshToken string    `json:"refresh_token" example:"q7Jd0d2b8lV3sWmZ..." log:"secret"`
+}
+
+type TokenResponse struct {
+	resp.Response
+	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." log:"secret"`
+	RefreshToken string `json:"refresh_token" example:"q7Jd0d2b8lV3sWmZ..." log:"secret"`
 }
 type NoteResponse struct {
 	resp.Response
-	NoteID    int64     `json:"noteID" example:"1"`
-	UserId    int64     `json:"userId" example:"1"`
-	Title     string    `json:"title" example:"note title"`
-	Content   string    `json:"content" example:"note content"`
-	CreatedAt time.Time `json:"createdAt" example:"2026-02-15T18:01:29.342814+02:00"`
-	UpdatedAt time.Time `json:"updatedAt" example:"2026-02-15T18:01:29.342814+02:00"`
+	NoteID     int64     `json:"noteID" example:"1"`
+	UserId     int64     `json:"userId" example:"1"`
+	Title      string    `json:"title" example:"note title"`
+	Content    string    `json:"content" example:"note content"`
+	Tags       []string  `json:"tags" example:"work,meeting"`
+	NotebookID *int64    `json:"notebook_id,omitempty" example:"3"`
+	Version    int64     `json:"version" example:"2"`
+	CreatedAt  time.Time `json:"createdAt" example:"2026-02-15T18:01:29.342814+02:00"`
+	UpdatedAt  time.Time `json:"updatedAt" example:"2026-02-15T18:01:29.342814+02:00"`
+}
+
+type NoteItem struct {
+	NoteID     int64     `json:"noteID" example:"1"`
+	UserId     int64     `json:"userId" example:"1"`
+	Title      string    `json:"title" example:"note title"`
+	Content    string    `json:"content" example:"note content"`
+	Tags       []string  `json:"tags" example:"work,meeting"`
+	NotebookID *int64    `json:"notebook_id,omitempty" example:"3"`
+	Version    int64     `json:"version" example:"2"`
+	CreatedAt  time.Time `json:"createdAt" example:"2026-02-15T18:01:29.342814+02:00"`
+	UpdatedAt  time.Time `json:"updatedAt" example:"2026-02-15T18:01:29.342814+02:00"`
+}
+
+type NoteListResponse struct {
+	resp.Response
+	Items []NoteItem `json:"items"`
+	// Число заметок, подходящих под фильтры, без учёта пагинации
+	Total int64 `json:"total" example:"42"`
+	Limit int   `json:"limit" example:"10"`
+	// Смещение страницы; отсутствует при переходе по курсору
+	Offset *int `json:"offset,omitempty" example:"0"`
+	// Курсоры соседних страниц; отсутствуют, если страницы нет
+	NextCursor string `json:"next_cursor,omitempty" example:"eyJ1IjoxLCJzIjoiY3JlYXRlZF9hdCJ9.kq3..."`
+	PrevCursor string `json:"prev_cursor,omitempty" example:"eyJ1IjoxLCJzIjoiY3JlYXRlZF9hdCJ9.Zx1..."`
 }
 
 type PutNoteRequest struct {
 	TitleNote   string `json:"title" validate:"required" example:"My new title"`
 	ContentNote string `json:"content" validate:"required" example:"Updated note content"`
+	// Если поле не передано, теги заметки не меняются; [] удаляет все теги
+	Tags []string `json:"tags,omitempty" validate:"omitempty,max=20,dive,max=50" example:"work,meeting"`
+	// Если поле не передано, заметка остаётся в своём блокноте; 0 переносит её из блокнота
+	NotebookID *int64 `json:"notebook_id,omitempty" validate:"omitempty,min=0" example:"3"`
 }
 
 type SaveNoteRequest struct {
-	TitleNote   string `json:"title" validate:"required" example:"My new title"`
-	ContentNote string `json:"content" validate:"required" example:"Updated note content"`
+	TitleNote   string   `json:"title" validate:"required" example:"My new title"`
+	ContentNote string   `json:"content" validate:"required" example:"Updated note content"`
+	Tags        []string `json:"tags,omitempty" validate:"omitempty,max=20,dive,max=50" example:"work,meeting"`
+	NotebookID  *int64   `json:"notebook_id,omitempty" validate:"omitempty,min=1" example:"3"`
+}
+
+type NotebookRequest struct {
+	Name string `json:"name" validate:"required,max=200" example:"Work"`
+	// Если поле не передано, блокнот становится блокнотом верхнего уровня
+	ParentID *int64 `json:"parent_id,omitempty" validate:"omitempty,min=1" example:"1"`
+}
+
+type NotebookItem struct {
+	ID        int64     `json:"id" example:"3"`
+	ParentID  *int64    `json:"parent_id" example:"1"`
+	Name      string    `json:"name" example:"Work"`
+	CreatedAt time.Time `json:"created_at" example:"2026-02-15T18:01:29.342814+02:00"`
+	UpdatedAt time.Time `json:"updated_at" example:"2026-02-15T18:01:29.342814+02:00"`
+}
+
+type NotebookResponse struct {
+	resp.Response
+	NotebookItem
+}
+
+type NotebookListResponse struct {
+	resp.Response
+	Notebooks []NotebookItem `json:"notebooks"`
+}
+
+type SearchResultItem struct {
+	NoteID     int64     `json:"noteID" example:"1"`
+	Title      string    `json:"title" example:"Meeting <mark>notes</mark>"`
+	Snippet    string    `json:"snippet" example:"...discussed the <mark>release</mark> plan..."`
+	Rank       float64   `json:"rank" example:"0.6079271"`
+	Tags       []string  `json:"tags" example:"work,meeting"`
+	NotebookID *int64    `json:"notebook_id,omitempty" example:"3"`
+	CreatedAt  time.Time `json:"createdAt" example:"2026-02-15T18:01:29.342814+02:00"`
+	UpdatedAt  time.Time `json:"updatedAt" example:"2026-02-15T18:01:29.342814+02:00"`
+}
+
+type SearchResponse struct {
+	resp.Response
+	Query   string             `json:"query" example:"\"release plan\" meet*"`
+	Results []SearchResultItem `json:"results"`
+}
+
+type RevisionItem struct {
+	Revision  int64     `json:"revision" example:"3"`
+	Title     string    `json:"title" example:"note title"`
+	CreatedAt time.Time `json:"created_at" example:"2026-02-15T18:01:29.342814+02:00"`
+}
+
+type RevisionListResponse struct {
+	resp.Response
+	NoteID    int64          `json:"noteID" example:"1"`
+	Revisions []RevisionItem `json:"revisions"`
+}
+
+type RevisionResponse struct {
+	resp.Response
+	NoteID int64 `json:"noteID" example:"1"`
+	RevisionItem
+	Content string `json:"content" example:"note content"`
+}
+
+type DiffLine struct {
+	Op   string `json:"op" enums:"equal,insert,delete" example:"insert"`
+	Text string `json:"text" example:"added line"`
+}
+
+type RevisionDiffResponse struct {
+	resp.Response
+	NoteID int64 `json:"noteID" example:"1"`
+	From   int64 `json:"from" example:"2"`
+	// Отсутствует, если сравнение идёт с текущей версией заметки
+	To      *int64     `json:"to,omitempty" example:"3"`
+	Title   []DiffLine `json:"title"`
+	Content []DiffLine `json:"content"`
+}
+
+type TrashItem struct {
+	NoteID     int64     `json:"noteID" example:"1"`
+	Title      string    `json:"title" example:"note title"`
+	Content    string    `json:"content" example:"note content"`
+	Tags       []string  `json:"tags" example:"work,meeting"`
+	NotebookID *int64    `json:"notebook_id,omitempty" example:"3"`
+	CreatedAt  time.Time `json:"createdAt" example:"2026-02-15T18:01:29.342814+02:00"`
+	UpdatedAt  time.Time `json:"updatedAt" example:"2026-02-15T18:01:29.342814+02:00"`
+	DeletedAt  time.Time `json:"deletedAt" example:"2026-02-16T09:12:00.000000+02:00"`
+	// Когда заметка будет удалена окончательно
+	PurgeAt time.Time `json:"purgeAt" example:"2026-03-18T09:12:00.000000+02:00"`
+}
+
+type TrashResponse struct {
+	resp.Response
+	Notes []TrashItem `json:"notes"`
+}
+
+type EmptyTrashResponse struct {
+	resp.Response
+	Deleted int64 `json:"deleted" example:"4"`
+}
+
+type TagItem struct {
+	Name      string `json:"name" example:"work"`
+	NoteCount int64  `json:"note_count" example:"12"`
+}
+
+type TagListResponse struct {
+	resp.Response
+	Tags []TagItem `json:"tags"`
 }
 
 type DeleteResponse struct {
 	resp.Response
 }
+
+type ShareRequest struct {
+	Username   string `json:"user_name" validate:"required" example:"jane_doe"`
+	Permission string `json:"permission" validate:"required,oneof=read write" example:"read"`
+}
+
+type ShareItem struct {
+	UserID     int64     `json:"userId" example:"2"`
+	Username   string    `json:"user_name" example:"jane_doe"`
+	Permission string    `json:"permission" example:"read"`
+	CreatedAt  time.Time `json:"createdAt" example:"2026-02-15T18:01:29.342814+02:00"`
+}
+
+type ShareResponse struct {
+	resp.Response
+	NoteID int64 `json:"noteID" example:"1"`
+	ShareItem
+}
+
+type ShareListResponse struct {
+	resp.Response
+	NoteID int64       `json:"noteID" example:"1"`
+	Shares []ShareItem `json:"shares"`
+}
+
+type SharedNoteItem struct {
+	NoteID     int64     `json:"noteID" example:"1"`
+	UserId     int64     `json:"userId" example:"1"`
+	OwnerName  string    `json:"owner_name" example:"john_doe"`
+	Title      string    `json:"title" example:"note title"`
+	Content    string    `json:"content" example:"note content"`
+	Permission string    `json:"permission" example:"write"`
+	CreatedAt  time.Time `json:"createdAt" example:"2026-02-15T18:01:29.342814+02:00"`
+	UpdatedAt  time.Time `json:"updatedAt" example:"2026-02-15T18:01:29.342814+02:00"`
+}
+
+type SharedNotesResponse struct {
+	resp.Response
+	Notes []SharedNoteItem `json:"notes"`
+}
+
+type CreateLinkRequest struct {
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2026-12-31T23:59:59Z"`
+	Password  string     `json:"password,omitempty" validate:"omitempty,min=4,max=72" example:"open-sesame" log:"secret"`
+}
+
+type LinkItem struct {
+	ID          int64      `json:"id" example:"1"`
+	HasPassword bool       `json:"has_password" example:"false"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" example:"2026-12-31T23:59:59Z"`
+	Revoked     bool       `json:"revoked" example:"false"`
+	ViewCount   int64      `json:"view_count" example:"12"`
+	CreatedAt   time.Time  `json:"createdAt" example:"2026-02-15T18:01:29.342814+02:00"`
+}
+
+type LinkResponse struct {
+	resp.Response
+	LinkItem
+	// Токен показывается только при создании ссылки
+	Token string `json:"token" example:"Zr4q2s0nXk3H9yV1dL8pQm7tB6wE5cA0uJ2fG4hK1iM" log:"secret"`
+	URL   string `json:"url" example:"/public/notes/Zr4q2s0nXk3H9yV1dL8pQm7tB6wE5cA0uJ2fG4hK1iM" log:"secret"`
+}
+
+type LinkListResponse struct {
+	resp.Response
+	NoteID int64      `json:"noteID" example:"1"`
+	Links  []LinkItem `json:"links"`
+}
+
+type PublicNoteResponse struct {
+	resp.Response
+	Title     string    `json:"title" example:"note title"`
+	Content   string    `json:"content" example:"note content"`
+	CreatedAt time.Time `json:"createdAt" example:"2026-02-15T18:01:29.342814+02:00"`
+	UpdatedAt time.Time `json:"updatedAt" example:"2026-02-15T18:01:29.342814+02:00"`
+	ViewCount int64     `json:"view_count" example:"13"`
+}
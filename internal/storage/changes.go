@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"NotesService/internal/models"
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// Ключи изменяемых полей заметки в NoteFields и NoteChanges
+const (
+	NoteFieldTitle      = "title"
+	NoteFieldContent    = "content"
+	NoteFieldTags       = "tags"
+	NoteFieldNotebookID = "notebook_id"
+)
+
+// NoteField — поле заметки, которое можно изменить через PATCH. Изменяемое поле
+// регистрируется только в NoteFields: по этой таблице строятся документ, к которому
+// применяется патч, проверка и сравнение результата с заметкой, NoteChanges
+// и запись изменений во всех хранилищах
+type NoteField struct {
+	// Name — ключ поля в документе PATCH, совпадает с тегом json поля models.Note
+	Name string
+	// Column — столбец таблицы notes, в который хранилища с SQL записывают значение.
+	// Пусто, если поле хранится по-своему (теги лежат в отдельной таблице)
+	Column string
+	// Validate — правила validator для значения поля после Decode
+	Validate string
+	// OwnerOnly — поле может изменить только владелец заметки, но не пользователь
+	// с доступом на запись: теги и блокноты относятся к организации заметок владельца
+	OwnerOnly bool
+	// Get возвращает значение поля заметки в том виде, в котором его возвращает Decode
+	Get func(n *models.Note) any
+	// Set записывает значение поля в заметку
+	Set func(n *models.Note, value any)
+	// Decode разбирает значение из изменённого документа (nil — ключа в документе нет)
+	// и нормализует его
+	Decode func(raw json.RawMessage) (any, error)
+	// Equal сообщает, что два значения поля совпадают
+	Equal func(a, b any) bool
+}
+
+// NoteFields — изменяемые поля заметки в порядке ключей документа
+var NoteFields = []NoteField{
+	noteField(NoteFieldTitle, "title", "required",
+		func(n *models.Note) string { return n.Title },
+		func(n *models.Note, v string) { n.Title = v },
+		strings.TrimSpace,
+		func(a, b string) bool { return a == b },
+	),
+	noteField(NoteFieldContent, "content", "required",
+		func(n *models.Note) string { return n.Content },
+		func(n *models.Note, v string) { n.Content = v },
+		strings.TrimSpace,
+		func(a, b string) bool { return a == b },
+	),
+	ownerOnly(noteField(NoteFieldTags, "", "max=20,dive,max=50",
+		func(n *models.Note) []string { return normalizeTagList(n.Tags) },
+		func(n *models.Note, v []string) { n.Tags = slices.Clone(v) },
+		normalizeTagList,
+		func(a, b []string) bool {
+			return slices.Equal(slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b)))
+		},
+	)),
+	// nil — заметка вне блокнотов. Блокнот должен принадлежать владельцу заметки
+	ownerOnly(noteField(NoteFieldNotebookID, "notebook_id", "omitempty,min=1",
+		func(n *models.Note) *int64 { return n.NotebookID },
+		func(n *models.Note, v *int64) {
+			n.NotebookID = nil
+			if v != nil {
+				id := *v
+				n.NotebookID = &id
+			}
+		},
+		nil,
+		func(a, b *int64) bool { return (a == nil) == (b == nil) && (a == nil || *a == *b) },
+	)),
+}
+
+// noteField описывает поле со значением типа T. normalize может быть nil
+func noteField[T any](name, column, validate string, get func(n *models.Note) T, set func(n *models.Note, v T), normalize func(T) T, equal func(a, b T) bool) NoteField {
+	return NoteField{
+		Name:     name,
+		Column:   column,
+		Validate: validate,
+		Get:      func(n *models.Note) any { return get(n) },
+		Set:      func(n *models.Note, value any) { set(n, value.(T)) },
+		Decode: func(raw json.RawMessage) (any, error) {
+			var v T
+			if raw != nil {
+				if err := json.Unmarshal(raw, &v); err != nil {
+					return nil, err
+				}
+			}
+			if normalize != nil {
+				v = normalize(v)
+			}
+			return v, nil
+		},
+		Equal: func(a, b any) bool { return equal(a.(T), b.(T)) },
+	}
+}
+
+// ownerOnly отмечает поле, которое может изменить только владелец заметки
+func ownerOnly(f NoteField) NoteField {
+	f.OwnerOnly = true
+	return f
+}
+
+// normalizeTagList нормализует теги; пустой список остаётся пустым, а не nil
+func normalizeTagList(tags []string) []string {
+	tags = NormalizeTags(tags)
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// LookupNoteField возвращает описание изменяемого поля по ключу
+func LookupNoteField(name string) (NoteField, bool) {
+	i := slices.IndexFunc(NoteFields, func(f NoteField) bool { return f.Name == name })
+	if i < 0 {
+		return NoteField{}, false
+	}
+	return NoteFields[i], true
+}
+
+// NoteDocument возвращает изменяемые поля заметки в виде документа для PATCH
+func NoteDocument(n *models.Note) map[string]any {
+	doc := make(map[string]any, len(NoteFields))
+	for _, f := range NoteFields {
+		doc[f.Name] = f.Get(n)
+	}
+	return doc
+}
+
+// NoteChanges — новые значения изменяемых полей заметки по ключу NoteField.Name,
+// в том виде, в котором их возвращает NoteField.Decode. Поля, которых нет
+// в NoteChanges, остаются без изменений
+type NoteChanges map[string]any
+
+// Empty сообщает, что изменений нет
+func (c NoteChanges) Empty() bool {
+	return len(c) == 0
+}
+
+// OwnerOnly возвращает ключи изменённых полей, которые может изменить только владелец заметки
+func (c NoteChanges) OwnerOnly() []string {
+	var names []string
+	for _, f := range NoteFields {
+		if _, ok := c[f.Name]; ok && f.OwnerOnly {
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+// Apply записывает изменения в заметку
+func (c NoteChanges) Apply(n *models.Note) {
+	for _, f := range NoteFields {
+		if value, ok := c[f.Name]; ok {
+			f.Set(n, value)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"NotesService/internal/models"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// notPatchable — поля models.Note, которые нельзя изменить через PATCH.
+// Новое поле заметки нужно добавить либо в NoteFields, либо сюда
+var notPatchable = map[string]bool{
+	"id":         true,
+	"user_id":    true,
+	"version":    true,
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+}
+
+func TestNoteFieldsCoverNote(t *testing.T) {
+	rt := reflect.TypeFor[models.Note]()
+	names := make(map[string]bool, rt.NumField())
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			t.Errorf("models.Note.%s has no json name", field.Name)
+			continue
+		}
+		names[name] = true
+
+		_, patchable := LookupNoteField(name)
+		switch {
+		case patchable && notPatchable[name]:
+			t.Errorf("field %q is both in NoteFields and in notPatchable", name)
+		case !patchable && !notPatchable[name]:
+			t.Errorf("field %q is neither in NoteFields nor in notPatchable", name)
+		}
+	}
+
+	for _, f := range NoteFields {
+		if !names[f.Name] {
+			t.Errorf("NoteFields has %q, which is not a json field of models.Note", f.Name)
+		}
+	}
+}
+
+func TestNoteFields(t *testing.T) {
+	notebookID := int64(3)
+
+	tests := []struct {
+		name    string
+		field   string
+		raw     string // пусто — ключа нет в документе
+		want    any
+		invalid bool
+	}{
+		{name: "title trimmed", field: NoteFieldTitle, raw: `"  hello "`, want: "hello"},
+		{name: "title blank", field: NoteFieldTitle, raw: `"   "`, want: "", invalid: true},
+		{name: "title missing", field: NoteFieldTitle, want: "", invalid: true},
+		{name: "content", field: NoteFieldContent, raw: `"text"`, want: "text"},
+		{name: "tags normalized", field: NoteFieldTags, raw: `[" Work ","work","home"]`, want: []string{"home", "work"}},
+		{name: "tags null", field: NoteFieldTags, raw: `null`, want: []string{}},
+		{name: "tags too long", field: NoteFieldTags, raw: `["` + strings.Repeat("a", 51) + `"]`, want: []string{strings.Repeat("a", 51)}, invalid: true},
+		{name: "notebook", field: NoteFieldNotebookID, raw: `3`, want: &notebookID},
+		{name: "notebook null", field: NoteFieldNotebookID, raw: `null`, want: (*int64)(nil)},
+		{name: "notebook zero", field: NoteFieldNotebookID, raw: `0`, want: new(int64), invalid: true},
+	}
+
+	validate := validator.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, ok := LookupNoteField(tt.field)
+			if !ok {
+				t.Fatalf("field %q is not registered", tt.field)
+			}
+
+			var raw json.RawMessage
+			if tt.raw != "" {
+				raw = json.RawMessage(tt.raw)
+			}
+			got, err := f.Decode(raw)
+			if err != nil {
+				t.Fatalf("Decode(%s): %v", tt.raw, err)
+			}
+			if !f.Equal(got, tt.want) {
+				t.Errorf("Decode(%s) = %v, want %v", tt.raw, got, tt.want)
+			}
+
+			if err := validate.Var(got, f.Validate); (err != nil) != tt.invalid {
+				t.Errorf("validate %v: got error %v, want invalid %v", got, err, tt.invalid)
+			}
+		})
+	}
+}
+
+func TestNoteChangesApply(t *testing.T) {
+	notebookID := int64(7)
+	note := &models.Note{Title: "old", Content: "content", Tags: []string{"a"}, NotebookID: &notebookID}
+
+	doc := NoteDocument(note)
+	NoteChanges{NoteFieldTitle: "new", NoteFieldNotebookID: (*int64)(nil)}.Apply(note)
+
+	if note.Title != "new" || note.Content != "content" || note.NotebookID != nil {
+		t.Errorf("Apply: got %+v", note)
+	}
+	if doc[NoteFieldTitle] != "old" || *doc[NoteFieldNotebookID].(*int64) != 7 {
+		t.Errorf("NoteDocument changed after Apply: %v", doc)
+	}
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// MaxTitleFilterLength — максимальная длина подстроки для отбора по заголовку
+const MaxTitleFilterLength = 200
+
+// NoteFilter — условия отбора заметок для GetAllNotes
+type NoteFilter struct {
+	// Теги, по которым отбираются заметки (пустой список — без отбора)
+	Tags []string
+	// true — у заметки должны быть все теги (AND), false — хотя бы один (OR)
+	MatchAllTags bool
+	// Блокнот, из которого отбираются заметки (nil — без отбора)
+	NotebookID *int64
+	// true — вместе с заметками из вложенных блокнотов
+	Recursive bool
+
+	// Диапазоны дат создания и изменения: From включается, To — нет (nil — без ограничения)
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+
+	// Заголовок начинается с / содержит подстроку (без учёта регистра, пусто — без отбора)
+	TitlePrefix   string
+	TitleContains string
+
+	// Границы длины содержимого в символах, включительно (nil — без ограничения)
+	MinContentLength *int
+	MaxContentLength *int
+}
+
+// Validate проверяет согласованность условий фильтра
+func (f NoteFilter) Validate() error {
+	if f.CreatedFrom != nil && f.CreatedTo != nil && !f.CreatedFrom.Before(*f.CreatedTo) {
+		return errors.New("created_from must be before created_to")
+	}
+	if f.UpdatedFrom != nil && f.UpdatedTo != nil && !f.UpdatedFrom.Before(*f.UpdatedTo) {
+		return errors.New("updated_from must be before updated_to")
+	}
+	if len(f.TitlePrefix) > MaxTitleFilterLength || len(f.TitleContains) > MaxTitleFilterLength {
+		return errors.New("title filter is too long")
+	}
+	if f.MinContentLength != nil && *f.MinContentLength < 0 {
+		return errors.New("min_length must not be negative")
+	}
+	if f.MaxContentLength != nil && *f.MaxContentLength < 0 {
+		return errors.New("max_length must not be negative")
+	}
+	if f.MinContentLength != nil && f.MaxContentLength != nil && *f.MinContentLength > *f.MaxContentLength {
+		return errors.New("min_length must not exceed max_length")
+	}
+	return nil
+}
+
+// NormalizeTags приводит теги к единому виду: обрезает пробелы, переводит
+// в нижний регистр, убирает пустые и повторяющиеся. nil остаётся nil —
+// так PutNote отличает "теги не переданы" от "удалить все теги"
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"html"
+	"strings"
+)
+
+// Границы найденных слов в NoteSearchResult.TitleHighlight и Snippet. Реализации поиска
+// возвращают текст заметки как есть, поэтому вместо <mark> используются символы из области
+// Unicode для частного использования: их не спутать с разметкой, написанной в самой заметке
+const (
+	HighlightStart = "\uE000"
+	HighlightStop  = "\uE001"
+)
+
+var highlightReplacer = strings.NewReplacer(HighlightStart, "<mark>", HighlightStop, "</mark>")
+
+// Highlight экранирует текст для HTML и заменяет границы найденных слов на <mark></mark>.
+// Результат можно вставлять в страницу как HTML: других тегов в нём нет
+func Highlight(text string) string {
+	return highlightReplacer.Replace(html.EscapeString(text))
+}
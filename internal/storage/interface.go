@@ -1,15 +1,98 @@
 package storage
 
-import "NotesService/internal/models"
+import (
+	"NotesService/internal/models"
+	"context"
+	"time"
+)
+
+// Storage объединяет все хранилища сервиса. Реализации: postgresql, sqlite и memory
+type Storage interface {
+	NoteStorage
+	TrashStorage
+	RevisionStorage
+	NotebookStorage
+	ShareStorage
+	LinkStorage
+	UserStorage
+	TokenStorage
+
+	// Close освобождает ресурсы хранилища (соединения с базой). Вызывается при остановке
+	// сервиса, когда обработчики и фоновые задачи уже завершены
+	Close() error
+}
 
 type NoteStorage interface {
-	SaveNotes(title string, content string, idUser int64) (*models.Note, int64, error)
-	GetAllNotes(idUser int64, limit, offset, sort string) ([]*models.Note, error)
-	GetOneNote(idUser int64, idNote int64) (*models.Note, error)
-	PutNote(idUser int64, idNote int64, title string, content string) (*models.Note, error)
-	DeleteNote(idUser int64, idNote int64) error
+	SaveNotes(ctx context.Context, title string, content string, idUser int64, tags []string, notebookID *int64) (*models.Note, int64, error)
+	GetAllNotes(ctx context.Context, idUser int64, page NotePage, filter NoteFilter) ([]*models.Note, error)
+	CountNotes(ctx context.Context, idUser int64, filter NoteFilter) (int64, error)
+	GetOneNote(ctx context.Context, idUser int64, idNote int64) (*models.Note, error)
+	// tags == nil оставляет теги заметки без изменений, notebookID == nil — блокнот,
+	// *notebookID == 0 переносит заметку из блокнота. version — ожидаемая версия
+	// заметки (0 — без проверки), при несовпадении возвращается ErrVersionMismatch
+	PutNote(ctx context.Context, idUser int64, idNote int64, title string, content string, tags []string, notebookID *int64, version int64) (*models.Note, error)
+	PatchNote(ctx context.Context, idUser int64, idNote int64, changes NoteChanges, version int64) (*models.Note, error)
+	DeleteNote(ctx context.Context, idUser int64, idNote int64, version int64) error
+	GetTags(ctx context.Context, idUser int64) ([]*models.Tag, error)
+	SearchNotes(ctx context.Context, idUser int64, query string, limit, offset int) ([]*models.NoteSearchResult, error)
+}
+
+type TrashStorage interface {
+	GetTrash(ctx context.Context, idUser int64) ([]*models.Note, error)
+	RestoreNote(ctx context.Context, idUser int64, idNote int64) (*models.Note, error)
+	EmptyTrash(ctx context.Context, idUser int64) (int64, error)
+	PurgeTrash(ctx context.Context, before time.Time) (int64, error)
+}
+
+type RevisionStorage interface {
+	GetNoteRevisions(ctx context.Context, idUser int64, idNote int64) ([]*models.NoteRevision, error)
+	GetNoteRevision(ctx context.Context, idUser int64, idNote int64, revision int64) (*models.NoteRevision, error)
+	RestoreNoteRevision(ctx context.Context, idUser int64, idNote int64, revision int64) (*models.Note, error)
+	PruneNoteRevisions(ctx context.Context, maxCount int, before time.Time) (int64, error)
+}
+
+type NotebookStorage interface {
+	CreateNotebook(ctx context.Context, idUser int64, name string, parentID *int64) (*models.Notebook, error)
+	GetNotebooks(ctx context.Context, idUser int64) ([]*models.Notebook, error)
+	GetNotebook(ctx context.Context, idUser int64, idNotebook int64) (*models.Notebook, error)
+	// Перенос блокнота в другого родителя переносит всё его поддерево
+	PutNotebook(ctx context.Context, idUser int64, idNotebook int64, name string, parentID *int64) (*models.Notebook, error)
+	DeleteNotebook(ctx context.Context, idUser int64, idNotebook int64) error
+}
+
+type ShareStorage interface {
+	ShareNote(ctx context.Context, idOwner int64, idNote int64, idUser int64, permission string) (*models.NoteShare, error)
+	UnshareNote(ctx context.Context, idOwner int64, idNote int64, idUser int64) error
+	GetNoteShares(ctx context.Context, idOwner int64, idNote int64) ([]*models.NoteShare, error)
+	GetSharedNotes(ctx context.Context, idUser int64) ([]*models.SharedNote, error)
+	GetSharePermission(ctx context.Context, idNote int64, idUser int64) (string, error)
+}
+
+type LinkStorage interface {
+	CreateNoteLink(ctx context.Context, idOwner int64, idNote int64, tokenHash string, passwordHash string, expiresAt *time.Time) (*models.NoteLink, error)
+	GetNoteLinks(ctx context.Context, idOwner int64, idNote int64) ([]*models.NoteLink, error)
+	RevokeNoteLink(ctx context.Context, idOwner int64, idNote int64, idLink int64) error
+	GetNoteLink(ctx context.Context, tokenHash string) (*models.NoteLink, error)
+	ViewNoteLink(ctx context.Context, idLink int64) (*models.Note, int64, error)
 }
 
 type UserStorage interface {
-	RegisterUser(userName string) (*models.User, error)
+	RegisterUser(ctx context.Context, userName string, passwordHash string) (*models.User, error)
+	GetUserByName(ctx context.Context, userName string) (*models.User, error)
+	GetUserByID(ctx context.Context, idUser int64) (*models.User, error)
+	// SetUserPassword заменяет хеш пароля пользователя (команда app users set-password)
+	SetUserPassword(ctx context.Context, idUser int64, passwordHash string) error
+}
+
+type TokenStorage interface {
+	SaveRefreshToken(ctx context.Context, idUser int64, tokenHash string, familyID string, expiresAt time.Time) error
+	RotateRefreshToken(ctx context.Context, oldHash string, newHash string, expiresAt time.Time) (*models.RefreshToken, error)
+	RevokeRefreshTokenFamily(ctx context.Context, idUser int64, tokenHash string) error
+	RevokeUserRefreshTokens(ctx context.Context, idUser int64) error
+
+	RevokeToken(ctx context.Context, jti string, idUser int64, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	GetTokenGeneration(ctx context.Context, idUser int64) (int64, error)
+	IncrementTokenGeneration(ctx context.Context, idUser int64) (int64, error)
+	PurgeExpiredTokens(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+func (s *Storage) CreateNoteLink(_ context.Context, idOwner int64, idNote int64, tokenHash string, passwordHash string, expiresAt *time.Time) (*models.NoteLink, error) {
+	const op = "storage.memory.CreateNoteLink"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.activeNote(idOwner, idNote); !ok {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+	}
+	if _, ok := s.linkByHash[tokenHash]; ok {
+		return nil, fmt.Errorf("%s: token hash already exists", op)
+	}
+
+	s.lastLinkID++
+
+	link := &models.NoteLink{
+		ID:           s.lastLinkID,
+		NoteID:       idNote,
+		TokenHash:    tokenHash,
+		PasswordHash: passwordHash,
+		ExpiresAt:    copyTime(expiresAt),
+		CreatedAt:    now(),
+	}
+	s.links[link.ID] = link
+	s.linkByHash[tokenHash] = link.ID
+
+	return copyLink(link), nil
+}
+
+func (s *Storage) GetNoteLinks(_ context.Context, idOwner int64, idNote int64) ([]*models.NoteLink, error) {
+	const op = "storage.memory.GetNoteLinks"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if n, ok := s.notes[idNote]; !ok || n.UserID != idOwner {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+	}
+
+	links := []*models.NoteLink{}
+	for _, link := range s.links {
+		if link.NoteID == idNote {
+			links = append(links, copyLink(link))
+		}
+	}
+
+	slices.SortFunc(links, func(a, b *models.NoteLink) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return links, nil
+}
+
+func (s *Storage) RevokeNoteLink(_ context.Context, idOwner int64, idNote int64, idLink int64) error {
+	const op = "storage.memory.RevokeNoteLink"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, ok := s.links[idLink]
+	if !ok || link.NoteID != idNote || s.notes[idNote].UserID != idOwner {
+		return fmt.Errorf("%s: %w", op, storageErr.ErrLinkNotFound)
+	}
+
+	if link.RevokedAt == nil {
+		t := now()
+		link.RevokedAt = &t
+	}
+
+	return nil
+}
+
+func (s *Storage) GetNoteLink(_ context.Context, tokenHash string) (*models.NoteLink, error) {
+	const op = "storage.memory.GetNoteLink"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.linkByHash[tokenHash]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrLinkNotFound)
+	}
+
+	return copyLink(s.links[id]), nil
+}
+
+// ViewNoteLink увеличивает счётчик просмотров действующей ссылки
+// и возвращает заметку вместе с новым значением счётчика
+func (s *Storage) ViewNoteLink(_ context.Context, idLink int64) (*models.Note, int64, error) {
+	const op = "storage.memory.ViewNoteLink"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, ok := s.links[idLink]
+	if !ok || link.RevokedAt != nil || (link.ExpiresAt != nil && !link.ExpiresAt.After(now())) {
+		return nil, 0, fmt.Errorf("%s: %w", op, storageErr.ErrLinkNotFound)
+	}
+	n, ok := s.notes[link.NoteID]
+	if !ok || n.DeletedAt != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, storageErr.ErrLinkNotFound)
+	}
+
+	link.ViewCount++
+
+	return &models.Note{
+		ID:        n.ID,
+		UserID:    n.UserID,
+		Title:     n.Title,
+		Content:   n.Content,
+		CreatedAt: n.CreatedAt,
+		UpdatedAt: n.UpdatedAt,
+	}, link.ViewCount, nil
+}
+
+func copyLink(link *models.NoteLink) *models.NoteLink {
+	c := *link
+	c.ExpiresAt = copyTime(link.ExpiresAt)
+	c.RevokedAt = copyTime(link.RevokedAt)
+	return &c
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
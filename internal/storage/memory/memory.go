@@ -0,0 +1,117 @@
+// Package memory — хранилище в памяти процесса для локальной разработки и тестов.
+// Семантика совпадает с postgresql: те же ошибки из storageErr, порядок и пагинация.
+// Данные теряются при перезапуске. Контекст вызова не используется: операции не
+// обращаются к сети и диску и выполняются под мьютексом за микросекунды
+package memory
+
+import (
+	"NotesService/internal/models"
+	"slices"
+	"sync"
+	"time"
+)
+
+type Storage struct {
+	mu sync.RWMutex
+
+	// Последние выданные id, как у BIGSERIAL
+	lastUserID, lastNoteID, lastNotebookID, lastTagID, lastLinkID, lastRefreshID int64
+
+	users       map[int64]*user
+	userByName  map[string]int64
+	notes       map[int64]*note
+	notebooks   map[int64]*models.Notebook
+	tags        map[int64]map[string]int64 // user_id -> name -> tag_id
+	shares      map[shareKey]*models.NoteShare
+	links       map[int64]*models.NoteLink
+	linkByHash  map[string]int64
+	refresh     map[int64]*models.RefreshToken
+	refreshHash map[string]int64
+	revoked     map[string]time.Time // jti -> expires_at
+}
+
+type user struct {
+	models.User
+	tokenGeneration int64
+}
+
+type note struct {
+	models.Note
+	lastRevision int64
+	revisions    []models.NoteRevision
+}
+
+type shareKey struct {
+	noteID int64
+	userID int64
+}
+
+func New() *Storage {
+	return &Storage{
+		users:       make(map[int64]*user),
+		userByName:  make(map[string]int64),
+		notes:       make(map[int64]*note),
+		notebooks:   make(map[int64]*models.Notebook),
+		tags:        make(map[int64]map[string]int64),
+		shares:      make(map[shareKey]*models.NoteShare),
+		links:       make(map[int64]*models.NoteLink),
+		linkByHash:  make(map[string]int64),
+		refresh:     make(map[int64]*models.RefreshToken),
+		refreshHash: make(map[string]int64),
+		revoked:     make(map[string]time.Time),
+	}
+}
+
+// Close ничего не делает: данные хранилища освобождаются вместе с процессом
+func (s *Storage) Close() error {
+	return nil
+}
+
+// now возвращает текущее время с точностью PostgreSQL (микросекунды)
+func now() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
+// activeNote возвращает заметку пользователя, не лежащую в корзине
+func (s *Storage) activeNote(idUser int64, idNote int64) (*note, bool) {
+	n, ok := s.notes[idNote]
+	if !ok || n.UserID != idUser || n.DeletedAt != nil {
+		return nil, false
+	}
+	return n, true
+}
+
+// copyNote возвращает копию заметки, которую вызывающий может менять
+func copyNote(n *note) *models.Note {
+	c := n.Note
+	c.Tags = slices.Clone(n.Tags)
+	if c.Tags == nil {
+		c.Tags = []string{}
+	}
+	if n.NotebookID != nil {
+		id := *n.NotebookID
+		c.NotebookID = &id
+	}
+	if n.DeletedAt != nil {
+		t := *n.DeletedAt
+		c.DeletedAt = &t
+	}
+	return &c
+}
+
+// deleteNote окончательно удаляет заметку вместе с доступами, ссылками и историей
+func (s *Storage) deleteNote(idNote int64) {
+	delete(s.notes, idNote)
+
+	for key := range s.shares {
+		if key.noteID == idNote {
+			delete(s.shares, key)
+		}
+	}
+	for id, link := range s.links {
+		if link.NoteID == idNote {
+			delete(s.linkByHash, link.TokenHash)
+			delete(s.links, id)
+		}
+	}
+}
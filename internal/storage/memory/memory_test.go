@@ -0,0 +1,12 @@
+package memory_test
+
+import (
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/memory"
+	"NotesService/internal/storage/storagetest"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(*testing.T) storage.Storage { return memory.New() })
+}
@@ -0,0 +1,172 @@
+package memory
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CreateNotebook создаёт блокнот. Родительский блокнот должен принадлежать тому же пользователю
+func (s *Storage) CreateNotebook(_ context.Context, idUser int64, name string, parentID *int64) (*models.Notebook, error) {
+	const op = "storage.memory.CreateNotebook"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if parentID != nil {
+		if err := s.checkNotebook(idUser, *parentID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	t := now()
+	s.lastNotebookID++
+
+	notebook := &models.Notebook{
+		ID:        s.lastNotebookID,
+		UserID:    idUser,
+		ParentID:  copyID(parentID),
+		Name:      name,
+		CreatedAt: t,
+		UpdatedAt: t,
+	}
+	s.notebooks[notebook.ID] = notebook
+
+	return copyNotebook(notebook), nil
+}
+
+func (s *Storage) GetNotebooks(_ context.Context, idUser int64) ([]*models.Notebook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	notebooks := []*models.Notebook{}
+	for _, notebook := range s.notebooks {
+		if notebook.UserID == idUser {
+			notebooks = append(notebooks, copyNotebook(notebook))
+		}
+	}
+
+	slices.SortFunc(notebooks, func(a, b *models.Notebook) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return notebooks, nil
+}
+
+func (s *Storage) GetNotebook(_ context.Context, idUser int64, idNotebook int64) (*models.Notebook, error) {
+	const op = "storage.memory.GetNotebook"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	notebook, ok := s.notebooks[idNotebook]
+	if !ok || notebook.UserID != idUser {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNotebookNotFound)
+	}
+
+	return copyNotebook(notebook), nil
+}
+
+// PutNotebook переименовывает блокнот и переносит его к новому родителю вместе с поддеревом
+func (s *Storage) PutNotebook(_ context.Context, idUser int64, idNotebook int64, name string, parentID *int64) (*models.Notebook, error) {
+	const op = "storage.memory.PutNotebook"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if parentID != nil {
+		if err := s.checkNotebook(idUser, *parentID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if s.notebookSubtree(idUser, idNotebook)[*parentID] {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNotebookCycle)
+		}
+	}
+
+	notebook, ok := s.notebooks[idNotebook]
+	if !ok || notebook.UserID != idUser {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNotebookNotFound)
+	}
+
+	notebook.Name = name
+	notebook.ParentID = copyID(parentID)
+	notebook.UpdatedAt = now()
+
+	return copyNotebook(notebook), nil
+}
+
+// DeleteNotebook удаляет блокнот вместе с вложенными блокнотами.
+// Заметки из них не удаляются, а остаются вне блокнотов
+func (s *Storage) DeleteNotebook(_ context.Context, idUser int64, idNotebook int64) error {
+	const op = "storage.memory.DeleteNotebook"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subtree := s.notebookSubtree(idUser, idNotebook)
+	if len(subtree) == 0 {
+		return fmt.Errorf("%s: %w", op, storageErr.ErrNotebookNotFound)
+	}
+
+	for id := range subtree {
+		delete(s.notebooks, id)
+	}
+	for _, n := range s.notes {
+		if n.NotebookID != nil && subtree[*n.NotebookID] {
+			n.NotebookID = nil
+		}
+	}
+
+	return nil
+}
+
+// checkNotebook проверяет, что блокнот idNotebook принадлежит пользователю idUser
+func (s *Storage) checkNotebook(idUser int64, idNotebook int64) error {
+	notebook, ok := s.notebooks[idNotebook]
+	if !ok || notebook.UserID != idUser {
+		return storageErr.ErrNotebookNotFound
+	}
+	return nil
+}
+
+// notebookSubtree возвращает id блокнота и всех вложенных в него. Пустой результат —
+// блокнота нет или он принадлежит другому пользователю
+func (s *Storage) notebookSubtree(idUser int64, idNotebook int64) map[int64]bool {
+	subtree := map[int64]bool{}
+	if s.checkNotebook(idUser, idNotebook) != nil {
+		return subtree
+	}
+
+	subtree[idNotebook] = true
+	for queue := []int64{idNotebook}; len(queue) > 0; queue = queue[1:] {
+		for _, notebook := range s.notebooks {
+			if notebook.ParentID != nil && *notebook.ParentID == queue[0] && !subtree[notebook.ID] {
+				subtree[notebook.ID] = true
+				queue = append(queue, notebook.ID)
+			}
+		}
+	}
+
+	return subtree
+}
+
+func copyNotebook(notebook *models.Notebook) *models.Notebook {
+	c := *notebook
+	c.ParentID = copyID(notebook.ParentID)
+	return &c
+}
+
+func copyID(id *int64) *int64 {
+	if id == nil {
+		return nil
+	}
+	c := *id
+	return &c
+}
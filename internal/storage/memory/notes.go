@@ -0,0 +1,406 @@
+package memory
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+func (s *Storage) SaveNotes(_ context.Context, title string, content string, idUser int64, tags []string, notebookID *int64) (*models.Note, int64, error) {
+	const op = "storage.memory.SaveNotes"
+
+	if title == "" {
+		return nil, 0, fmt.Errorf("%s: title cannot be empty", op)
+	}
+	if content == "" {
+		return nil, 0, fmt.Errorf("%s: content cannot be empty", op)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[idUser]; !ok {
+		return nil, 0, fmt.Errorf("%s: %w", op, storageErr.ErrUserNotFound)
+	}
+	if notebookID != nil {
+		if err := s.checkNotebook(idUser, *notebookID); err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	t := now()
+	s.lastNoteID++
+
+	n := &note{Note: models.Note{
+		ID:        s.lastNoteID,
+		UserID:    idUser,
+		Title:     title,
+		Content:   content,
+		Version:   1,
+		CreatedAt: t,
+		UpdatedAt: t,
+	}}
+	if notebookID != nil {
+		id := *notebookID
+		n.NotebookID = &id
+	}
+	s.notes[n.ID] = n
+
+	if len(tags) > 0 {
+		s.setNoteTags(idUser, n, tags)
+	}
+
+	// Как и postgresql, возвращает теги в переданном порядке
+	saved := copyNote(n)
+	if len(tags) > 0 {
+		saved.Tags = slices.Clone(tags)
+	}
+
+	return saved, 0, nil
+}
+
+func (s *Storage) GetAllNotes(_ context.Context, idUser int64, page storage.NotePage, filter storage.NoteFilter) ([]*models.Note, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matched := s.filterNotes(idUser, filter)
+
+	// Для предыдущей страницы идём от курсора в обратную сторону, а затем разворачиваем результат
+	desc := page.Desc != page.Backward
+	compare := func(a, b *note) int {
+		c := compareKeys(storage.KeysetOf(&a.Note, page.Sort), storage.KeysetOf(&b.Note, page.Sort), page.Sort)
+		if desc {
+			return -c
+		}
+		return c
+	}
+	slices.SortFunc(matched, compare)
+
+	if page.After != nil {
+		matched = slices.DeleteFunc(matched, func(n *note) bool {
+			c := compareKeys(storage.KeysetOf(&n.Note, page.Sort), *page.After, page.Sort)
+			if desc {
+				return c >= 0
+			}
+			return c <= 0
+		})
+	} else if page.Offset > 0 {
+		matched = matched[min(page.Offset, len(matched)):]
+	}
+
+	if page.Limit >= 0 && len(matched) > page.Limit {
+		matched = matched[:page.Limit]
+	}
+
+	notes := make([]*models.Note, 0, len(matched))
+	for _, n := range matched {
+		notes = append(notes, copyNote(n))
+	}
+
+	if page.Backward {
+		slices.Reverse(notes)
+	}
+
+	return notes, nil
+}
+
+func (s *Storage) CountNotes(_ context.Context, idUser int64, filter storage.NoteFilter) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.filterNotes(idUser, filter))), nil
+}
+
+func (s *Storage) GetOneNote(_ context.Context, idUser int64, idNote int64) (*models.Note, error) {
+	const op = "storage.memory.GetOneNote"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	n, ok := s.activeNote(idUser, idNote)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+	}
+
+	return copyNote(n), nil
+}
+
+func (s *Storage) PutNote(_ context.Context, idUser int64, idNote int64, title string, content string, tags []string, notebookID *int64, version int64) (*models.Note, error) {
+	const op = "storage.memory.PutNote"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, err := s.lockNote(idUser, idNote, version)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Блокнот должен принадлежать владельцу заметки; 0 означает "вне блокнотов"
+	if notebookID != nil && *notebookID != 0 {
+		if err := s.checkNotebook(idUser, *notebookID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	saveNoteRevision(n, title, content)
+
+	n.Title = title
+	n.Content = content
+	if notebookID != nil {
+		setNotebook(n, *notebookID)
+	}
+	n.Version++
+	n.UpdatedAt = now()
+
+	if tags != nil {
+		s.setNoteTags(n.UserID, n, tags)
+	}
+
+	return copyNote(n), nil
+}
+
+func (s *Storage) PatchNote(_ context.Context, idUser int64, idNote int64, changes storage.NoteChanges, version int64) (*models.Note, error) {
+	const op = "storage.memory.PatchNote"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, err := s.lockNote(idUser, idNote, version)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if notebookID, ok := changes[storage.NoteFieldNotebookID].(*int64); ok && notebookID != nil {
+		if err := s.checkNotebook(idUser, *notebookID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// Предыдущая версия попадает в историю, если меняется заголовок или текст
+	patched := n.Note
+	changes.Apply(&patched)
+	saveNoteRevision(n, patched.Title, patched.Content)
+
+	for _, f := range storage.NoteFields {
+		value, ok := changes[f.Name]
+		switch {
+		case !ok:
+		case f.Name == storage.NoteFieldTags:
+			// Теги обновляют ещё и словарь тегов пользователя
+			s.setNoteTags(idUser, n, value.([]string))
+		default:
+			f.Set(&n.Note, value)
+		}
+	}
+
+	n.Version++
+	n.UpdatedAt = now()
+
+	return copyNote(n), nil
+}
+
+// DeleteNote переносит заметку в корзину. version == 0 — без проверки версии
+func (s *Storage) DeleteNote(_ context.Context, idUser int64, idNote int64, version int64) error {
+	const op = "storage.memory.DeleteNote"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, err := s.lockNote(idUser, idNote, version)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	t := now()
+	n.DeletedAt = &t
+
+	return nil
+}
+
+func (s *Storage) GetTags(_ context.Context, idUser int64) ([]*models.Tag, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int64)
+	for _, n := range s.notes {
+		if n.UserID != idUser || n.DeletedAt != nil {
+			continue
+		}
+		for _, tag := range n.Tags {
+			counts[tag]++
+		}
+	}
+
+	tags := make([]*models.Tag, 0, len(counts))
+	for name, count := range counts {
+		tags = append(tags, &models.Tag{ID: s.tags[idUser][name], Name: name, NoteCount: count})
+	}
+	slices.SortFunc(tags, func(a, b *models.Tag) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return tags, nil
+}
+
+// lockNote проверяет, что заметка существует и её версия совпадает с version (0 — без проверки)
+func (s *Storage) lockNote(idUser int64, idNote int64, version int64) (*note, error) {
+	n, ok := s.activeNote(idUser, idNote)
+	if !ok {
+		return nil, storageErr.ErrNoteNotFound
+	}
+	if version != 0 && n.Version != version {
+		return nil, storageErr.ErrVersionMismatch
+	}
+	return n, nil
+}
+
+// saveNoteRevision сохраняет текущую версию заметки в историю, если title или content меняются
+func saveNoteRevision(n *note, title string, content string) {
+	if n.Title == title && n.Content == content {
+		return
+	}
+
+	n.lastRevision++
+	n.revisions = append(n.revisions, models.NoteRevision{
+		NoteID:    n.ID,
+		Revision:  n.lastRevision,
+		Title:     n.Title,
+		Content:   n.Content,
+		CreatedAt: n.UpdatedAt,
+	})
+}
+
+// setNotebook переносит заметку в блокнот; 0 — из блокнота
+func setNotebook(n *note, idNotebook int64) {
+	if idNotebook == 0 {
+		n.NotebookID = nil
+		return
+	}
+	n.NotebookID = &idNotebook
+}
+
+// setNoteTags заменяет теги заметки. Отсутствующие у пользователя теги создаются,
+// теги, которые больше не используются ни одной заметкой, удаляются
+func (s *Storage) setNoteTags(idUser int64, n *note, tags []string) {
+	n.Tags = slices.Sorted(slices.Values(tags))
+	n.Tags = slices.Compact(n.Tags)
+
+	userTags, ok := s.tags[idUser]
+	if !ok {
+		userTags = make(map[string]int64)
+		s.tags[idUser] = userTags
+	}
+	for _, tag := range n.Tags {
+		if _, ok := userTags[tag]; !ok {
+			s.lastTagID++
+			userTags[tag] = s.lastTagID
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, other := range s.notes {
+		if other.UserID != idUser {
+			continue
+		}
+		for _, tag := range other.Tags {
+			used[tag] = true
+		}
+	}
+	for tag := range userTags {
+		if !used[tag] {
+			delete(userTags, tag)
+		}
+	}
+}
+
+// filterNotes отбирает заметки пользователя по filter, как noteFilterWhere в postgresql
+func (s *Storage) filterNotes(idUser int64, filter storage.NoteFilter) []*note {
+	var notebooks map[int64]bool
+	if filter.NotebookID != nil {
+		notebooks = map[int64]bool{*filter.NotebookID: true}
+		if filter.Recursive {
+			notebooks = s.notebookSubtree(idUser, *filter.NotebookID)
+		}
+	}
+
+	matched := []*note{}
+	for _, n := range s.notes {
+		if n.UserID != idUser || n.DeletedAt != nil {
+			continue
+		}
+
+		if len(filter.Tags) > 0 {
+			found := 0
+			for _, tag := range filter.Tags {
+				if slices.Contains(n.Tags, tag) {
+					found++
+				}
+			}
+			if found == 0 || (filter.MatchAllTags && found != len(filter.Tags)) {
+				continue
+			}
+		}
+
+		if notebooks != nil && (n.NotebookID == nil || !notebooks[*n.NotebookID]) {
+			continue
+		}
+
+		if !inRange(n.CreatedAt, filter.CreatedFrom, filter.CreatedTo) || !inRange(n.UpdatedAt, filter.UpdatedFrom, filter.UpdatedTo) {
+			continue
+		}
+
+		title := strings.ToLower(n.Title)
+		if filter.TitlePrefix != "" && !strings.HasPrefix(title, strings.ToLower(filter.TitlePrefix)) {
+			continue
+		}
+		if filter.TitleContains != "" && !strings.Contains(title, strings.ToLower(filter.TitleContains)) {
+			continue
+		}
+
+		length := utf8.RuneCountInString(n.Content)
+		if filter.MinContentLength != nil && length < *filter.MinContentLength {
+			continue
+		}
+		if filter.MaxContentLength != nil && length > *filter.MaxContentLength {
+			continue
+		}
+
+		matched = append(matched, n)
+	}
+
+	return matched
+}
+
+// inRange — from включается, to нет
+func inRange(t time.Time, from, to *time.Time) bool {
+	if from != nil && t.Before(*from) {
+		return false
+	}
+	if to != nil && !t.Before(*to) {
+		return false
+	}
+	return true
+}
+
+// compareKeys сравнивает позиции заметок по полю сортировки, а при равенстве — по id.
+// Заголовки сравниваются побайтово, а не по правилам сортировки (collation) базы
+func compareKeys(a, b storage.NoteKeyset, sort storage.NoteSort) int {
+	var c int
+	if sort == storage.NoteSortTitle {
+		c = strings.Compare(a.Title, b.Title)
+	} else {
+		c = a.Time.Compare(b.Time)
+	}
+	if c != 0 {
+		return c
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
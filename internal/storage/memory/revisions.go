@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// GetNoteRevisions возвращает сохранённые версии заметки, начиная с последней
+func (s *Storage) GetNoteRevisions(_ context.Context, idUser int64, idNote int64) ([]*models.NoteRevision, error) {
+	const op = "storage.memory.GetNoteRevisions"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	n, ok := s.activeNote(idUser, idNote)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+	}
+
+	revisions := make([]*models.NoteRevision, 0, len(n.revisions))
+	for i := len(n.revisions) - 1; i >= 0; i-- {
+		rev := n.revisions[i]
+		revisions = append(revisions, &rev)
+	}
+
+	return revisions, nil
+}
+
+func (s *Storage) GetNoteRevision(_ context.Context, idUser int64, idNote int64, revision int64) (*models.NoteRevision, error) {
+	const op = "storage.memory.GetNoteRevision"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rev, ok := s.findRevision(idUser, idNote, revision)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrRevisionNotFound)
+	}
+
+	return &rev, nil
+}
+
+// RestoreNoteRevision возвращает заметке title и content из сохранённой версии.
+// Текущая версия перед этим тоже попадает в историю, поэтому восстановление можно отменить
+func (s *Storage) RestoreNoteRevision(_ context.Context, idUser int64, idNote int64, revision int64) (*models.Note, error) {
+	const op = "storage.memory.RestoreNoteRevision"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rev, ok := s.findRevision(idUser, idNote, revision)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrRevisionNotFound)
+	}
+
+	n := s.notes[idNote]
+	saveNoteRevision(n, rev.Title, rev.Content)
+
+	n.Title = rev.Title
+	n.Content = rev.Content
+	n.Version++
+	n.UpdatedAt = now()
+
+	return copyNote(n), nil
+}
+
+// PruneNoteRevisions удаляет версии заметок сверх maxCount последних для каждой
+// заметки и версии, созданные раньше before. maxCount <= 0 и нулевое before
+// отключают соответствующее ограничение
+func (s *Storage) PruneNoteRevisions(_ context.Context, maxCount int, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pruned int64
+	for _, n := range s.notes {
+		kept := slices.DeleteFunc(n.revisions, func(rev models.NoteRevision) bool {
+			expired := (maxCount > 0 && rev.Revision <= n.lastRevision-int64(maxCount)) ||
+				(!before.IsZero() && rev.CreatedAt.Before(before))
+			if expired {
+				pruned++
+			}
+			return expired
+		})
+		n.revisions = kept
+	}
+
+	return pruned, nil
+}
+
+func (s *Storage) findRevision(idUser int64, idNote int64, revision int64) (models.NoteRevision, bool) {
+	n, ok := s.activeNote(idUser, idNote)
+	if !ok {
+		return models.NoteRevision{}, false
+	}
+
+	for _, rev := range n.revisions {
+		if rev.Revision == revision {
+			return rev, true
+		}
+	}
+
+	return models.NoteRevision{}, false
+}
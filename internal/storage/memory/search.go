@@ -0,0 +1,263 @@
+package memory
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Веса совпадений в заголовке и тексте, как setweight 'A' и 'B' в postgresql
+const (
+	titleWeight   = 1.0
+	contentWeight = 0.4
+)
+
+// term — условие поискового запроса: слова фразы (подряд), префикс для последнего слова
+// и отрицание. or — условие присоединяется к предыдущему через OR
+type term struct {
+	words  []string
+	prefix bool
+	negate bool
+	or     bool
+}
+
+// SearchNotes ищет заметки пользователя по заголовку и тексту. Запрос разбирается
+// так же, как в postgresql (слова, "фразы", префикс*, -исключение, or), но без
+// стемминга и с упрощённым ранжированием
+func (s *Storage) SearchNotes(_ context.Context, idUser int64, query string, limit int, offset int) ([]*models.NoteSearchResult, error) {
+	const op = "storage.memory.SearchNotes"
+
+	terms := parseQuery(query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrEmptySearchQuery)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := []*models.NoteSearchResult{}
+	for _, n := range s.notes {
+		if n.UserID != idUser || n.DeletedAt != nil {
+			continue
+		}
+
+		title, content := words(n.Title), words(n.Content)
+		if !matchQuery(terms, title, content) {
+			continue
+		}
+
+		var rank float64
+		for _, t := range terms {
+			if !t.negate {
+				rank += titleWeight*float64(countMatches(t, title)) + contentWeight*float64(countMatches(t, content))
+			}
+		}
+
+		note := copyNote(n)
+		results = append(results, &models.NoteSearchResult{
+			Note:           *note,
+			Rank:           rank,
+			TitleHighlight: highlight(n.Title, terms, 0),
+			Snippet:        highlight(n.Content, terms, 30),
+		})
+	}
+
+	slices.SortFunc(results, func(a, b *models.NoteSearchResult) int {
+		if c := cmp.Compare(b.Rank, a.Rank); c != 0 {
+			return c
+		}
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+
+	results = results[min(offset, len(results)):]
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// parseQuery разбирает поисковую строку по тем же правилам, что buildTSQuery в postgresql
+func parseQuery(input string) []term {
+	var (
+		terms  []term
+		joinOr bool
+	)
+
+	add := func(t term) {
+		if len(t.words) == 0 {
+			return
+		}
+		t.or = joinOr && len(terms) > 0
+		terms = append(terms, t)
+		joinOr = false
+	}
+
+	for len(input) > 0 {
+		input = strings.TrimLeftFunc(input, unicode.IsSpace)
+		if input == "" {
+			break
+		}
+
+		negate := false
+		if input[0] == '-' {
+			negate = true
+			input = input[1:]
+		}
+
+		if strings.HasPrefix(input, `"`) {
+			end := strings.IndexByte(input[1:], '"')
+			var phrase string
+			if end < 0 {
+				phrase, input = input[1:], ""
+			} else {
+				phrase, input = input[1:end+1], input[end+2:]
+			}
+			add(term{words: words(phrase), negate: negate})
+			continue
+		}
+
+		end := strings.IndexFunc(input, unicode.IsSpace)
+		var word string
+		if end < 0 {
+			word, input = input, ""
+		} else {
+			word, input = input[:end], input[end:]
+		}
+
+		if !negate && strings.EqualFold(word, "or") {
+			joinOr = len(terms) > 0
+			continue
+		}
+
+		add(term{words: words(word), prefix: strings.HasSuffix(word, "*"), negate: negate})
+	}
+
+	return terms
+}
+
+// words разбивает текст на слова в нижнем регистре по небуквенным символам
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), isSeparator)
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// matchQuery вычисляет запрос: AND связывает сильнее OR, как в tsquery
+func matchQuery(terms []term, title, content []string) bool {
+	group := true
+	for i, t := range terms {
+		if t.or && i > 0 {
+			if group {
+				return true
+			}
+			group = true
+		}
+
+		found := countMatches(t, title) > 0 || countMatches(t, content) > 0
+		group = group && found != t.negate
+	}
+	return group
+}
+
+// countMatches считает вхождения фразы t в текст
+func countMatches(t term, text []string) int {
+	count := 0
+	for i := 0; i+len(t.words) <= len(text); i++ {
+		if matchAt(t, text[i:]) {
+			count++
+		}
+	}
+	return count
+}
+
+func matchAt(t term, text []string) bool {
+	for j, w := range t.words {
+		if t.prefix && j == len(t.words)-1 {
+			if !strings.HasPrefix(text[j], w) {
+				return false
+			}
+		} else if text[j] != w {
+			return false
+		}
+	}
+	return true
+}
+
+// highlight обрамляет storage.HighlightStart и storage.HighlightStop слова текста, совпадающие с положительными условиями
+// запроса. maxWords > 0 ограничивает результат окном вокруг первого совпадения
+func highlight(text string, terms []term, maxWords int) string {
+	type span struct{ start, end int }
+
+	var spans []span
+	start := -1
+	for i, r := range text {
+		switch {
+		case !isSeparator(r) && start < 0:
+			start = i
+		case isSeparator(r) && start >= 0:
+			spans = append(spans, span{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, span{start, len(text)})
+	}
+
+	marked := make([]bool, len(spans))
+	lower := make([]string, len(spans))
+	for i, sp := range spans {
+		lower[i] = strings.ToLower(text[sp.start:sp.end])
+	}
+	for _, t := range terms {
+		if t.negate {
+			continue
+		}
+		for i := 0; i+len(t.words) <= len(lower); i++ {
+			if matchAt(t, lower[i:]) {
+				for j := range t.words {
+					marked[i+j] = true
+				}
+			}
+		}
+	}
+
+	from, to := 0, len(spans)
+	if maxWords > 0 && len(spans) > maxWords {
+		first := max(slices.Index(marked, true), 0)
+		from = max(min(first-maxWords/3, len(spans)-maxWords), 0)
+		to = from + maxWords
+	}
+	if from >= to {
+		return text
+	}
+
+	var b strings.Builder
+	pos := spans[from].start
+	if from == 0 {
+		pos = 0
+	}
+	for i := from; i < to; i++ {
+		b.WriteString(text[pos:spans[i].start])
+		word := text[spans[i].start:spans[i].end]
+		if marked[i] {
+			b.WriteString(storage.HighlightStart + word + storage.HighlightStop)
+		} else {
+			b.WriteString(word)
+		}
+		pos = spans[i].end
+	}
+	if to == len(spans) {
+		b.WriteString(text[pos:])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+)
+
+// ShareNote выдаёт пользователю idUser доступ к заметке владельца idOwner.
+// Если доступ уже был выдан, права заменяются
+func (s *Storage) ShareNote(_ context.Context, idOwner int64, idNote int64, idUser int64, permission string) (*models.NoteShare, error) {
+	const op = "storage.memory.ShareNote"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.activeNote(idOwner, idNote); !ok {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+	}
+	u, ok := s.users[idUser]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrUserNotFound)
+	}
+
+	key := shareKey{noteID: idNote, userID: idUser}
+	share, ok := s.shares[key]
+	if !ok {
+		share = &models.NoteShare{
+			NoteID:    idNote,
+			UserID:    idUser,
+			Username:  u.Username,
+			CreatedAt: now(),
+		}
+		s.shares[key] = share
+	}
+	share.Permission = permission
+
+	// Как и postgresql, ShareNote не возвращает имя пользователя
+	c := *share
+	c.Username = ""
+	return &c, nil
+}
+
+func (s *Storage) UnshareNote(_ context.Context, idOwner int64, idNote int64, idUser int64) error {
+	const op = "storage.memory.UnshareNote"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := shareKey{noteID: idNote, userID: idUser}
+	n, ok := s.notes[idNote]
+	if _, shared := s.shares[key]; !ok || n.UserID != idOwner || !shared {
+		return fmt.Errorf("%s: %w", op, storageErr.ErrShareNotFound)
+	}
+
+	delete(s.shares, key)
+
+	return nil
+}
+
+func (s *Storage) GetNoteShares(_ context.Context, idOwner int64, idNote int64) ([]*models.NoteShare, error) {
+	const op = "storage.memory.GetNoteShares"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if n, ok := s.notes[idNote]; !ok || n.UserID != idOwner {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+	}
+
+	shares := []*models.NoteShare{}
+	for key, share := range s.shares {
+		if key.noteID == idNote {
+			c := *share
+			shares = append(shares, &c)
+		}
+	}
+
+	slices.SortFunc(shares, func(a, b *models.NoteShare) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+
+	return shares, nil
+}
+
+// GetSharedNotes возвращает заметки других пользователей, к которым idUser выдан доступ
+func (s *Storage) GetSharedNotes(_ context.Context, idUser int64) ([]*models.SharedNote, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	notes := []*models.SharedNote{}
+	for key, share := range s.shares {
+		if key.userID != idUser {
+			continue
+		}
+		n, ok := s.notes[key.noteID]
+		if !ok || n.DeletedAt != nil {
+			continue
+		}
+
+		notes = append(notes, &models.SharedNote{
+			Note: models.Note{
+				ID:        n.ID,
+				UserID:    n.UserID,
+				Title:     n.Title,
+				Content:   n.Content,
+				CreatedAt: n.CreatedAt,
+				UpdatedAt: n.UpdatedAt,
+			},
+			OwnerName:  s.users[n.UserID].Username,
+			Permission: share.Permission,
+		})
+	}
+
+	slices.SortFunc(notes, func(a, b *models.SharedNote) int {
+		if c := b.UpdatedAt.Compare(a.UpdatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+
+	return notes, nil
+}
+
+func (s *Storage) GetSharePermission(_ context.Context, idNote int64, idUser int64) (string, error) {
+	const op = "storage.memory.GetSharePermission"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	share, ok := s.shares[shareKey{noteID: idNote, userID: idUser}]
+	if n, exists := s.notes[idNote]; !ok || !exists || n.DeletedAt != nil {
+		return "", fmt.Errorf("%s: %w", op, storageErr.ErrShareNotFound)
+	}
+
+	return share.Permission, nil
+}
@@ -0,0 +1,195 @@
+package memory
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+	"time"
+)
+
+func (s *Storage) SaveRefreshToken(_ context.Context, idUser int64, tokenHash string, familyID string, expiresAt time.Time) error {
+	const op = "storage.memory.SaveRefreshToken"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[idUser]; !ok {
+		return fmt.Errorf("%s: %w", op, storageErr.ErrUserNotFound)
+	}
+	if _, ok := s.refreshHash[tokenHash]; ok {
+		return fmt.Errorf("%s: token hash already exists", op)
+	}
+
+	s.addRefreshToken(idUser, tokenHash, familyID, expiresAt)
+
+	return nil
+}
+
+// RotateRefreshToken помечает старый токен использованным и выдаёт новый в том же семействе.
+// Повторное предъявление уже использованного токена отзывает всё семейство
+func (s *Storage) RotateRefreshToken(_ context.Context, oldHash string, newHash string, expiresAt time.Time) (*models.RefreshToken, error) {
+	const op = "storage.memory.RotateRefreshToken"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.refreshHash[oldHash]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrRefreshTokenNotFound)
+	}
+	old := s.refresh[id]
+
+	t := now()
+
+	if old.UsedAt != nil || old.RevokedAt != nil {
+		for _, token := range s.refresh {
+			if token.FamilyID == old.FamilyID && token.RevokedAt == nil {
+				revokedAt := t
+				token.RevokedAt = &revokedAt
+			}
+		}
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrRefreshTokenReused)
+	}
+
+	if time.Now().After(old.ExpiresAt) {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrRefreshTokenExpired)
+	}
+	if _, ok := s.refreshHash[newHash]; ok {
+		return nil, fmt.Errorf("%s: token hash already exists", op)
+	}
+
+	old.UsedAt = &t
+
+	token := s.addRefreshToken(old.UserID, newHash, old.FamilyID, expiresAt)
+
+	c := *token
+	return &c, nil
+}
+
+// RevokeRefreshTokenFamily отзывает все токены из семейства, к которому относится tokenHash
+func (s *Storage) RevokeRefreshTokenFamily(_ context.Context, idUser int64, tokenHash string) error {
+	const op = "storage.memory.RevokeRefreshTokenFamily"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.refreshHash[tokenHash]
+	if !ok || s.refresh[id].UserID != idUser {
+		return fmt.Errorf("%s: %w", op, storageErr.ErrRefreshTokenNotFound)
+	}
+
+	family := s.refresh[id].FamilyID
+	t := now()
+	for _, token := range s.refresh {
+		if token.FamilyID == family && token.RevokedAt == nil {
+			revokedAt := t
+			token.RevokedAt = &revokedAt
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) RevokeUserRefreshTokens(_ context.Context, idUser int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := now()
+	for _, token := range s.refresh {
+		if token.UserID == idUser && token.RevokedAt == nil {
+			revokedAt := t
+			token.RevokedAt = &revokedAt
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) RevokeToken(_ context.Context, jti string, idUser int64, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.revoked[jti]; !ok {
+		s.revoked[jti] = expiresAt
+	}
+
+	return nil
+}
+
+func (s *Storage) IsTokenRevoked(_ context.Context, jti string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, revoked := s.revoked[jti]
+	return revoked, nil
+}
+
+func (s *Storage) GetTokenGeneration(_ context.Context, idUser int64) (int64, error) {
+	const op = "storage.memory.GetTokenGeneration"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[idUser]
+	if !ok {
+		return 0, fmt.Errorf("%s: %w", op, storageErr.ErrUserNotFound)
+	}
+
+	return u.tokenGeneration, nil
+}
+
+func (s *Storage) IncrementTokenGeneration(_ context.Context, idUser int64) (int64, error) {
+	const op = "storage.memory.IncrementTokenGeneration"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[idUser]
+	if !ok {
+		return 0, fmt.Errorf("%s: %w", op, storageErr.ErrUserNotFound)
+	}
+
+	u.tokenGeneration++
+	return u.tokenGeneration, nil
+}
+
+// PurgeExpiredTokens удаляет отозванные access-токены и refresh-токены, срок жизни которых истёк
+func (s *Storage) PurgeExpiredTokens(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for jti, expiresAt := range s.revoked {
+		if expiresAt.Before(before) {
+			delete(s.revoked, jti)
+			purged++
+		}
+	}
+	for id, token := range s.refresh {
+		if token.ExpiresAt.Before(before) {
+			delete(s.refreshHash, token.TokenHash)
+			delete(s.refresh, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+func (s *Storage) addRefreshToken(idUser int64, tokenHash string, familyID string, expiresAt time.Time) *models.RefreshToken {
+	s.lastRefreshID++
+
+	token := &models.RefreshToken{
+		ID:        s.lastRefreshID,
+		UserID:    idUser,
+		TokenHash: tokenHash,
+		FamilyID:  familyID,
+		ExpiresAt: expiresAt,
+		CreatedAt: now(),
+	}
+	s.refresh[token.ID] = token
+	s.refreshHash[tokenHash] = token.ID
+
+	return token
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// GetTrash возвращает заметки пользователя из корзины, начиная с последних удалённых
+func (s *Storage) GetTrash(_ context.Context, idUser int64) ([]*models.Note, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	notes := []*models.Note{}
+	for _, n := range s.notes {
+		if n.UserID == idUser && n.DeletedAt != nil {
+			notes = append(notes, copyNote(n))
+		}
+	}
+
+	slices.SortFunc(notes, func(a, b *models.Note) int {
+		if c := b.DeletedAt.Compare(*a.DeletedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+
+	return notes, nil
+}
+
+// RestoreNote возвращает заметку из корзины
+func (s *Storage) RestoreNote(_ context.Context, idUser int64, idNote int64) (*models.Note, error) {
+	const op = "storage.memory.RestoreNote"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, ok := s.notes[idNote]
+	if !ok || n.UserID != idUser || n.DeletedAt == nil {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+	}
+
+	n.DeletedAt = nil
+
+	return copyNote(n), nil
+}
+
+// EmptyTrash окончательно удаляет все заметки пользователя из корзины
+func (s *Storage) EmptyTrash(_ context.Context, idUser int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for id, n := range s.notes {
+		if n.UserID == idUser && n.DeletedAt != nil {
+			s.deleteNote(id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// PurgeTrash окончательно удаляет заметки, попавшие в корзину раньше before
+func (s *Storage) PurgeTrash(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for id, n := range s.notes {
+		if n.DeletedAt != nil && n.DeletedAt.Before(before) {
+			s.deleteNote(id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+)
+
+func (s *Storage) RegisterUser(_ context.Context, userName string, passwordHash string) (*models.User, error) {
+	const op = "storage.memory.RegisterUser"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.userByName[userName]; ok {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrUserExists)
+	}
+
+	s.lastUserID++
+
+	u := &user{User: models.User{
+		ID:           s.lastUserID,
+		Username:     userName,
+		PasswordHash: passwordHash,
+		CreatedAt:    now(),
+	}}
+	s.users[u.ID] = u
+	s.userByName[userName] = u.ID
+
+	c := u.User
+	return &c, nil
+}
+
+func (s *Storage) GetUserByName(_ context.Context, userName string) (*models.User, error) {
+	const op = "storage.memory.GetUserByName"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.userByName[userName]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrUserNotFound)
+	}
+
+	c := s.users[id].User
+	return &c, nil
+}
+
+func (s *Storage) GetUserByID(_ context.Context, idUser int64) (*models.User, error) {
+	const op = "storage.memory.GetUserByID"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[idUser]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrUserNotFound)
+	}
+
+	c := u.User
+	return &c, nil
+}
+
+func (s *Storage) SetUserPassword(_ context.Context, idUser int64, passwordHash string) error {
+	const op = "storage.memory.SetUserPassword"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[idUser]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, storageErr.ErrUserNotFound)
+	}
+	u.PasswordHash = passwordHash
+
+	return nil
+}
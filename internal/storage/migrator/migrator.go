@@ -0,0 +1,349 @@
+// Package migrator применяет SQL миграции в формате goose к PostgreSQL и SQLite.
+// Применённые версии хранятся в таблице schema_migrations, а одновременный
+// запуск с нескольких экземпляров сервиса исключается блокировкой из Dialect
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"time"
+)
+
+// lockKey — ключ pg_advisory_lock, общий для всех экземпляров сервиса
+const lockKey int64 = 7236501284437109
+
+// Dialect — запросы, которые у СУБД различаются
+type Dialect struct {
+	name string
+	// lock и unlock выполняются на соединении мигратора; пустые — без блокировки
+	lock   string
+	unlock string
+	// createTable создаёт schema_migrations, если её ещё нет
+	createTable string
+}
+
+var (
+	Postgres = Dialect{
+		name:   "postgres",
+		lock:   fmt.Sprintf(`SELECT pg_advisory_lock(%d)`, lockKey),
+		unlock: fmt.Sprintf(`SELECT pg_advisory_unlock(%d)`, lockKey),
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations(
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP)`,
+	}
+
+	// SQLite: файл базы открывает один экземпляр сервиса, а запись в него и так
+	// выполняется по одной транзакции, поэтому отдельная блокировка не нужна
+	SQLite = Dialect{
+		name: "sqlite",
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations(
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000', 'now')))`,
+	}
+)
+
+// ErrNoApplied — откатывать нечего
+var ErrNoApplied = errors.New("no applied migrations")
+
+// Status — состояние миграции в базе
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	log        *slog.Logger
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+// New разбирает миграции из fsys. Ошибка в любом файле не даёт создать мигратор,
+// чтобы частично разобранный набор не был применён
+func New(log *slog.Logger, db *sql.DB, dialect Dialect, fsys fs.FS) (*Migrator, error) {
+	const op = "migrator.New"
+
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Migrator{
+		log:        log.With(slog.String("component", "migrator"), slog.String("dialect", dialect.name)),
+		db:         db,
+		dialect:    dialect,
+		migrations: migrations,
+	}, nil
+}
+
+// Up применяет все ещё не применённые миграции и возвращает их число
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	const op = "migrator.Up"
+
+	applied := 0
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := versions[mig.Version]; ok {
+				continue
+			}
+
+			if err := m.apply(ctx, conn, mig, mig.Up, true); err != nil {
+				return err
+			}
+			applied++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return applied, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return applied, nil
+}
+
+// Down откатывает последнюю применённую миграцию
+func (m *Migrator) Down(ctx context.Context) error {
+	const op = "migrator.Down"
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		mig, err := m.last(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		return m.apply(ctx, conn, mig, mig.Down, false)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Redo откатывает и заново применяет последнюю миграцию
+func (m *Migrator) Redo(ctx context.Context) error {
+	const op = "migrator.Redo"
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		mig, err := m.last(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if err := m.apply(ctx, conn, mig, mig.Down, false); err != nil {
+			return err
+		}
+
+		return m.apply(ctx, conn, mig, mig.Up, true)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Status возвращает все известные миграции и время их применения (nil — не применена)
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	const op = "migrator.Status"
+
+	var statuses []Status
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		statuses = make([]Status, 0, len(m.migrations))
+		for _, mig := range m.migrations {
+			status := Status{Version: mig.Version, Name: mig.Name}
+			if appliedAt, ok := versions[mig.Version]; ok {
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return statuses, nil
+}
+
+// Pending возвращает версии миграций, ещё не применённых к базе. В отличие от Status
+// не берёт блокировку и не создаёт schema_migrations, поэтому подходит для частых
+// проверок готовности, в том числе пока другой экземпляр применяет миграции
+func (m *Migrator) Pending(ctx context.Context) ([]int64, error) {
+	const op = "migrator.Pending"
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer conn.Close()
+
+	versions, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var pending []int64
+	for _, mig := range m.migrations {
+		if _, ok := versions[mig.Version]; !ok {
+			pending = append(pending, mig.Version)
+		}
+	}
+
+	return pending, nil
+}
+
+// withLock выполняет fn на отдельном соединении под блокировкой диалекта.
+// Блокировка сессионная, поэтому все запросы идут через одно соединение
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if m.dialect.lock != "" {
+		if _, err := conn.ExecContext(ctx, m.dialect.lock); err != nil {
+			return fmt.Errorf("acquire lock: %w", err)
+		}
+		defer func() {
+			// Контекст мог быть отменён, а блокировку нужно снять в любом случае
+			if _, err := conn.ExecContext(context.Background(), m.dialect.unlock); err != nil {
+				m.log.Error("failed to release migration lock", slog.String("error", err.Error()))
+			}
+		}()
+	}
+
+	if _, err := conn.ExecContext(ctx, m.dialect.createTable); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// last возвращает последнюю применённую миграцию
+func (m *Migrator) last(ctx context.Context, conn *sql.Conn) (Migration, error) {
+	var version int64
+	err := conn.QueryRowContext(ctx, `SELECT version FROM schema_migrations ORDER BY version DESC LIMIT 1`).Scan(&version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Migration{}, ErrNoApplied
+		}
+		return Migration{}, err
+	}
+
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig, nil
+		}
+	}
+
+	return Migration{}, fmt.Errorf("applied migration %d is not found in migration files", version)
+}
+
+// execer — общее у *sql.Conn и *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// apply выполняет запросы миграции и отмечает её применённой (up) или откатанной (down)
+// в той же транзакции, если миграция не помечена NO TRANSACTION
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration, statements []string, up bool) error {
+	direction := "down"
+	if up {
+		direction = "up"
+	}
+
+	run := func(exec execer) error {
+		for _, statement := range statements {
+			if _, err := exec.ExecContext(ctx, statement); err != nil {
+				return err
+			}
+		}
+
+		if up {
+			_, err := exec.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, mig.Version, mig.Name)
+			return err
+		}
+		_, err := exec.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version)
+		return err
+	}
+
+	start := time.Now()
+
+	var err error
+	if mig.NoTransaction {
+		err = run(conn)
+	} else {
+		err = inTx(ctx, conn, run)
+	}
+	if err != nil {
+		return fmt.Errorf("%s %d_%s: %w", direction, mig.Version, mig.Name, err)
+	}
+
+	m.log.Info("migration applied",
+		slog.String("direction", direction),
+		slog.Int64("version", mig.Version),
+		slog.String("name", mig.Name),
+		slog.Duration("duration", time.Since(start)),
+	)
+
+	return nil
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, fn func(exec execer) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make(map[int64]time.Time)
+	for rows.Next() {
+		var (
+			version   int64
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		versions[version] = appliedAt
+	}
+
+	return versions, rows.Err()
+}
@@ -0,0 +1,155 @@
+package migrator
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration — одна миграция: версия и имя берутся из имени файла 20261017120000_name.sql
+type Migration struct {
+	Version int64
+	Name    string
+	Up      []string
+	Down    []string
+	// -- +goose NO TRANSACTION: миграция выполняется вне транзакции
+	// (например, CREATE INDEX CONCURRENTLY)
+	NoTransaction bool
+}
+
+// load читает и разбирает все *.sql файлы из корня fsys, отсортированные по версии
+func load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(files))
+	seen := make(map[int64]string, len(files))
+
+	for _, file := range files {
+		versionStr, name, ok := strings.Cut(strings.TrimSuffix(path.Base(file), ".sql"), "_")
+		version, err := strconv.ParseInt(versionStr, 10, 64)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: file name must be <version>_<name>.sql", file)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migration %s: version %d is already used by %s", file, version, other)
+		}
+		seen[version] = file
+
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, err := parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", file, err)
+		}
+		m.Version = version
+		m.Name = name
+
+		migrations = append(migrations, m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// parse разбирает файл в формате goose. Блок между StatementBegin и StatementEnd
+// выполняется одним запросом, вне блоков запросы разделяются ';' в конце строки
+func parse(data []byte) (Migration, error) {
+	var (
+		m         Migration
+		section   *[]string
+		inBlock   bool
+		statement strings.Builder
+	)
+
+	flush := func() {
+		if s := strings.TrimSpace(statement.String()); hasSQL(s) && section != nil {
+			*section = append(*section, s)
+		}
+		statement.Reset()
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if annotation, ok := strings.CutPrefix(trimmed, "-- +goose "); ok {
+			switch strings.ToUpper(strings.TrimSpace(annotation)) {
+			case "UP":
+				flush()
+				section = &m.Up
+			case "DOWN":
+				flush()
+				section = &m.Down
+			case "STATEMENTBEGIN":
+				flush()
+				inBlock = true
+			case "STATEMENTEND":
+				if !inBlock {
+					return m, fmt.Errorf("StatementEnd without StatementBegin")
+				}
+				flush()
+				inBlock = false
+			case "NO TRANSACTION":
+				m.NoTransaction = true
+			default:
+				return m, fmt.Errorf("unknown annotation %q", trimmed)
+			}
+			continue
+		}
+
+		if section == nil {
+			if trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+				return m, fmt.Errorf("statement before -- +goose Up")
+			}
+			continue
+		}
+
+		statement.WriteString(line)
+		statement.WriteByte('\n')
+
+		if !inBlock && strings.HasSuffix(trimmed, ";") {
+			flush()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return m, err
+	}
+
+	if inBlock {
+		return m, fmt.Errorf("StatementBegin without StatementEnd")
+	}
+	flush()
+
+	if len(m.Up) == 0 {
+		return m, fmt.Errorf("no statements in -- +goose Up")
+	}
+
+	return m, nil
+}
+
+// hasSQL сообщает, есть ли в запросе что-то кроме комментариев
+func hasSQL(statement string) bool {
+	for line := range strings.Lines(statement) {
+		if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+			return true
+		}
+	}
+	return false
+}
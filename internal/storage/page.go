@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"NotesService/internal/models"
+	"time"
+)
+
+// NoteSort — поле сортировки списка заметок
+type NoteSort string
+
+const (
+	NoteSortCreatedAt NoteSort = "created_at"
+	NoteSortUpdatedAt NoteSort = "updated_at"
+	NoteSortTitle     NoteSort = "title"
+)
+
+// Valid сообщает, поддерживается ли поле сортировки
+func (s NoteSort) Valid() bool {
+	switch s {
+	case NoteSortCreatedAt, NoteSortUpdatedAt, NoteSortTitle:
+		return true
+	}
+	return false
+}
+
+// NoteKeyset — позиция заметки в отсортированном списке: значение поля сортировки и id.
+// Для сортировки по дате используется Time, по заголовку — Title
+type NoteKeyset struct {
+	Time  time.Time
+	Title string
+	ID    int64
+}
+
+// KeysetOf возвращает позицию заметки при сортировке sort
+func KeysetOf(note *models.Note, sort NoteSort) NoteKeyset {
+	key := NoteKeyset{ID: note.ID}
+
+	switch sort {
+	case NoteSortUpdatedAt:
+		key.Time = note.UpdatedAt
+	case NoteSortTitle:
+		key.Title = note.Title
+	default:
+		key.Time = note.CreatedAt
+	}
+
+	return key
+}
+
+// MaxPageLimit — наибольший размер страницы списков и поиска. Больший limit
+// уменьшается до него
+const MaxPageLimit = 100
+
+// NotePage — параметры выборки страницы списка заметок.
+// Если задан After, используется keyset-пагинация и Offset игнорируется
+type NotePage struct {
+	Limit  int
+	Offset int
+	Sort   NoteSort
+	Desc   bool
+	// Заметки строго после этой позиции в порядке сортировки
+	After *NoteKeyset
+	// Выбрать заметки перед After (предыдущая страница). Порядок результата при этом не меняется
+	Backward bool
+}
@@ -0,0 +1,25 @@
+package postgresql
+
+import (
+	"NotesService/internal/storage"
+	"context"
+	"fmt"
+)
+
+// CountNotes возвращает число заметок пользователя, подходящих под filter
+func (s *Storage) CountNotes(ctx context.Context, idUser int64, filter storage.NoteFilter) (int64, error) {
+	const op = "storage.postgresql.CountNotes"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	where := noteFilterWhere(idUser, filter)
+
+	var total int64
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notes n WHERE `+where.String(), where.args...).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return total, nil
+}
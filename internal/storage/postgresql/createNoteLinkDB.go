@@ -0,0 +1,42 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+func (s *Storage) CreateNoteLink(ctx context.Context, idOwner int64, idNote int64, tokenHash string, passwordHash string, expiresAt *time.Time) (*models.NoteLink, error) {
+	const op = "storage.postgresql.CreateNoteLink"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	link := &models.NoteLink{
+		TokenHash:    tokenHash,
+		PasswordHash: passwordHash,
+		ExpiresAt:    expiresAt,
+	}
+
+	err := s.db.QueryRowContext(ctx, `INSERT INTO note_links (note_id, token_hash, password_hash, expires_at)
+							 SELECT n.id, $3, $4, $5 FROM notes n
+							 WHERE n.user_id = $1 AND n.id = $2 AND n.deleted_at IS NULL
+							 RETURNING id, note_id, view_count, created_at`, idOwner, idNote, tokenHash, passwordHash, expiresAt).Scan(
+		&link.ID,
+		&link.NoteID,
+		&link.ViewCount,
+		&link.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return link, nil
+}
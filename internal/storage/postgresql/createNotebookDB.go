@@ -0,0 +1,41 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// CreateNotebook создаёт блокнот. Родительский блокнот должен принадлежать тому же пользователю
+func (s *Storage) CreateNotebook(ctx context.Context, idUser int64, name string, parentID *int64) (*models.Notebook, error) {
+	const op = "storage.postgresql.CreateNotebook"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	notebook := &models.Notebook{}
+
+	err := s.db.QueryRowContext(ctx, `INSERT INTO notebooks (user_id, parent_id, name)
+							 SELECT $1, $2, $3
+							 WHERE $2::bigint IS NULL
+							    OR EXISTS (SELECT 1 FROM notebooks WHERE user_id = $1 AND id = $2)
+							 RETURNING id, user_id, parent_id, name, created_at, updated_at`, idUser, parentID, name).Scan(
+		&notebook.ID,
+		&notebook.UserID,
+		&notebook.ParentID,
+		&notebook.Name,
+		&notebook.CreatedAt,
+		&notebook.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNotebookNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return notebook, nil
+}
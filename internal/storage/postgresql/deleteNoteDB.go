@@ -1,27 +1,37 @@
 package postgresql
 
 import (
-	"NotesService/internal/storage/storageErr"
+	"context"
 	"fmt"
 )
 
-func (s *Storage) DeleteNote(idUser int64, idNote int64) error {
+// DeleteNote переносит заметку в корзину. Окончательно заметка удаляется
+// при очистке корзины или по истечении срока хранения. version == 0 — без проверки версии
+func (s *Storage) DeleteNote(ctx context.Context, idUser int64, idNote int64, version int64) error {
 	const op = "storage.postgresql.DeleteNote"
 
-	res, err := s.db.Exec(`DELETE FROM notes 
-								WHERE user_id = $1 AND id = $2`, idUser, idNote)
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	defer tx.Rollback()
+
+	if err := lockNote(ctx, tx, idUser, idNote, version); err != nil {
+		return fmt.Errorf("%s: %w", op, queryErr(ctx, err))
 	}
 
-	//res.RowsAffected() - возвращает количество затронутых строк
-	rowsAffected, err := res.RowsAffected()
+	_, err = tx.ExecContext(ctx, `UPDATE notes
+						 SET deleted_at = CURRENT_TIMESTAMP
+						 WHERE id = $1`, idNote)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return fmt.Errorf("%s: %w", op, queryErr(ctx, err))
 	}
-	// если rowsAffected = 1, то было что то удалено, если 0, то ошибка
-	if rowsAffected == 0 {
-		return fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, queryErr(ctx, err))
 	}
 
 	return nil
@@ -0,0 +1,31 @@
+package postgresql
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+)
+
+// DeleteNotebook удаляет блокнот вместе с вложенными блокнотами.
+// Заметки из них не удаляются, а остаются вне блокнотов
+func (s *Storage) DeleteNotebook(ctx context.Context, idUser int64, idNotebook int64) error {
+	const op = "storage.postgresql.DeleteNotebook"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM notebooks WHERE user_id = $1 AND id = $2`, idUser, idNotebook)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storageErr.ErrNotebookNotFound)
+	}
+
+	return nil
+}
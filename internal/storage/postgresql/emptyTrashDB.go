@@ -0,0 +1,26 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+)
+
+// EmptyTrash окончательно удаляет все заметки пользователя из корзины
+func (s *Storage) EmptyTrash(ctx context.Context, idUser int64) (int64, error) {
+	const op = "storage.postgresql.EmptyTrash"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM notes WHERE user_id = $1 AND deleted_at IS NOT NULL`, idUser)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return deleted, nil
+}
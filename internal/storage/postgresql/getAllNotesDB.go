@@ -2,49 +2,73 @@ package postgresql
 
 import (
 	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"context"
 	"fmt"
-	"strconv"
-	"strings"
+	"slices"
+
+	"github.com/lib/pq"
 )
 
-func (s *Storage) GetAllNotes(idUser int64, limit string, offset string, sort string) ([]*models.Note, error) {
+// sortColumns — колонки, по которым разрешена сортировка. Значение NoteSort
+// никогда не подставляется в запрос напрямую
+var sortColumns = map[storage.NoteSort]string{
+	storage.NoteSortCreatedAt: "n.created_at",
+	storage.NoteSortUpdatedAt: "n.updated_at",
+	storage.NoteSortTitle:     "n.title",
+}
+
+func (s *Storage) GetAllNotes(ctx context.Context, idUser int64, page storage.NotePage, filter storage.NoteFilter) ([]*models.Note, error) {
 	const op = "storage.postgresql.GetAllNotes"
 
-	limitDefault := 10
-	offsetDefault := 0
+	ctx, end := s.begin(ctx, op)
+	defer end()
 
 	notes := []*models.Note{}
 
-	if limit != "" {
-		l, err := strconv.Atoi(limit)
-		if err == nil && l > 0 {
-			limitDefault = l
-		}
+	column, ok := sortColumns[page.Sort]
+	if !ok {
+		column = sortColumns[storage.NoteSortCreatedAt]
 	}
 
-	if offset != "" {
-		of, err := strconv.Atoi(offset)
-		if err == nil && of >= 0 {
-			offsetDefault = of
-		}
+	// Для предыдущей страницы идём от курсора в обратную сторону, а затем разворачиваем результат
+	desc := page.Desc != page.Backward
+	direction, cmp := "ASC", ">"
+	if desc {
+		direction, cmp = "DESC", "<"
 	}
-	if sort != "asc" && sort != "desc" {
-		sort = "desc"
+
+	where := noteFilterWhere(idUser, filter)
+
+	// Keyset: сравнение кортежей (поле, id) использует индекс и не пропускает строки,
+	// добавленные во время листания
+	pagination := ""
+	if page.After != nil {
+		var key any = page.After.Time
+		if page.Sort == storage.NoteSortTitle {
+			key = page.After.Title
+		}
+		where.and("(" + column + ", n.id) " + cmp + " (" + where.arg(key) + ", " + where.arg(page.After.ID) + ")")
+	} else if page.Offset > 0 {
+		pagination = " OFFSET " + where.arg(page.Offset)
 	}
 
+	pagination = "LIMIT " + where.arg(page.Limit) + pagination
+
+	// В текст запроса подставляются только константы: колонка из sortColumns, направление
+	// и условие из whereBuilder, все значения передаются аргументами
 	query := fmt.Sprintf(`
-	SELECT id, user_id, title, content, created_at, updated_at
-    FROM notes
-    WHERE user_id = $1
-    ORDER BY created_at %s
-    LIMIT $2
-    OFFSET $3
-`, strings.ToUpper(sort))
+	SELECT n.id, n.user_id, n.title, n.content, %s, n.notebook_id, n.version, n.created_at, n.updated_at
+    FROM notes n
+    WHERE %s
+    ORDER BY %s %s, n.id %s
+    %s
+`, noteTagsColumn, where.String(), column, direction, direction, pagination)
 
-	rows, err := s.db.Query(query, idUser, limitDefault, offsetDefault)
+	rows, err := s.db.QueryContext(ctx, query, where.args...)
 
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
 	}
 	defer rows.Close()
 
@@ -57,6 +81,9 @@ func (s *Storage) GetAllNotes(idUser int64, limit string, offset string, sort st
 			&note.UserID,
 			&note.Title,
 			&note.Content,
+			pq.Array(&note.Tags),
+			&note.NotebookID,
+			&note.Version,
 			&note.CreatedAt,
 			&note.UpdatedAt,
 		)
@@ -71,5 +98,9 @@ func (s *Storage) GetAllNotes(idUser int64, limit string, offset string, sort st
 		return nil, fmt.Errorf("%s: rows iteration: %w", op, err)
 	}
 
+	if page.Backward {
+		slices.Reverse(notes)
+	}
+
 	return notes, nil
 }
@@ -0,0 +1,61 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+)
+
+func (s *Storage) GetNoteLinks(ctx context.Context, idOwner int64, idNote int64) ([]*models.NoteLink, error) {
+	const op = "storage.postgresql.GetNoteLinks"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM notes WHERE user_id = $1 AND id = $2)`, idOwner, idNote).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT id, note_id, token_hash, password_hash, expires_at, revoked_at, view_count, created_at
+								FROM note_links
+								WHERE note_id = $1
+								ORDER BY created_at, id`, idNote)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	defer rows.Close()
+
+	links := []*models.NoteLink{}
+
+	for rows.Next() {
+		link := &models.NoteLink{}
+
+		err := rows.Scan(
+			&link.ID,
+			&link.NoteID,
+			&link.TokenHash,
+			&link.PasswordHash,
+			&link.ExpiresAt,
+			&link.RevokedAt,
+			&link.ViewCount,
+			&link.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		links = append(links, link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration: %w", op, err)
+	}
+
+	return links, nil
+}
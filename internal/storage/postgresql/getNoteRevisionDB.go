@@ -0,0 +1,38 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+func (s *Storage) GetNoteRevision(ctx context.Context, idUser int64, idNote int64, revision int64) (*models.NoteRevision, error) {
+	const op = "storage.postgresql.GetNoteRevision"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	rev := &models.NoteRevision{}
+
+	err := s.db.QueryRowContext(ctx, `SELECT r.note_id, r.revision, r.title, r.content, r.created_at
+							 FROM note_revisions r
+							 JOIN notes n ON n.id = r.note_id
+							 WHERE n.user_id = $1 AND r.note_id = $2 AND r.revision = $3 AND n.deleted_at IS NULL`, idUser, idNote, revision).Scan(
+		&rev.NoteID,
+		&rev.Revision,
+		&rev.Title,
+		&rev.Content,
+		&rev.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrRevisionNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return rev, nil
+}
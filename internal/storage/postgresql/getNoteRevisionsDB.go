@@ -0,0 +1,59 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+)
+
+// GetNoteRevisions возвращает сохранённые версии заметки, начиная с последней
+func (s *Storage) GetNoteRevisions(ctx context.Context, idUser int64, idNote int64) ([]*models.NoteRevision, error) {
+	const op = "storage.postgresql.GetNoteRevisions"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM notes WHERE user_id = $1 AND id = $2 AND deleted_at IS NULL)`, idUser, idNote).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT note_id, revision, title, content, created_at
+								FROM note_revisions
+								WHERE note_id = $1
+								ORDER BY revision DESC`, idNote)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	defer rows.Close()
+
+	revisions := []*models.NoteRevision{}
+
+	for rows.Next() {
+		revision := &models.NoteRevision{}
+
+		err := rows.Scan(
+			&revision.NoteID,
+			&revision.Revision,
+			&revision.Title,
+			&revision.Content,
+			&revision.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration: %w", op, err)
+	}
+
+	return revisions, nil
+}
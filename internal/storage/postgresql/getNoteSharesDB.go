@@ -0,0 +1,59 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+)
+
+func (s *Storage) GetNoteShares(ctx context.Context, idOwner int64, idNote int64) ([]*models.NoteShare, error) {
+	const op = "storage.postgresql.GetNoteShares"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM notes WHERE user_id = $1 AND id = $2)`, idOwner, idNote).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT sh.note_id, sh.user_id, u.user_name, sh.permission, sh.created_at
+								FROM note_shares sh
+								JOIN users u ON u.id = sh.user_id
+								WHERE sh.note_id = $1
+								ORDER BY sh.created_at`, idNote)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	defer rows.Close()
+
+	shares := []*models.NoteShare{}
+
+	for rows.Next() {
+		share := &models.NoteShare{}
+
+		err := rows.Scan(
+			&share.NoteID,
+			&share.UserID,
+			&share.Username,
+			&share.Permission,
+			&share.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		shares = append(shares, share)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration: %w", op, err)
+	}
+
+	return shares, nil
+}
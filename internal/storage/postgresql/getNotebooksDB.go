@@ -0,0 +1,49 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"context"
+	"fmt"
+)
+
+func (s *Storage) GetNotebooks(ctx context.Context, idUser int64) ([]*models.Notebook, error) {
+	const op = "storage.postgresql.GetNotebooks"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT id, user_id, parent_id, name, created_at, updated_at
+								FROM notebooks
+								WHERE user_id = $1
+								ORDER BY name, id`, idUser)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	defer rows.Close()
+
+	notebooks := []*models.Notebook{}
+
+	for rows.Next() {
+		notebook := &models.Notebook{}
+
+		err := rows.Scan(
+			&notebook.ID,
+			&notebook.UserID,
+			&notebook.ParentID,
+			&notebook.Name,
+			&notebook.CreatedAt,
+			&notebook.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		notebooks = append(notebooks, notebook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration: %w", op, err)
+	}
+
+	return notebooks, nil
+}
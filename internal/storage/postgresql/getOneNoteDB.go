@@ -3,16 +3,23 @@ package postgresql
 import (
 	"NotesService/internal/models"
 	"NotesService/internal/storage/storageErr"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+
+	"github.com/lib/pq"
 )
 
-func (s *Storage) GetOneNote(idUser int64, idNote int64) (*models.Note, error) {
+func (s *Storage) GetOneNote(ctx context.Context, idUser int64, idNote int64) (*models.Note, error) {
 	const op = "storage.postgresql.GetOneNote"
 
-	row := s.db.QueryRow(`SELECT id, user_id, title, content, created_at, updated_at
-									  FROM notes
-									  Where user_id = $1 AND id = $2`, idUser, idNote)
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	row := s.db.QueryRowContext(ctx, `SELECT n.id, n.user_id, n.title, n.content, `+noteTagsColumn+`, n.notebook_id, n.version, n.created_at, n.updated_at
+									  FROM notes n
+									  Where n.user_id = $1 AND n.id = $2 AND n.deleted_at IS NULL`, idUser, idNote)
 
 	note := &models.Note{}
 
@@ -21,13 +28,17 @@ func (s *Storage) GetOneNote(idUser int64, idNote int64) (*models.Note, error) {
 		&note.UserID,
 		&note.Title,
 		&note.Content,
+		pq.Array(&note.Tags),
+		&note.NotebookID,
+		&note.Version,
 		&note.CreatedAt,
 		&note.UpdatedAt,
 	)
 	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
+		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
 		}
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
 	}
 	return note, nil
 
@@ -0,0 +1,30 @@
+package postgresql
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+func (s *Storage) GetSharePermission(ctx context.Context, idNote int64, idUser int64) (string, error) {
+	const op = "storage.postgresql.GetSharePermission"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	var permission string
+
+	err := s.db.QueryRowContext(ctx, `SELECT sh.permission FROM note_shares sh
+							 JOIN notes n ON n.id = sh.note_id
+							 WHERE sh.note_id = $1 AND sh.user_id = $2 AND n.deleted_at IS NULL`, idNote, idUser).Scan(&permission)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", op, storageErr.ErrShareNotFound)
+		}
+		return "", fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return permission, nil
+}
@@ -0,0 +1,54 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"context"
+	"fmt"
+)
+
+// GetSharedNotes возвращает заметки других пользователей, к которым idUser выдан доступ
+func (s *Storage) GetSharedNotes(ctx context.Context, idUser int64) ([]*models.SharedNote, error) {
+	const op = "storage.postgresql.GetSharedNotes"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT n.id, n.user_id, u.user_name, n.title, n.content, sh.permission, n.created_at, n.updated_at
+								FROM note_shares sh
+								JOIN notes n ON n.id = sh.note_id
+								JOIN users u ON u.id = n.user_id
+								WHERE sh.user_id = $1 AND n.deleted_at IS NULL
+								ORDER BY n.updated_at DESC, n.id DESC`, idUser)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	defer rows.Close()
+
+	notes := []*models.SharedNote{}
+
+	for rows.Next() {
+		note := &models.SharedNote{}
+
+		err := rows.Scan(
+			&note.ID,
+			&note.UserID,
+			&note.OwnerName,
+			&note.Title,
+			&note.Content,
+			&note.Permission,
+			&note.CreatedAt,
+			&note.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		notes = append(notes, note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration: %w", op, err)
+	}
+
+	return notes, nil
+}
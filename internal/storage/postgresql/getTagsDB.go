@@ -0,0 +1,49 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"context"
+	"fmt"
+)
+
+func (s *Storage) GetTags(ctx context.Context, idUser int64) ([]*models.Tag, error) {
+	const op = "storage.postgresql.GetTags"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT t.id, t.name, COUNT(nt.note_id)
+								FROM tags t
+								JOIN note_tags nt ON nt.tag_id = t.id
+								JOIN notes n ON n.id = nt.note_id
+								WHERE t.user_id = $1 AND n.deleted_at IS NULL
+								GROUP BY t.id, t.name
+								ORDER BY t.name`, idUser)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	defer rows.Close()
+
+	tags := []*models.Tag{}
+
+	for rows.Next() {
+		tag := &models.Tag{}
+
+		err := rows.Scan(
+			&tag.ID,
+			&tag.Name,
+			&tag.NoteCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration: %w", op, err)
+	}
+
+	return tags, nil
+}
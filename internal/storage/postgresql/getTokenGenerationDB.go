@@ -0,0 +1,28 @@
+package postgresql
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+func (s *Storage) GetTokenGeneration(ctx context.Context, idUser int64) (int64, error) {
+	const op = "storage.postgresql.GetTokenGeneration"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	var generation int64
+
+	err := s.db.QueryRowContext(ctx, `SELECT token_generation FROM users WHERE id = $1`, idUser).Scan(&generation)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storageErr.ErrUserNotFound)
+		}
+		return 0, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return generation, nil
+}
@@ -0,0 +1,55 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// GetTrash возвращает заметки пользователя из корзины, начиная с последних удалённых
+func (s *Storage) GetTrash(ctx context.Context, idUser int64) ([]*models.Note, error) {
+	const op = "storage.postgresql.GetTrash"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT n.id, n.user_id, n.title, n.content, `+noteTagsColumn+`, n.notebook_id, n.created_at, n.updated_at, n.deleted_at
+								FROM notes n
+								WHERE n.user_id = $1 AND n.deleted_at IS NOT NULL
+								ORDER BY n.deleted_at DESC, n.id DESC`, idUser)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	defer rows.Close()
+
+	notes := []*models.Note{}
+
+	for rows.Next() {
+		note := &models.Note{}
+
+		err := rows.Scan(
+			&note.ID,
+			&note.UserID,
+			&note.Title,
+			&note.Content,
+			pq.Array(&note.Tags),
+			&note.NotebookID,
+			&note.CreatedAt,
+			&note.UpdatedAt,
+			&note.DeletedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		notes = append(notes, note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration: %w", op, err)
+	}
+
+	return notes, nil
+}
@@ -0,0 +1,36 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+func (s *Storage) GetUserByID(ctx context.Context, idUser int64) (*models.User, error) {
+	const op = "storage.postgresql.GetUserByID"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	user := &models.User{}
+
+	err := s.db.QueryRowContext(ctx, `SELECT id, user_name, password_hash, created_at
+									  FROM users
+									  WHERE id = $1`, idUser).Scan(
+		&user.ID,
+		&user.Username,
+		&user.PasswordHash,
+		&user.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrUserNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return user, nil
+}
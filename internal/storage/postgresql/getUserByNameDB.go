@@ -0,0 +1,36 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+func (s *Storage) GetUserByName(ctx context.Context, userName string) (*models.User, error) {
+	const op = "storage.postgresql.GetUserByName"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	user := &models.User{}
+
+	err := s.db.QueryRowContext(ctx, `SELECT id, user_name, password_hash, created_at
+									  FROM users
+									  WHERE user_name = $1`, userName).Scan(
+		&user.ID,
+		&user.Username,
+		&user.PasswordHash,
+		&user.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrUserNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return user, nil
+}
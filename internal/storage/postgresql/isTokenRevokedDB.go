@@ -0,0 +1,22 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+)
+
+func (s *Storage) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	const op = "storage.postgresql.IsTokenRevoked"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	var revoked bool
+
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)`, jti).Scan(&revoked)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return revoked, nil
+}
@@ -0,0 +1,31 @@
+package postgresql
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// lockNote блокирует заметку до конца транзакции и проверяет, что её версия
+// совпадает с version (оптимистичная блокировка). version == 0 — без проверки
+func lockNote(ctx context.Context, tx *sql.Tx, idUser int64, idNote int64, version int64) error {
+	var current int64
+
+	err := tx.QueryRowContext(ctx, `SELECT version FROM notes
+						   WHERE user_id = $1 AND id = $2 AND deleted_at IS NULL
+						   FOR UPDATE`, idUser, idNote).Scan(&current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storageErr.ErrNoteNotFound
+		}
+		return fmt.Errorf("lock note: %w", err)
+	}
+
+	if version != 0 && current != version {
+		return storageErr.ErrVersionMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package postgresql
+
+import (
+	"NotesService/internal/storage"
+
+	"github.com/lib/pq"
+)
+
+// noteFilterWhere строит условие WHERE для заметок пользователя (алиас n) с учётом filter.
+// idUser всегда передаётся первым аргументом ($1)
+func noteFilterWhere(idUser int64, filter storage.NoteFilter) *whereBuilder {
+	b := &whereBuilder{}
+	b.and("n.user_id = " + b.arg(idUser))
+	b.and("n.deleted_at IS NULL")
+
+	// Фильтр по тегам: в режиме OR достаточно одного совпадения, в режиме AND нужны все теги
+	if len(filter.Tags) > 0 {
+		tagsSubquery := `(SELECT COUNT(DISTINCT t.name) FROM note_tags nt
+			JOIN tags t ON t.id = nt.tag_id
+			WHERE nt.note_id = n.id AND t.name = ANY(` + b.arg(pq.Array(filter.Tags)) + `))`
+
+		if filter.MatchAllTags {
+			b.and(tagsSubquery + " = " + b.arg(len(filter.Tags)))
+		} else {
+			b.and(tagsSubquery + " > 0")
+		}
+	}
+
+	// Фильтр по блокноту: с Recursive в выборку попадают и заметки из вложенных блокнотов
+	if filter.NotebookID != nil {
+		if filter.Recursive {
+			b.and(`n.notebook_id IN (
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM notebooks WHERE user_id = $1 AND id = ` + b.arg(*filter.NotebookID) + `
+				UNION ALL
+				SELECT nb.id FROM notebooks nb JOIN subtree st ON nb.parent_id = st.id
+			)
+			SELECT id FROM subtree)`)
+		} else {
+			b.and("n.notebook_id = " + b.arg(*filter.NotebookID))
+		}
+	}
+
+	// Диапазоны дат: нижняя граница включается, верхняя — нет
+	if filter.CreatedFrom != nil {
+		b.and("n.created_at >= " + b.arg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		b.and("n.created_at < " + b.arg(*filter.CreatedTo))
+	}
+	if filter.UpdatedFrom != nil {
+		b.and("n.updated_at >= " + b.arg(*filter.UpdatedFrom))
+	}
+	if filter.UpdatedTo != nil {
+		b.and("n.updated_at < " + b.arg(*filter.UpdatedTo))
+	}
+
+	// Поиск по заголовку без учёта регистра; % и _ из запроса сравниваются буквально
+	if filter.TitlePrefix != "" {
+		b.and("n.title ILIKE (" + b.arg(likeEscape(filter.TitlePrefix)) + "::text || '%')")
+	}
+	if filter.TitleContains != "" {
+		b.and("n.title ILIKE ('%' || " + b.arg(likeEscape(filter.TitleContains)) + "::text || '%')")
+	}
+
+	if filter.MinContentLength != nil {
+		b.and("char_length(n.content) >= " + b.arg(*filter.MinContentLength))
+	}
+	if filter.MaxContentLength != nil {
+		b.and("char_length(n.content) <= " + b.arg(*filter.MaxContentLength))
+	}
+
+	return b
+}
@@ -0,0 +1,49 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// noteTagsColumn — подзапрос, возвращающий теги заметки n как массив (сканируется через pq.Array)
+const noteTagsColumn = `COALESCE(ARRAY(SELECT t.name FROM note_tags nt
+	JOIN tags t ON t.id = nt.tag_id
+	WHERE nt.note_id = n.id
+	ORDER BY t.name), '{}')`
+
+// setNoteTags заменяет теги заметки. Отсутствующие у пользователя теги создаются,
+// теги, которые больше не используются ни одной заметкой, удаляются
+func setNoteTags(ctx context.Context, tx *sql.Tx, idUser int64, idNote int64, tags []string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM note_tags WHERE note_id = $1`, idNote)
+	if err != nil {
+		return fmt.Errorf("delete note tags: %w", err)
+	}
+
+	if len(tags) > 0 {
+		_, err = tx.ExecContext(ctx, `INSERT INTO tags (user_id, name)
+							 SELECT $1, unnest($2::text[])
+							 ON CONFLICT (user_id, name) DO NOTHING`, idUser, pq.Array(tags))
+		if err != nil {
+			return fmt.Errorf("insert tags: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO note_tags (note_id, tag_id)
+							 SELECT $1, id FROM tags
+							 WHERE user_id = $2 AND name = ANY($3)`, idNote, idUser, pq.Array(tags))
+		if err != nil {
+			return fmt.Errorf("insert note tags: %w", err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM tags t
+						 WHERE t.user_id = $1
+						   AND NOT EXISTS (SELECT 1 FROM note_tags nt WHERE nt.tag_id = t.id)`, idUser)
+	if err != nil {
+		return fmt.Errorf("delete unused tags: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package postgresql
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// checkNotebook проверяет, что блокнот idNotebook принадлежит пользователю idUser
+func checkNotebook(ctx context.Context, tx *sql.Tx, idUser int64, idNotebook int64) error {
+	var exists bool
+	err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM notebooks WHERE user_id = $1 AND id = $2)`, idUser, idNotebook).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check notebook: %w", err)
+	}
+	if !exists {
+		return storageErr.ErrNotebookNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// PatchNote обновляет только переданные в changes поля заметки.
+// version — ожидаемая версия заметки (0 — без проверки)
+func (s *Storage) PatchNote(ctx context.Context, idUser int64, idNote int64, changes storage.NoteChanges, version int64) (*models.Note, error) {
+	const op = "storage.postgresql.PatchNote"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	defer tx.Rollback()
+
+	if err := lockNote(ctx, tx, idUser, idNote, version); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	set := []string{"version = version + 1", "updated_at = CURRENT_TIMESTAMP"}
+	args := []any{idUser, idNote}
+
+	// Блокнот должен принадлежать владельцу заметки; nil означает "вне блокнотов"
+	if notebookID, ok := changes[storage.NoteFieldNotebookID].(*int64); ok && notebookID != nil {
+		if err := checkNotebook(ctx, tx, idUser, *notebookID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+		}
+	}
+
+	_, titleChanged := changes[storage.NoteFieldTitle]
+	_, contentChanged := changes[storage.NoteFieldContent]
+	if titleChanged || contentChanged {
+		patched := models.Note{}
+		err := tx.QueryRowContext(ctx, `SELECT title, content FROM notes WHERE id = $1`, idNote).Scan(&patched.Title, &patched.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+		}
+		changes.Apply(&patched)
+
+		// Предыдущая версия попадает в историю в той же транзакции, что и изменение
+		if err := saveNoteRevision(ctx, tx, idUser, idNote, patched.Title, patched.Content); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+		}
+	}
+
+	for _, f := range storage.NoteFields {
+		if value, ok := changes[f.Name]; ok && f.Column != "" {
+			args = append(args, value)
+			set = append(set, fmt.Sprintf("%s = $%d", f.Column, len(args)))
+		}
+	}
+
+	if tags, ok := changes[storage.NoteFieldTags].([]string); ok {
+		if err := setNoteTags(ctx, tx, idUser, idNote, tags); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+		}
+	}
+
+	note := &models.Note{}
+
+	err = tx.QueryRowContext(ctx, `UPDATE notes n
+						  SET `+strings.Join(set, ", ")+`
+						  WHERE n.user_id = $1 AND n.id = $2
+						  RETURNING n.id, n.user_id, n.title, n.content, `+noteTagsColumn+`, n.notebook_id, n.version, n.created_at, n.updated_at`,
+		args...).Scan(
+		&note.ID,
+		&note.UserID,
+		&note.Title,
+		&note.Content,
+		pq.Array(&note.Tags),
+		&note.NotebookID,
+		&note.Version,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return note, nil
+}
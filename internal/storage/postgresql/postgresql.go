@@ -1,58 +1,95 @@
 package postgresql
 
 import (
+	"NotesService/internal/storage/migrator"
+	"NotesService/internal/tracing"
+	"NotesService/migrations"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
 )
 
+var tracer = otel.Tracer("NotesService/internal/storage/postgresql")
+
 type Storage struct {
 	db *sql.DB
+	// Предельное время одного вызова хранилища
+	queryTimeout time.Duration
 }
 
-func New(storagePath string) (*Storage, error) {
+func New(storagePath string, queryTimeout time.Duration) (*Storage, error) {
 	const op = "storage.postgresql.New"
 
-	db, err := sql.Open("postgres", storagePath)
+	connector, err := pq.NewConnector(storagePath)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 
 	}
 
-	//think about Migration
+	// Каждый запрос к базе — дочерний спан вызова хранилища с текстом SQL без литералов
+	db := sql.OpenDB(tracing.Connector(connector, "postgresql"))
 
-	stmt1, err := db.Prepare(`create table IF NOT EXISTS users(
-									id BIGSERIAL PRIMARY KEY,
-									user_name TEXT NOT NULL ,
-									created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP);`)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
-	defer stmt1.Close()
+	return &Storage{db: db, queryTimeout: queryTimeout}, nil
 
-	_, err = stmt1.Exec()
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
+}
 
-	stmt2, err := db.Prepare(`create table IF NOT EXISTS notes(
-									id BIGSERIAL PRIMARY KEY,
-									user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE cascade,
-									title TEXT NOT NULL,
-									content TEXT NOT NULL,
-									created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
-									updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP)`)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+// Ping проверяет, что база доступна
+func (s *Storage) Ping(ctx context.Context) error {
+	const op = "storage.postgresql.Ping"
+
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
-	defer stmt2.Close()
 
-	_, err = stmt2.Exec()
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+	return nil
+}
+
+// Stats возвращает состояние пула соединений с базой
+func (s *Storage) Stats() sql.DBStats {
+	return s.db.Stats()
+}
+
+// Close закрывает соединения с базой
+func (s *Storage) Close() error {
+	const op = "storage.postgresql.Close"
+
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &Storage{db: db}, nil
+	return nil
+}
 
+// Migrator возвращает мигратор схемы с миграциями, встроенными из migrations/
+func (s *Storage) Migrator(log *slog.Logger) (*migrator.Migrator, error) {
+	return migrator.New(log, s.db, migrator.Postgres, migrations.FS)
+}
+
+// begin открывает спан вызова хранилища op, дочерний к спану HTTP запроса, и ограничивает
+// вызов queryTimeout. Отмена ctx (клиент отключился, истёк таймаут запроса) прерывает
+// запрос к базе раньше. Возвращённая функция снимает таймаут и закрывает спан
+func (s *Storage) begin(ctx context.Context, op string) (context.Context, func()) {
+	ctx, span := tracer.Start(ctx, op)
+	ctx, cancel := context.WithTimeout(ctx, s.queryTimeout)
+
+	return ctx, func() {
+		cancel()
+		span.End()
+	}
+}
+
+// queryErr добавляет к ошибке драйвера причину из ctx, если запрос прерван его отменой
+// или таймаутом: lib/pq сообщает об этом ошибкой 57014 query_canceled, по которой
+// нельзя отличить таймаут от других ошибок базы
+func queryErr(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil && !errors.Is(err, ctxErr) {
+		return fmt.Errorf("%w: %w", ctxErr, err)
+	}
+	return err
 }
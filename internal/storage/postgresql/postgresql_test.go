@@ -0,0 +1,84 @@
+package postgresql_test
+
+import (
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/postgresql"
+	"NotesService/internal/storage/storagetest"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dsnEnv — строка подключения к тестовой базе PostgreSQL. Без неё тест пропускается.
+// Каждая проверка работает в своей схеме, которая удаляется после неё, поэтому
+// подойдёт и база, где уже есть данные
+const dsnEnv = "TEST_POSTGRES_DSN"
+
+func TestConformance(t *testing.T) {
+	dsn := os.Getenv(dsnEnv)
+	if dsn == "" {
+		t.Skip(dsnEnv + " is not set")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+	if err := admin.PingContext(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	schemas := 0
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		schemas++
+		schema := fmt.Sprintf("storagetest_%d_%d", os.Getpid(), schemas)
+
+		if _, err := admin.ExecContext(t.Context(), "CREATE SCHEMA "+schema); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			if _, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+				t.Errorf("drop schema %s: %v", schema, err)
+			}
+		})
+
+		s, err := postgresql.New(withSearchPath(t, dsn, schema), 5*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+
+		m, err := s.Migrator(slog.New(slog.DiscardHandler))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m.Up(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		return s
+	})
+}
+
+// withSearchPath добавляет к строке подключения search_path, в URL и в форме key=value
+func withSearchPath(t *testing.T, dsn, schema string) string {
+	if !strings.Contains(dsn, "://") {
+		return dsn + " search_path=" + schema
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
@@ -0,0 +1,38 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PruneNoteRevisions удаляет версии заметок сверх maxCount последних для каждой
+// заметки и версии, созданные раньше before. maxCount <= 0 и нулевое before
+// отключают соответствующее ограничение
+func (s *Storage) PruneNoteRevisions(ctx context.Context, maxCount int, before time.Time) (int64, error) {
+	const op = "storage.postgresql.PruneNoteRevisions"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	var beforeArg *time.Time
+	if !before.IsZero() {
+		beforeArg = &before
+	}
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM note_revisions r
+							  USING notes n
+							  WHERE n.id = r.note_id
+							    AND (($1 > 0 AND r.revision <= n.last_revision - $1)
+							      OR ($2::timestamptz IS NOT NULL AND r.created_at < $2))`, maxCount, beforeArg)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	pruned, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return pruned, nil
+}
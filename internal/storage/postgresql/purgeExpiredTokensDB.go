@@ -0,0 +1,35 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PurgeExpiredTokens удаляет отозванные access-токены и refresh-токены, срок жизни которых истёк
+func (s *Storage) PurgeExpiredTokens(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.postgresql.PurgeExpiredTokens"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	var purged int64
+
+	for _, query := range []string{
+		`DELETE FROM revoked_tokens WHERE expires_at < $1`,
+		`DELETE FROM refresh_tokens WHERE expires_at < $1`,
+	} {
+		res, err := s.db.ExecContext(ctx, query, before)
+		if err != nil {
+			return purged, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return purged, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+		}
+		purged += rowsAffected
+	}
+
+	return purged, nil
+}
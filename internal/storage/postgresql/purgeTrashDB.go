@@ -0,0 +1,27 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PurgeTrash окончательно удаляет заметки, попавшие в корзину раньше before
+func (s *Storage) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.postgresql.PurgeTrash"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM notes WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return purged, nil
+}
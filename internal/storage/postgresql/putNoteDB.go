@@ -3,14 +3,21 @@ package postgresql
 import (
 	"NotesService/internal/models"
 	"NotesService/internal/storage/storageErr"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/lib/pq"
 )
 
-func (s *Storage) PutNote(idUser int64, idNote int64, title string, content string) (*models.Note, error) {
+func (s *Storage) PutNote(ctx context.Context, idUser int64, idNote int64, title string, content string, tags []string, notebookID *int64, version int64) (*models.Note, error) {
 	const op = "storage.postgresql.PutNote"
 
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
 	note := &models.Note{
 		UserID:    idUser,
 		ID:        idNote,
@@ -19,23 +26,66 @@ func (s *Storage) PutNote(idUser int64, idNote int64, title string, content stri
 		UpdatedAt: time.Now(),
 	}
 
-	err := s.db.QueryRow(`UPDATE notes 
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	defer tx.Rollback()
+
+	if err := lockNote(ctx, tx, idUser, idNote, version); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	// Предыдущая версия попадает в историю в той же транзакции, что и изменение
+	if err := saveNoteRevision(ctx, tx, idUser, idNote, title, content); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	// Блокнот должен принадлежать владельцу заметки; 0 означает "вне блокнотов"
+	if notebookID != nil && *notebookID != 0 {
+		if err := checkNotebook(ctx, tx, idUser, *notebookID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `UPDATE notes 
 								SET title=$3,
 								    content=$4,
+								    notebook_id=CASE WHEN $5::bigint IS NULL THEN notebook_id ELSE NULLIF($5, 0) END,
+								    version=version+1,
 								    updated_at=CURRENT_TIMESTAMP 
-								WHERE user_id = $1 AND id = $2
-								RETURNING id,user_id,title,content,created_at,updated_at`, idUser, idNote, title, content).Scan(
+								WHERE user_id = $1 AND id = $2 AND deleted_at IS NULL
+								RETURNING id,user_id,title,content,notebook_id,version,created_at,updated_at`, idUser, idNote, title, content, notebookID).Scan(
 		&note.ID,
 		&note.UserID,
 		&note.Title,
 		&note.Content,
+		&note.NotebookID,
+		&note.Version,
 		&note.CreatedAt,
 		&note.UpdatedAt,
 	)
 	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
+		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
 		}
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	// Владелец тегов — автор заметки, а не тот, кто её редактирует
+	if tags != nil {
+		if err := setNoteTags(ctx, tx, note.UserID, note.ID, tags); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `SELECT `+noteTagsColumn+` FROM notes n WHERE n.id = $1`, note.ID).Scan(pq.Array(&note.Tags))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
 	}
 
 	return note, nil
@@ -2,21 +2,35 @@ package postgresql
 
 import (
 	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"errors"
 	"fmt"
+
+	"github.com/lib/pq"
 )
 
-func (s *Storage) RegisterUser(userName string) (*models.User, error) {
+func (s *Storage) RegisterUser(ctx context.Context, userName string, passwordHash string) (*models.User, error) {
 	const op = "storage.postgresql.RegisterUser"
 
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
 	user := &models.User{
-		Username: userName,
+		Username:     userName,
+		PasswordHash: passwordHash,
 	}
 
-	err := s.db.QueryRow(`INSERT INTO users (user_name) 
-									  values ($1)
-									  RETURNING id,user_name,created_at`, userName).Scan(&user.ID, &user.Username, &user.CreatedAt)
+	err := s.db.QueryRowContext(ctx, `INSERT INTO users (user_name, password_hash) 
+									  values ($1, $2)
+									  RETURNING id,user_name,created_at`, userName, passwordHash).Scan(&user.ID, &user.Username, &user.CreatedAt)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+		var pqErr *pq.Error
+		// 23505 - unique_violation: имя пользователя уже занято
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrUserExists)
+		}
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
 	}
 	return user, nil
 
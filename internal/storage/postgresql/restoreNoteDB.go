@@ -0,0 +1,46 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// RestoreNote возвращает заметку из корзины
+func (s *Storage) RestoreNote(ctx context.Context, idUser int64, idNote int64) (*models.Note, error) {
+	const op = "storage.postgresql.RestoreNote"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	note := &models.Note{}
+
+	err := s.db.QueryRowContext(ctx, `UPDATE notes n
+							 SET deleted_at = NULL
+							 WHERE n.user_id = $1 AND n.id = $2 AND n.deleted_at IS NOT NULL
+							 RETURNING n.id, n.user_id, n.title, n.content, `+noteTagsColumn+`, n.notebook_id, n.version, n.created_at, n.updated_at`,
+		idUser, idNote).Scan(
+		&note.ID,
+		&note.UserID,
+		&note.Title,
+		&note.Content,
+		pq.Array(&note.Tags),
+		&note.NotebookID,
+		&note.Version,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return note, nil
+}
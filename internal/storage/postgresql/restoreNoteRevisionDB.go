@@ -0,0 +1,76 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// RestoreNoteRevision возвращает заметке title и content из сохранённой версии.
+// Текущая версия перед этим тоже попадает в историю, поэтому восстановление можно отменить
+func (s *Storage) RestoreNoteRevision(ctx context.Context, idUser int64, idNote int64, revision int64) (*models.Note, error) {
+	const op = "storage.postgresql.RestoreNoteRevision"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	defer tx.Rollback()
+
+	var title, content string
+	err = tx.QueryRowContext(ctx, `SELECT r.title, r.content
+						  FROM note_revisions r
+						  JOIN notes n ON n.id = r.note_id
+						  WHERE n.user_id = $1 AND r.note_id = $2 AND r.revision = $3 AND n.deleted_at IS NULL`, idUser, idNote, revision).Scan(&title, &content)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrRevisionNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	if err := saveNoteRevision(ctx, tx, idUser, idNote, title, content); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	note := &models.Note{}
+
+	err = tx.QueryRowContext(ctx, `UPDATE notes n
+						  SET title = $3,
+						      content = $4,
+						      version = version + 1,
+						      updated_at = CURRENT_TIMESTAMP
+						  WHERE n.user_id = $1 AND n.id = $2 AND n.deleted_at IS NULL
+						  RETURNING n.id, n.user_id, n.title, n.content, `+noteTagsColumn+`, n.notebook_id, n.version, n.created_at, n.updated_at`,
+		idUser, idNote, title, content).Scan(
+		&note.ID,
+		&note.UserID,
+		&note.Title,
+		&note.Content,
+		pq.Array(&note.Tags),
+		&note.NotebookID,
+		&note.Version,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return note, nil
+}
@@ -0,0 +1,32 @@
+package postgresql
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+)
+
+func (s *Storage) RevokeNoteLink(ctx context.Context, idOwner int64, idNote int64, idLink int64) error {
+	const op = "storage.postgresql.RevokeNoteLink"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	res, err := s.db.ExecContext(ctx, `UPDATE note_links l
+								SET revoked_at = COALESCE(l.revoked_at, CURRENT_TIMESTAMP)
+								FROM notes n
+								WHERE l.note_id = n.id AND n.user_id = $1 AND n.id = $2 AND l.id = $3`, idOwner, idNote, idLink)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storageErr.ErrLinkNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package postgresql
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+)
+
+// RevokeRefreshTokenFamily отзывает все токены из семейства, к которому относится tokenHash
+func (s *Storage) RevokeRefreshTokenFamily(ctx context.Context, idUser int64, tokenHash string) error {
+	const op = "storage.postgresql.RevokeRefreshTokenFamily"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	res, err := s.db.ExecContext(ctx, `UPDATE refresh_tokens
+								SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+								WHERE family_id = (SELECT family_id FROM refresh_tokens
+								                   WHERE token_hash = $2 AND user_id = $1)`, idUser, tokenHash)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storageErr.ErrRefreshTokenNotFound)
+	}
+
+	return nil
+}
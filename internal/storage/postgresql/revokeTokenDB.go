@@ -0,0 +1,23 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (s *Storage) RevokeToken(ctx context.Context, jti string, idUser int64, expiresAt time.Time) error {
+	const op = "storage.postgresql.RevokeToken"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	_, err := s.db.ExecContext(ctx, `INSERT INTO revoked_tokens (jti, user_id, expires_at)
+							VALUES ($1, $2, $3)
+							ON CONFLICT (jti) DO NOTHING`, jti, idUser, expiresAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return nil
+}
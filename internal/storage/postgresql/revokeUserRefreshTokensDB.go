@@ -0,0 +1,22 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+)
+
+func (s *Storage) RevokeUserRefreshTokens(ctx context.Context, idUser int64) error {
+	const op = "storage.postgresql.RevokeUserRefreshTokens"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	_, err := s.db.ExecContext(ctx, `UPDATE refresh_tokens
+							SET revoked_at = CURRENT_TIMESTAMP
+							WHERE user_id = $1 AND revoked_at IS NULL`, idUser)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RotateRefreshToken помечает старый токен использованным и выдаёт новый в том же семействе.
+// Повторное предъявление уже использованного токена отзывает всё семейство
+func (s *Storage) RotateRefreshToken(ctx context.Context, oldHash string, newHash string, expiresAt time.Time) (*models.RefreshToken, error) {
+	const op = "storage.postgresql.RotateRefreshToken"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	defer tx.Rollback()
+
+	old := &models.RefreshToken{}
+
+	// FOR UPDATE — две параллельные ротации одного токена не пройдут обе
+	err = tx.QueryRowContext(ctx, `SELECT id, user_id, family_id, expires_at, used_at, revoked_at
+							  FROM refresh_tokens
+							  WHERE token_hash = $1
+							  FOR UPDATE`, oldHash).Scan(
+		&old.ID,
+		&old.UserID,
+		&old.FamilyID,
+		&old.ExpiresAt,
+		&old.UsedAt,
+		&old.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrRefreshTokenNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	if old.UsedAt != nil || old.RevokedAt != nil {
+		_, err = tx.ExecContext(ctx, `UPDATE refresh_tokens
+							 SET revoked_at = CURRENT_TIMESTAMP
+							 WHERE family_id = $1 AND revoked_at IS NULL`, old.FamilyID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+		}
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrRefreshTokenReused)
+	}
+
+	if time.Now().After(old.ExpiresAt) {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrRefreshTokenExpired)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE refresh_tokens SET used_at = CURRENT_TIMESTAMP WHERE id = $1`, old.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	token := &models.RefreshToken{
+		UserID:    old.UserID,
+		TokenHash: newHash,
+		FamilyID:  old.FamilyID,
+		ExpiresAt: expiresAt,
+	}
+
+	err = tx.QueryRowContext(ctx, `INSERT INTO refresh_tokens (user_id, token_hash, family_id, expires_at)
+							  VALUES ($1, $2, $3, $4)
+							  RETURNING id, created_at`, token.UserID, newHash, token.FamilyID, expiresAt).Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return token, nil
+}
@@ -2,12 +2,16 @@ package postgresql
 
 import (
 	"NotesService/internal/models"
+	"context"
 	"fmt"
 )
 
-func (s *Storage) SaveNotes(title string, content string, idUser int64) (*models.Note, int64, error) {
+func (s *Storage) SaveNotes(ctx context.Context, title string, content string, idUser int64, tags []string, notebookID *int64) (*models.Note, int64, error) {
 	const op = "storage.postgresql.SaveNotes"
 
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
 	if title == "" {
 		return nil, 0, fmt.Errorf("%s: title cannot be empty", op)
 	}
@@ -15,22 +19,42 @@ func (s *Storage) SaveNotes(title string, content string, idUser int64) (*models
 		return nil, 0, fmt.Errorf("%s: content cannot be empty", op)
 	}
 
-	stmt, err := s.db.Prepare(`insert into notes (user_id,title,content) values ($1,$2,$3) returning id,user_id,title,content,created_at,updated_at`)
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, 0, fmt.Errorf("%s: %w", op, err)
+		return nil, 0, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	defer tx.Rollback()
+
+	if notebookID != nil {
+		if err := checkNotebook(ctx, tx, idUser, *notebookID); err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+		}
 	}
-	defer stmt.Close()
 
 	note := &models.Note{
 		UserID:  idUser,
 		Title:   title,
 		Content: content,
+		Tags:    []string{},
 	}
 	var id int64
 
-	err = stmt.QueryRow(idUser, title, content).Scan(&note.ID, &note.UserID, &note.Title, &note.Content, &note.CreatedAt, &note.UpdatedAt)
+	err = tx.QueryRowContext(ctx, `insert into notes (user_id,title,content,notebook_id) values ($1,$2,$3,$4) returning id,user_id,title,content,notebook_id,version,created_at,updated_at`,
+		idUser, title, content, notebookID).Scan(&note.ID, &note.UserID, &note.Title, &note.Content, &note.NotebookID, &note.Version, &note.CreatedAt, &note.UpdatedAt)
 	if err != nil {
-		return nil, 0, fmt.Errorf("%s: %w", op, err)
+		return nil, 0, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	if len(tags) > 0 {
+		if err := setNoteTags(ctx, tx, idUser, note.ID, tags); err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+		}
+		note.Tags = tags
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
 	}
+
 	return note, id, nil
 }
@@ -0,0 +1,47 @@
+package postgresql
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// saveNoteRevision блокирует заметку и сохраняет её текущую версию в историю,
+// если новые title и content от неё отличаются. Вызывается перед изменением заметки
+func saveNoteRevision(ctx context.Context, tx *sql.Tx, idUser int64, idNote int64, title string, content string) error {
+	var (
+		oldTitle   string
+		oldContent string
+		updatedAt  time.Time
+	)
+
+	err := tx.QueryRowContext(ctx, `SELECT title, content, updated_at FROM notes
+						   WHERE user_id = $1 AND id = $2 AND deleted_at IS NULL
+						   FOR UPDATE`, idUser, idNote).Scan(&oldTitle, &oldContent, &updatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storageErr.ErrNoteNotFound
+		}
+		return fmt.Errorf("lock note: %w", err)
+	}
+
+	if oldTitle == title && oldContent == content {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, `WITH rev AS (
+						 UPDATE notes SET last_revision = last_revision + 1
+						 WHERE id = $1
+						 RETURNING last_revision
+					 )
+					 INSERT INTO note_revisions (note_id, revision, title, content, created_at)
+					 SELECT $1, last_revision, $2, $3, $4 FROM rev`, idNote, oldTitle, oldContent, updatedAt)
+	if err != nil {
+		return fmt.Errorf("insert revision: %w", err)
+	}
+
+	return nil
+}
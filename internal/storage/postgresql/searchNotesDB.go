@@ -0,0 +1,82 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// Параметры ts_headline для заголовка и фрагмента текста заметки
+const (
+	titleHeadlineOptions = "HighlightAll=true, StartSel=" + storage.HighlightStart + ", StopSel=" + storage.HighlightStop
+	headlineOptions      = "StartSel=" + storage.HighlightStart + ", StopSel=" + storage.HighlightStop + ", MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=\" ... \""
+)
+
+// SearchNotes ищет заметки пользователя по заголовку и тексту. Результаты
+// упорядочены по релевантности (ts_rank), найденные слова подсвечиваются ts_headline
+func (s *Storage) SearchNotes(ctx context.Context, idUser int64, query string, limit int, offset int) ([]*models.NoteSearchResult, error) {
+	const op = "storage.postgresql.SearchNotes"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	tsQuery := buildTSQuery(query)
+	if tsQuery == "" {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrEmptySearchQuery)
+	}
+
+	// ts_headline дорогой, поэтому считается только для отобранной страницы
+	rows, err := s.db.QueryContext(ctx, `
+	SELECT n.id, n.user_id, n.title, n.content, `+noteTagsColumn+`, n.notebook_id, n.created_at, n.updated_at,
+	       n.rank,
+	       ts_headline('simple', n.title, n.q, $6),
+	       ts_headline('simple', n.content, n.q, $5)
+	FROM (
+		SELECT notes.*, q, ts_rank(notes.search_vector, q) AS rank
+		FROM notes, to_tsquery('simple', $2) q
+		WHERE notes.user_id = $1 AND notes.deleted_at IS NULL AND notes.search_vector @@ q
+		ORDER BY rank DESC, notes.updated_at DESC
+		LIMIT $3
+		OFFSET $4
+	) n
+	ORDER BY n.rank DESC, n.updated_at DESC`, idUser, tsQuery, limit, offset, headlineOptions, titleHeadlineOptions)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	defer rows.Close()
+
+	results := []*models.NoteSearchResult{}
+
+	for rows.Next() {
+		result := &models.NoteSearchResult{}
+
+		err := rows.Scan(
+			&result.ID,
+			&result.UserID,
+			&result.Title,
+			&result.Content,
+			pq.Array(&result.Tags),
+			&result.NotebookID,
+			&result.CreatedAt,
+			&result.UpdatedAt,
+			&result.Rank,
+			&result.TitleHighlight,
+			&result.Snippet,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration: %w", op, err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,29 @@
+package postgresql
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+)
+
+func (s *Storage) SetUserPassword(ctx context.Context, idUser int64, passwordHash string) error {
+	const op = "storage.postgresql.SetUserPassword"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	res, err := s.db.ExecContext(ctx, `UPDATE users SET password_hash = $2 WHERE id = $1`, idUser, passwordHash)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storageErr.ErrUserNotFound)
+	}
+
+	return nil
+}
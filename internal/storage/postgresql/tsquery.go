@@ -0,0 +1,112 @@
+package postgresql
+
+import (
+	"strings"
+	"unicode"
+)
+
+// buildTSQuery переводит поисковую строку пользователя в синтаксис to_tsquery:
+//
+//	слово        — обычный терм, термы объединяются через AND
+//	"два слова"  — фраза, слова должны идти подряд
+//	слов*        — поиск по префиксу
+//	-слово       — заметка не должна содержать слово
+//	or           — OR между соседними термами
+//
+// Служебные символы tsquery из ввода отбрасываются, поэтому результат всегда
+// синтаксически корректен. Пустая строка означает, что искать нечего
+func buildTSQuery(input string) string {
+	var (
+		terms  []string
+		joinOr bool
+	)
+
+	add := func(term string, negate bool) {
+		if term == "" {
+			return
+		}
+		if negate {
+			term = "!" + term
+		}
+		if len(terms) > 0 {
+			if joinOr {
+				terms = append(terms, "|")
+			} else {
+				terms = append(terms, "&")
+			}
+		}
+		terms = append(terms, term)
+		joinOr = false
+	}
+
+	for len(input) > 0 {
+		input = strings.TrimLeftFunc(input, unicode.IsSpace)
+		if input == "" {
+			break
+		}
+
+		negate := false
+		if input[0] == '-' {
+			negate = true
+			input = input[1:]
+		}
+
+		if strings.HasPrefix(input, `"`) {
+			end := strings.IndexByte(input[1:], '"')
+			var phrase string
+			if end < 0 {
+				phrase, input = input[1:], ""
+			} else {
+				phrase, input = input[1:end+1], input[end+2:]
+			}
+			add(phraseTerm(strings.Fields(phrase), false), negate)
+			continue
+		}
+
+		end := strings.IndexFunc(input, unicode.IsSpace)
+		var word string
+		if end < 0 {
+			word, input = input, ""
+		} else {
+			word, input = input[:end], input[end:]
+		}
+
+		if !negate && strings.EqualFold(word, "or") {
+			joinOr = len(terms) > 0
+			continue
+		}
+
+		prefix := strings.HasSuffix(word, "*")
+		add(phraseTerm([]string{word}, prefix), negate)
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// phraseTerm собирает из слов фразу "a <-> b <-> c". Слова разбиваются по
+// небуквенным символам; при prefix последняя часть ищется как префикс
+func phraseTerm(words []string, prefix bool) string {
+	var parts []string
+	for _, word := range words {
+		parts = append(parts, strings.FieldsFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})...)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	for i, part := range parts {
+		parts[i] = "'" + strings.ToLower(part) + "'"
+	}
+	if prefix {
+		parts[len(parts)-1] += ":*"
+	}
+
+	term := strings.Join(parts, " <-> ")
+	if len(parts) > 1 {
+		term = "(" + term + ")"
+	}
+
+	return term
+}
@@ -0,0 +1,31 @@
+package postgresql
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+)
+
+func (s *Storage) UnshareNote(ctx context.Context, idOwner int64, idNote int64, idUser int64) error {
+	const op = "storage.postgresql.UnshareNote"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM note_shares sh
+								USING notes n
+								WHERE sh.note_id = n.id AND n.user_id = $1 AND n.id = $2 AND sh.user_id = $3`, idOwner, idNote, idUser)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storageErr.ErrShareNotFound)
+	}
+
+	return nil
+}
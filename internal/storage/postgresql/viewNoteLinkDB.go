@@ -0,0 +1,50 @@
+package postgresql
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ViewNoteLink увеличивает счётчик просмотров действующей ссылки
+// и возвращает заметку вместе с новым значением счётчика
+func (s *Storage) ViewNoteLink(ctx context.Context, idLink int64) (*models.Note, int64, error) {
+	const op = "storage.postgresql.ViewNoteLink"
+
+	ctx, end := s.begin(ctx, op)
+	defer end()
+
+	note := &models.Note{}
+	var views int64
+
+	err := s.db.QueryRowContext(ctx, `WITH link AS (
+								UPDATE note_links
+								SET view_count = view_count + 1
+								WHERE id = $1
+								  AND revoked_at IS NULL
+								  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+								  AND EXISTS (SELECT 1 FROM notes WHERE notes.id = note_links.note_id AND notes.deleted_at IS NULL)
+								RETURNING note_id, view_count)
+							 SELECT n.id, n.user_id, n.title, n.content, n.created_at, n.updated_at, link.view_count
+							 FROM link
+							 JOIN notes n ON n.id = link.note_id`, idLink).Scan(
+		&note.ID,
+		&note.UserID,
+		&note.Title,
+		&note.Content,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+		&views,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, 0, fmt.Errorf("%s: %w", op, storageErr.ErrLinkNotFound)
+		}
+		return nil, 0, fmt.Errorf("%s: %w", op, queryErr(ctx, err))
+	}
+
+	return note, views, nil
+}
@@ -0,0 +1,28 @@
+package sqlite
+
+import (
+	"NotesService/internal/storage"
+	"context"
+	"fmt"
+)
+
+// CountNotes возвращает число заметок пользователя, подходящих под filter
+func (s *Storage) CountNotes(ctx context.Context, idUser int64, filter storage.NoteFilter) (int64, error) {
+	const op = "storage.sqlite.CountNotes"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	where, err := noteFilterWhere(idUser, filter)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var total int64
+	err = s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notes n WHERE `+where.String(), where.args...).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return total, nil
+}
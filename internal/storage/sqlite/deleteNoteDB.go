@@ -0,0 +1,38 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+// DeleteNote переносит заметку в корзину. Окончательно заметка удаляется
+// при очистке корзины или по истечении срока хранения. version == 0 — без проверки версии
+func (s *Storage) DeleteNote(ctx context.Context, idUser int64, idNote int64, version int64) error {
+	const op = "storage.sqlite.DeleteNote"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := lockNote(ctx, tx, idUser, idNote, version); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE notes
+						 SET deleted_at = `+currentTimestamp+`
+						 WHERE id = $1`, idNote)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+)
+
+// DeleteNotebook удаляет блокнот вместе с вложенными блокнотами.
+// Заметки из них не удаляются, а остаются вне блокнотов
+func (s *Storage) DeleteNotebook(ctx context.Context, idUser int64, idNotebook int64) error {
+	const op = "storage.sqlite.DeleteNotebook"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM notebooks WHERE user_id = $1 AND id = $2`, idUser, idNotebook)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storageErr.ErrNotebookNotFound)
+	}
+
+	return nil
+}
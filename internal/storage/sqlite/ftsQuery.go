@@ -0,0 +1,108 @@
+package sqlite
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ftsTerm — условие поиска в синтаксисе FTS5 ("слово", "два слова", "префикс"*)
+// и признак отрицания
+type ftsTerm struct {
+	match  string
+	negate bool
+}
+
+// buildFTSQuery разбирает поисковую строку пользователя так же, как buildTSQuery в postgresql:
+//
+//	слово        — обычный терм, термы объединяются через AND
+//	"два слова"  — фраза, слова должны идти подряд
+//	слов*        — поиск по префиксу
+//	-слово       — заметка не должна содержать слово
+//	or           — OR между соседними термами
+//
+// Результат — группы термов: внутри группы термы объединяются через AND, группы
+// между собой — через OR (у & в tsquery приоритет выше, чем у |). В FTS5 NOT только
+// бинарный, поэтому отрицания проверяются в SQL, а не в выражении MATCH.
+// Каждый терм состоит только из букв и цифр в кавычках, поэтому всегда корректен
+func buildFTSQuery(input string) [][]ftsTerm {
+	var (
+		groups [][]ftsTerm
+		joinOr bool
+	)
+
+	add := func(match string, negate bool) {
+		if match == "" {
+			return
+		}
+		if len(groups) == 0 || joinOr {
+			groups = append(groups, nil)
+		}
+		last := len(groups) - 1
+		groups[last] = append(groups[last], ftsTerm{match: match, negate: negate})
+		joinOr = false
+	}
+
+	for len(input) > 0 {
+		input = strings.TrimLeftFunc(input, unicode.IsSpace)
+		if input == "" {
+			break
+		}
+
+		negate := false
+		if input[0] == '-' {
+			negate = true
+			input = input[1:]
+		}
+
+		if strings.HasPrefix(input, `"`) {
+			end := strings.IndexByte(input[1:], '"')
+			var phrase string
+			if end < 0 {
+				phrase, input = input[1:], ""
+			} else {
+				phrase, input = input[1:end+1], input[end+2:]
+			}
+			add(ftsPhrase(strings.Fields(phrase), false), negate)
+			continue
+		}
+
+		end := strings.IndexFunc(input, unicode.IsSpace)
+		var word string
+		if end < 0 {
+			word, input = input, ""
+		} else {
+			word, input = input[:end], input[end:]
+		}
+
+		if !negate && strings.EqualFold(word, "or") {
+			joinOr = len(groups) > 0
+			continue
+		}
+
+		prefix := strings.HasSuffix(word, "*")
+		add(ftsPhrase([]string{word}, prefix), negate)
+	}
+
+	return groups
+}
+
+// ftsPhrase собирает из слов фразу FTS5 "a b c". Слова разбиваются по
+// небуквенным символам; при prefix последняя часть ищется как префикс
+func ftsPhrase(words []string, prefix bool) string {
+	var parts []string
+	for _, word := range words {
+		parts = append(parts, strings.FieldsFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})...)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	phrase := `"` + strings.ToLower(strings.Join(parts, " ")) + `"`
+	if prefix {
+		phrase += "*"
+	}
+
+	return phrase
+}
@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"context"
+	"fmt"
+	"slices"
+)
+
+// sortColumns — колонки, по которым разрешена сортировка. Значение NoteSort
+// никогда не подставляется в запрос напрямую
+var sortColumns = map[storage.NoteSort]string{
+	storage.NoteSortCreatedAt: "n.created_at",
+	storage.NoteSortUpdatedAt: "n.updated_at",
+	storage.NoteSortTitle:     "n.title",
+}
+
+func (s *Storage) GetAllNotes(ctx context.Context, idUser int64, page storage.NotePage, filter storage.NoteFilter) ([]*models.Note, error) {
+	const op = "storage.sqlite.GetAllNotes"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	notes := []*models.Note{}
+
+	column, ok := sortColumns[page.Sort]
+	if !ok {
+		column = sortColumns[storage.NoteSortCreatedAt]
+	}
+
+	// Для предыдущей страницы идём от курсора в обратную сторону, а затем разворачиваем результат
+	desc := page.Desc != page.Backward
+	direction, cmp := "ASC", ">"
+	if desc {
+		direction, cmp = "DESC", "<"
+	}
+
+	where, err := noteFilterWhere(idUser, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Keyset: сравнение пар (поле, id) не пропускает строки, добавленные во время листания
+	pagination := ""
+	if page.After != nil {
+		var key any = timestamp(page.After.Time)
+		if page.Sort == storage.NoteSortTitle {
+			key = page.After.Title
+		}
+		where.and("(" + column + ", n.id) " + cmp + " (" + where.arg(key) + ", " + where.arg(page.After.ID) + ")")
+	} else if page.Offset > 0 {
+		pagination = " OFFSET " + where.arg(page.Offset)
+	}
+
+	pagination = "LIMIT " + where.arg(page.Limit) + pagination
+
+	// В текст запроса подставляются только константы: колонка из sortColumns, направление
+	// и условие из whereBuilder, все значения передаются аргументами
+	query := fmt.Sprintf(`
+	SELECT n.id, n.user_id, n.title, n.content, %s, n.notebook_id, n.version, n.created_at, n.updated_at
+    FROM notes n
+    WHERE %s
+    ORDER BY %s %s, n.id %s
+    %s
+`, noteTagsColumn, where.String(), column, direction, direction, pagination)
+
+	rows, err := s.db.QueryContext(ctx, query, where.args...)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+
+		note := &models.Note{}
+
+		err := rows.Scan(
+			&note.ID,
+			&note.UserID,
+			&note.Title,
+			&note.Content,
+			jsonArray(&note.Tags),
+			&note.NotebookID,
+			&note.Version,
+			&note.CreatedAt,
+			&note.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		notes = append(notes, note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration: %w", op, err)
+	}
+
+	if page.Backward {
+		slices.Reverse(notes)
+	}
+
+	return notes, nil
+}
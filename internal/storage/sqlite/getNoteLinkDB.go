@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+func (s *Storage) GetNoteLink(ctx context.Context, tokenHash string) (*models.NoteLink, error) {
+	const op = "storage.sqlite.GetNoteLink"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	link := &models.NoteLink{}
+
+	err := s.db.QueryRowContext(ctx, `SELECT id, note_id, token_hash, password_hash, expires_at, revoked_at, view_count, created_at
+							 FROM note_links
+							 WHERE token_hash = $1`, tokenHash).Scan(
+		&link.ID,
+		&link.NoteID,
+		&link.TokenHash,
+		&link.PasswordHash,
+		&link.ExpiresAt,
+		&link.RevokedAt,
+		&link.ViewCount,
+		&link.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrLinkNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return link, nil
+}
@@ -0,0 +1,38 @@
+package sqlite
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+func (s *Storage) GetNotebook(ctx context.Context, idUser int64, idNotebook int64) (*models.Notebook, error) {
+	const op = "storage.sqlite.GetNotebook"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	notebook := &models.Notebook{}
+
+	err := s.db.QueryRowContext(ctx, `SELECT id, user_id, parent_id, name, created_at, updated_at
+							 FROM notebooks
+							 WHERE user_id = $1 AND id = $2`, idUser, idNotebook).Scan(
+		&notebook.ID,
+		&notebook.UserID,
+		&notebook.ParentID,
+		&notebook.Name,
+		&notebook.CreatedAt,
+		&notebook.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNotebookNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return notebook, nil
+}
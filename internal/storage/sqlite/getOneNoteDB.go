@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+func (s *Storage) GetOneNote(ctx context.Context, idUser int64, idNote int64) (*models.Note, error) {
+	const op = "storage.sqlite.GetOneNote"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	row := s.db.QueryRowContext(ctx, `SELECT n.id, n.user_id, n.title, n.content, `+noteTagsColumn+`, n.notebook_id, n.version, n.created_at, n.updated_at
+							 FROM notes n
+							 WHERE n.user_id = $1 AND n.id = $2 AND n.deleted_at IS NULL`, idUser, idNote)
+
+	note := &models.Note{}
+
+	err := row.Scan(
+		&note.ID,
+		&note.UserID,
+		&note.Title,
+		&note.Content,
+		jsonArray(&note.Tags),
+		&note.NotebookID,
+		&note.Version,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return note, nil
+}
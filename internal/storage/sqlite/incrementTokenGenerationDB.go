@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+func (s *Storage) IncrementTokenGeneration(ctx context.Context, idUser int64) (int64, error) {
+	const op = "storage.sqlite.IncrementTokenGeneration"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var generation int64
+
+	err := s.db.QueryRowContext(ctx, `UPDATE users
+							 SET token_generation = token_generation + 1
+							 WHERE id = $1
+							 RETURNING token_generation`, idUser).Scan(&generation)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storageErr.ErrUserNotFound)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return generation, nil
+}
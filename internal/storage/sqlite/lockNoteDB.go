@@ -0,0 +1,32 @@
+package sqlite
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// lockNote проверяет, что заметка существует и её версия совпадает с version
+// (оптимистичная блокировка). version == 0 — без проверки. FOR UPDATE в SQLite нет:
+// транзакция начинается с блокировки записи (_txlock=immediate), и до её конца
+// заметку никто не изменит
+func lockNote(ctx context.Context, tx *sql.Tx, idUser int64, idNote int64, version int64) error {
+	var current int64
+
+	err := tx.QueryRowContext(ctx, `SELECT version FROM notes
+						   WHERE user_id = $1 AND id = $2 AND deleted_at IS NULL`, idUser, idNote).Scan(&current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storageErr.ErrNoteNotFound
+		}
+		return fmt.Errorf("lock note: %w", err)
+	}
+
+	if version != 0 && current != version {
+		return storageErr.ErrVersionMismatch
+	}
+
+	return nil
+}
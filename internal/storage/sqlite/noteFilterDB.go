@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"NotesService/internal/storage"
+)
+
+// noteFilterWhere строит условие WHERE для заметок пользователя (алиас n) с учётом filter.
+// idUser всегда передаётся первым аргументом ($1)
+func noteFilterWhere(idUser int64, filter storage.NoteFilter) (*whereBuilder, error) {
+	b := &whereBuilder{}
+	b.and("n.user_id = " + b.arg(idUser))
+	b.and("n.deleted_at IS NULL")
+
+	// Фильтр по тегам: в режиме OR достаточно одного совпадения, в режиме AND нужны все теги
+	if len(filter.Tags) > 0 {
+		tags, err := jsonValue(filter.Tags)
+		if err != nil {
+			return nil, err
+		}
+
+		tagsSubquery := `(SELECT COUNT(DISTINCT t.name) FROM note_tags nt
+			JOIN tags t ON t.id = nt.tag_id
+			WHERE nt.note_id = n.id AND t.name IN (SELECT value FROM json_each(` + b.arg(tags) + `)))`
+
+		if filter.MatchAllTags {
+			b.and(tagsSubquery + " = " + b.arg(len(filter.Tags)))
+		} else {
+			b.and(tagsSubquery + " > 0")
+		}
+	}
+
+	// Фильтр по блокноту: с Recursive в выборку попадают и заметки из вложенных блокнотов
+	if filter.NotebookID != nil {
+		if filter.Recursive {
+			b.and(`n.notebook_id IN (
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM notebooks WHERE user_id = $1 AND id = ` + b.arg(*filter.NotebookID) + `
+				UNION ALL
+				SELECT nb.id FROM notebooks nb JOIN subtree st ON nb.parent_id = st.id
+			)
+			SELECT id FROM subtree)`)
+		} else {
+			b.and("n.notebook_id = " + b.arg(*filter.NotebookID))
+		}
+	}
+
+	// Диапазоны дат: нижняя граница включается, верхняя — нет
+	if filter.CreatedFrom != nil {
+		b.and("n.created_at >= " + b.arg(timestamp(*filter.CreatedFrom)))
+	}
+	if filter.CreatedTo != nil {
+		b.and("n.created_at < " + b.arg(timestamp(*filter.CreatedTo)))
+	}
+	if filter.UpdatedFrom != nil {
+		b.and("n.updated_at >= " + b.arg(timestamp(*filter.UpdatedFrom)))
+	}
+	if filter.UpdatedTo != nil {
+		b.and("n.updated_at < " + b.arg(timestamp(*filter.UpdatedTo)))
+	}
+
+	// Поиск по заголовку без учёта регистра; % и _ из запроса сравниваются буквально
+	if filter.TitlePrefix != "" {
+		b.and(`lower_unicode(n.title) LIKE (lower_unicode(` + b.arg(likeEscape(filter.TitlePrefix)) + `) || '%') ESCAPE '\'`)
+	}
+	if filter.TitleContains != "" {
+		b.and(`lower_unicode(n.title) LIKE ('%' || lower_unicode(` + b.arg(likeEscape(filter.TitleContains)) + `) || '%') ESCAPE '\'`)
+	}
+
+	// length() считает символы, а не байты, как char_length в PostgreSQL
+	if filter.MinContentLength != nil {
+		b.and("length(n.content) >= " + b.arg(*filter.MinContentLength))
+	}
+	if filter.MaxContentLength != nil {
+		b.and("length(n.content) <= " + b.arg(*filter.MaxContentLength))
+	}
+
+	return b, nil
+}
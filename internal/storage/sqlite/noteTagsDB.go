@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// noteTagsColumn — подзапрос, возвращающий теги заметки n как JSON массив (сканируется через jsonArray)
+const noteTagsColumn = `(SELECT json_group_array(name) FROM (SELECT t.name FROM note_tags nt
+	JOIN tags t ON t.id = nt.tag_id
+	WHERE nt.note_id = n.id
+	ORDER BY t.name))`
+
+// stringArray сканирует JSON массив строк в dst. Массивов в SQLite нет, поэтому
+// списки передаются в запросы и читаются из них в JSON (аналог pq.Array)
+type stringArray struct {
+	dst *[]string
+}
+
+func jsonArray(dst *[]string) sql.Scanner {
+	return stringArray{dst: dst}
+}
+
+func (a stringArray) Scan(src any) error {
+	var data []byte
+
+	switch v := src.(type) {
+	case nil:
+		*a.dst = []string{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("scan %T into string array", src)
+	}
+
+	return json.Unmarshal(data, a.dst)
+}
+
+// jsonValue кодирует список для передачи в запрос, где он разбирается через json_each
+func jsonValue(values []string) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// setNoteTags заменяет теги заметки. Отсутствующие у пользователя теги создаются,
+// теги, которые больше не используются ни одной заметкой, удаляются
+func setNoteTags(ctx context.Context, tx *sql.Tx, idUser int64, idNote int64, tags []string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM note_tags WHERE note_id = $1`, idNote)
+	if err != nil {
+		return fmt.Errorf("delete note tags: %w", err)
+	}
+
+	if len(tags) > 0 {
+		names, err := jsonValue(tags)
+		if err != nil {
+			return fmt.Errorf("encode tags: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO tags (user_id, name)
+							 SELECT $1, value FROM json_each($2) WHERE TRUE
+							 ON CONFLICT (user_id, name) DO NOTHING`, idUser, names)
+		if err != nil {
+			return fmt.Errorf("insert tags: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO note_tags (note_id, tag_id)
+							 SELECT $1, id FROM tags
+							 WHERE user_id = $2 AND name IN (SELECT value FROM json_each($3))`, idNote, idUser, names)
+		if err != nil {
+			return fmt.Errorf("insert note tags: %w", err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM tags
+						 WHERE user_id = $1
+						   AND NOT EXISTS (SELECT 1 FROM note_tags nt WHERE nt.tag_id = tags.id)`, idUser)
+	if err != nil {
+		return fmt.Errorf("delete unused tags: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// PatchNote обновляет только переданные в changes поля заметки.
+// version — ожидаемая версия заметки (0 — без проверки)
+func (s *Storage) PatchNote(ctx context.Context, idUser int64, idNote int64, changes storage.NoteChanges, version int64) (*models.Note, error) {
+	const op = "storage.sqlite.PatchNote"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := lockNote(ctx, tx, idUser, idNote, version); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	set := []string{"version = version + 1", "updated_at = " + currentTimestamp}
+	args := []any{idUser, idNote}
+
+	// Блокнот должен принадлежать владельцу заметки; nil означает "вне блокнотов"
+	if notebookID, ok := changes[storage.NoteFieldNotebookID].(*int64); ok && notebookID != nil {
+		if err := checkNotebook(ctx, tx, idUser, *notebookID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	_, titleChanged := changes[storage.NoteFieldTitle]
+	_, contentChanged := changes[storage.NoteFieldContent]
+	if titleChanged || contentChanged {
+		patched := models.Note{}
+		err := tx.QueryRowContext(ctx, `SELECT title, content FROM notes WHERE id = $1`, idNote).Scan(&patched.Title, &patched.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		changes.Apply(&patched)
+
+		// Предыдущая версия попадает в историю в той же транзакции, что и изменение
+		if err := saveNoteRevision(ctx, tx, idUser, idNote, patched.Title, patched.Content); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	for _, f := range storage.NoteFields {
+		if value, ok := changes[f.Name]; ok && f.Column != "" {
+			args = append(args, value)
+			set = append(set, fmt.Sprintf("%s = $%d", f.Column, len(args)))
+		}
+	}
+
+	if tags, ok := changes[storage.NoteFieldTags].([]string); ok {
+		if err := setNoteTags(ctx, tx, idUser, idNote, tags); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	note := &models.Note{}
+
+	err = tx.QueryRowContext(ctx, `UPDATE notes
+						  SET `+strings.Join(set, ", ")+`
+						  WHERE user_id = $1 AND id = $2
+						  RETURNING id, user_id, title, content, notebook_id, version, created_at, updated_at`,
+		args...).Scan(
+		&note.ID,
+		&note.UserID,
+		&note.Title,
+		&note.Content,
+		&note.NotebookID,
+		&note.Version,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// RETURNING в SQLite не видит алиас таблицы, поэтому теги читаются отдельно
+	err = tx.QueryRowContext(ctx, `SELECT `+noteTagsColumn+` FROM notes n WHERE n.id = $1`, note.ID).Scan(jsonArray(&note.Tags))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return note, nil
+}
@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PruneNoteRevisions удаляет версии заметок сверх maxCount последних для каждой
+// заметки и версии, созданные раньше before. maxCount <= 0 и нулевое before
+// отключают соответствующее ограничение
+func (s *Storage) PruneNoteRevisions(ctx context.Context, maxCount int, before time.Time) (int64, error) {
+	const op = "storage.sqlite.PruneNoteRevisions"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var beforeArg *time.Time
+	if !before.IsZero() {
+		beforeArg = &before
+	}
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM note_revisions
+							  WHERE ($1 > 0 AND revision <= (SELECT n.last_revision FROM notes n WHERE n.id = note_revisions.note_id) - $1)
+							     OR ($2 IS NOT NULL AND created_at < $2)`, maxCount, nullTimestamp(beforeArg))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pruned, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pruned, nil
+}
@@ -0,0 +1,35 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PurgeExpiredTokens удаляет отозванные access-токены и refresh-токены, срок жизни которых истёк
+func (s *Storage) PurgeExpiredTokens(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.PurgeExpiredTokens"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var purged int64
+
+	for _, query := range []string{
+		`DELETE FROM revoked_tokens WHERE expires_at < $1`,
+		`DELETE FROM refresh_tokens WHERE expires_at < $1`,
+	} {
+		res, err := s.db.ExecContext(ctx, query, timestamp(before))
+		if err != nil {
+			return purged, fmt.Errorf("%s: %w", op, err)
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return purged, fmt.Errorf("%s: %w", op, err)
+		}
+		purged += rowsAffected
+	}
+
+	return purged, nil
+}
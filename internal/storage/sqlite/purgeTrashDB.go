@@ -0,0 +1,27 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PurgeTrash окончательно удаляет заметки, попавшие в корзину раньше before
+func (s *Storage) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.PurgeTrash"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM notes WHERE deleted_at < $1`, timestamp(before))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+func (s *Storage) PutNote(ctx context.Context, idUser int64, idNote int64, title string, content string, tags []string, notebookID *int64, version int64) (*models.Note, error) {
+	const op = "storage.sqlite.PutNote"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := lockNote(ctx, tx, idUser, idNote, version); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Предыдущая версия попадает в историю в той же транзакции, что и изменение
+	if err := saveNoteRevision(ctx, tx, idUser, idNote, title, content); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Блокнот должен принадлежать владельцу заметки; 0 означает "вне блокнотов"
+	if notebookID != nil && *notebookID != 0 {
+		if err := checkNotebook(ctx, tx, idUser, *notebookID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	note := &models.Note{}
+
+	err = tx.QueryRowContext(ctx, `UPDATE notes
+						  SET title = $3,
+						      content = $4,
+						      notebook_id = CASE WHEN $5 IS NULL THEN notebook_id ELSE NULLIF($5, 0) END,
+						      version = version + 1,
+						      updated_at = `+currentTimestamp+`
+						  WHERE user_id = $1 AND id = $2 AND deleted_at IS NULL
+						  RETURNING id, user_id, title, content, notebook_id, version, created_at, updated_at`, idUser, idNote, title, content, notebookID).Scan(
+		&note.ID,
+		&note.UserID,
+		&note.Title,
+		&note.Content,
+		&note.NotebookID,
+		&note.Version,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Владелец тегов — автор заметки, а не тот, кто её редактирует
+	if tags != nil {
+		if err := setNoteTags(ctx, tx, note.UserID, note.ID, tags); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `SELECT `+noteTagsColumn+` FROM notes n WHERE n.id = $1`, note.ID).Scan(jsonArray(&note.Tags))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return note, nil
+}
@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// PutNotebook переименовывает блокнот и переносит его к новому родителю.
+// Вложенные блокноты и заметки ссылаются на блокнот, поэтому переезжают вместе с ним
+func (s *Storage) PutNotebook(ctx context.Context, idUser int64, idNotebook int64, name string, parentID *int64) (*models.Notebook, error) {
+	const op = "storage.sqlite.PutNotebook"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	// Транзакция с самого начала держит блокировку записи, поэтому встречный перенос
+	// не может выполниться между проверкой на цикл и обновлением
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if parentID != nil {
+		if err := checkNotebook(ctx, tx, idUser, *parentID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		var cycle bool
+		err = tx.QueryRowContext(ctx, `WITH RECURSIVE subtree AS (
+								SELECT id FROM notebooks WHERE user_id = $1 AND id = $2
+								UNION ALL
+								SELECT nb.id FROM notebooks nb JOIN subtree st ON nb.parent_id = st.id
+							 )
+							 SELECT EXISTS (SELECT 1 FROM subtree WHERE id = $3)`, idUser, idNotebook, *parentID).Scan(&cycle)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if cycle {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNotebookCycle)
+		}
+	}
+
+	notebook := &models.Notebook{}
+
+	err = tx.QueryRowContext(ctx, `UPDATE notebooks
+						  SET name = $3,
+						      parent_id = $4,
+						      updated_at = `+currentTimestamp+`
+						  WHERE user_id = $1 AND id = $2
+						  RETURNING id, user_id, parent_id, name, created_at, updated_at`, idUser, idNotebook, name, parentID).Scan(
+		&notebook.ID,
+		&notebook.UserID,
+		&notebook.ParentID,
+		&notebook.Name,
+		&notebook.CreatedAt,
+		&notebook.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNotebookNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return notebook, nil
+}
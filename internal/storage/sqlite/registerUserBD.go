@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"errors"
+	"fmt"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+func (s *Storage) RegisterUser(ctx context.Context, userName string, passwordHash string) (*models.User, error) {
+	const op = "storage.sqlite.RegisterUser"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	user := &models.User{
+		Username:     userName,
+		PasswordHash: passwordHash,
+	}
+
+	err := s.db.QueryRowContext(ctx, `INSERT INTO users (user_name, password_hash)
+							 VALUES ($1, $2)
+							 RETURNING id, user_name, created_at`, userName, passwordHash).Scan(&user.ID, &user.Username, &user.CreatedAt)
+	if err != nil {
+		var sqliteErr *sqlite.Error
+		// Имя пользователя уже занято
+		if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrUserExists)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return user, nil
+}
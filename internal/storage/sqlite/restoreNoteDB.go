@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// RestoreNote возвращает заметку из корзины
+func (s *Storage) RestoreNote(ctx context.Context, idUser int64, idNote int64) (*models.Note, error) {
+	const op = "storage.sqlite.RestoreNote"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	note := &models.Note{}
+
+	err = tx.QueryRowContext(ctx, `UPDATE notes
+						  SET deleted_at = NULL
+						  WHERE user_id = $1 AND id = $2 AND deleted_at IS NOT NULL
+						  RETURNING id, user_id, title, content, notebook_id, version, created_at, updated_at`,
+		idUser, idNote).Scan(
+		&note.ID,
+		&note.UserID,
+		&note.Title,
+		&note.Content,
+		&note.NotebookID,
+		&note.Version,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = tx.QueryRowContext(ctx, `SELECT `+noteTagsColumn+` FROM notes n WHERE n.id = $1`, note.ID).Scan(jsonArray(&note.Tags))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return note, nil
+}
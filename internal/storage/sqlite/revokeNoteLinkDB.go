@@ -0,0 +1,32 @@
+package sqlite
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+)
+
+func (s *Storage) RevokeNoteLink(ctx context.Context, idOwner int64, idNote int64, idLink int64) error {
+	const op = "storage.sqlite.RevokeNoteLink"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `UPDATE note_links
+								SET revoked_at = COALESCE(revoked_at, `+currentTimestamp+`)
+								WHERE id = $3
+								  AND note_id IN (SELECT id FROM notes WHERE user_id = $1 AND id = $2)`, idOwner, idNote, idLink)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storageErr.ErrLinkNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package sqlite
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+)
+
+// RevokeRefreshTokenFamily отзывает все токены из семейства, к которому относится tokenHash
+func (s *Storage) RevokeRefreshTokenFamily(ctx context.Context, idUser int64, tokenHash string) error {
+	const op = "storage.sqlite.RevokeRefreshTokenFamily"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `UPDATE refresh_tokens
+								SET revoked_at = COALESCE(revoked_at, `+currentTimestamp+`)
+								WHERE family_id = (SELECT family_id FROM refresh_tokens
+								                   WHERE token_hash = $2 AND user_id = $1)`, idUser, tokenHash)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storageErr.ErrRefreshTokenNotFound)
+	}
+
+	return nil
+}
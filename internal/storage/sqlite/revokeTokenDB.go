@@ -0,0 +1,23 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (s *Storage) RevokeToken(ctx context.Context, jti string, idUser int64, expiresAt time.Time) error {
+	const op = "storage.sqlite.RevokeToken"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `INSERT INTO revoked_tokens (jti, user_id, expires_at)
+							VALUES ($1, $2, $3)
+							ON CONFLICT (jti) DO NOTHING`, jti, idUser, timestamp(expiresAt))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+func (s *Storage) RevokeUserRefreshTokens(ctx context.Context, idUser int64) error {
+	const op = "storage.sqlite.RevokeUserRefreshTokens"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `UPDATE refresh_tokens
+							SET revoked_at = `+currentTimestamp+`
+							WHERE user_id = $1 AND revoked_at IS NULL`, idUser)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
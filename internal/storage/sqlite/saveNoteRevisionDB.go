@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// saveNoteRevision сохраняет текущую версию заметки в историю, если новые title
+// и content от неё отличаются. Вызывается перед изменением заметки
+func saveNoteRevision(ctx context.Context, tx *sql.Tx, idUser int64, idNote int64, title string, content string) error {
+	var (
+		oldTitle   string
+		oldContent string
+		updatedAt  time.Time
+	)
+
+	err := tx.QueryRowContext(ctx, `SELECT title, content, updated_at FROM notes
+						   WHERE user_id = $1 AND id = $2 AND deleted_at IS NULL`, idUser, idNote).Scan(&oldTitle, &oldContent, &updatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storageErr.ErrNoteNotFound
+		}
+		return fmt.Errorf("lock note: %w", err)
+	}
+
+	if oldTitle == title && oldContent == content {
+		return nil
+	}
+
+	var revision int64
+	err = tx.QueryRowContext(ctx, `UPDATE notes SET last_revision = last_revision + 1
+						  WHERE id = $1
+						  RETURNING last_revision`, idNote).Scan(&revision)
+	if err != nil {
+		return fmt.Errorf("next revision: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO note_revisions (note_id, revision, title, content, created_at)
+						 VALUES ($1, $2, $3, $4, $5)`, idNote, revision, oldTitle, oldContent, timestamp(updatedAt))
+	if err != nil {
+		return fmt.Errorf("insert revision: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (s *Storage) SaveRefreshToken(ctx context.Context, idUser int64, tokenHash string, familyID string, expiresAt time.Time) error {
+	const op = "storage.sqlite.SaveRefreshToken"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `INSERT INTO refresh_tokens (user_id, token_hash, family_id, expires_at)
+								VALUES ($1, $2, $3, $4)`, idUser, tokenHash, familyID, timestamp(expiresAt))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package sqlite
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SearchNotes ищет заметки пользователя по заголовку и тексту через FTS5. Результаты
+// упорядочены по релевантности (bm25 с весами заголовка и текста, как setweight
+// в postgresql), найденные слова подсвечиваются highlight и snippet
+func (s *Storage) SearchNotes(ctx context.Context, idUser int64, query string, limit int, offset int) ([]*models.NoteSearchResult, error) {
+	const op = "storage.sqlite.SearchNotes"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	groups := buildFTSQuery(query)
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, storageErr.ErrEmptySearchQuery)
+	}
+
+	b := &whereBuilder{}
+	b.and("n.user_id = " + b.arg(idUser))
+	b.and("n.deleted_at IS NULL")
+
+	// Каждый терм проверяется отдельным MATCH, а группы объединяются через OR.
+	// Все положительные термы вместе дают выражение для ранжирования и подсветки
+	var (
+		alternatives []string
+		positive     []string
+	)
+	for _, group := range groups {
+		var conds []string
+		for _, term := range group {
+			in := "IN"
+			if term.negate {
+				in = "NOT IN"
+			} else {
+				positive = append(positive, term.match)
+			}
+			conds = append(conds, "n.id "+in+" (SELECT rowid FROM notes_fts WHERE notes_fts MATCH "+b.arg(term.match)+")")
+		}
+		alternatives = append(alternatives, "("+strings.Join(conds, " AND ")+")")
+	}
+	b.and("(" + strings.Join(alternatives, " OR ") + ")")
+
+	// Запрос только из исключений ("-слово") ничего не подсвечивает, ранг у таких заметок 0
+	ranked := `(SELECT NULL AS rowid, NULL AS rank, NULL AS title_hl, NULL AS snippet)`
+	if len(positive) > 0 {
+		start, stop := b.arg(storage.HighlightStart), b.arg(storage.HighlightStop)
+		ranked = `(SELECT rowid, -bm25(notes_fts, 1.0, 0.4) AS rank,
+			highlight(notes_fts, 0, ` + start + `, ` + stop + `) AS title_hl,
+			snippet(notes_fts, 1, ` + start + `, ` + stop + `, ' ... ', 30) AS snippet
+			FROM notes_fts WHERE notes_fts MATCH ` + b.arg(strings.Join(positive, " OR ")) + `)`
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+	SELECT n.id, n.user_id, n.title, n.content, `+noteTagsColumn+`, n.notebook_id, n.created_at, n.updated_at,
+	       COALESCE(f.rank, 0) AS rank,
+	       COALESCE(f.title_hl, n.title),
+	       COALESCE(f.snippet, substr(n.content, 1, 200))
+	FROM notes n
+	LEFT JOIN `+ranked+` f ON f.rowid = n.id
+	WHERE `+b.String()+`
+	ORDER BY rank DESC, n.updated_at DESC
+	LIMIT `+b.arg(limit)+`
+	OFFSET `+b.arg(offset), b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	results := []*models.NoteSearchResult{}
+
+	for rows.Next() {
+		result := &models.NoteSearchResult{}
+
+		err := rows.Scan(
+			&result.ID,
+			&result.UserID,
+			&result.Title,
+			&result.Content,
+			jsonArray(&result.Tags),
+			&result.NotebookID,
+			&result.CreatedAt,
+			&result.UpdatedAt,
+			&result.Rank,
+			&result.TitleHighlight,
+			&result.Snippet,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration: %w", op, err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ShareNote выдаёт пользователю idUser доступ к заметке владельца idOwner.
+// Если доступ уже был выдан, права заменяются
+func (s *Storage) ShareNote(ctx context.Context, idOwner int64, idNote int64, idUser int64, permission string) (*models.NoteShare, error) {
+	const op = "storage.sqlite.ShareNote"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	share := &models.NoteShare{}
+
+	err := s.db.QueryRowContext(ctx, `INSERT INTO note_shares (note_id, user_id, permission)
+							 SELECT n.id, $3, $4 FROM notes n
+							 WHERE n.user_id = $1 AND n.id = $2 AND n.deleted_at IS NULL
+							 ON CONFLICT (note_id, user_id) DO UPDATE SET permission = excluded.permission
+							 RETURNING note_id, user_id, permission, created_at`, idOwner, idNote, idUser, permission).Scan(
+		&share.NoteID,
+		&share.UserID,
+		&share.Permission,
+		&share.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storageErr.ErrNoteNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return share, nil
+}
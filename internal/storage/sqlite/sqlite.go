@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"NotesService/internal/storage/migrator"
+	"NotesService/migrations"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// options — параметры соединения:
+//   - foreign_keys включает каскадное удаление, как в PostgreSQL
+//   - WAL позволяет читать базу во время записи
+//   - busy_timeout ждёт освобождения базы вместо немедленной ошибки SQLITE_BUSY
+//   - _txlock=immediate берёт блокировку записи в начале транзакции, поэтому
+//     транзакции не конфликтуют между собой и заменяют SELECT ... FOR UPDATE
+const options = "_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_pragma=synchronous(NORMAL)&_txlock=immediate"
+
+func init() {
+	// lower() в SQLite приводит к нижнему регистру только ASCII, а ILIKE в PostgreSQL —
+	// любые буквы. lower_unicode используется для поиска по заголовку без учёта регистра
+	sqlite.MustRegisterDeterministicScalarFunction("lower_unicode", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return args[0], nil
+		}
+		return strings.ToLower(s), nil
+	})
+}
+
+type Storage struct {
+	db *sql.DB
+	// Предельное время одного вызова хранилища
+	queryTimeout time.Duration
+}
+
+// New открывает файл базы storagePath, при необходимости создавая его и каталог для него
+func New(storagePath string, queryTimeout time.Duration) (*Storage, error) {
+	const op = "storage.sqlite.New"
+
+	if dir := filepath.Dir(storagePath); dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", "file:"+storagePath+"?"+options)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Файл открывается лениво, поэтому ошибки пути и прав проверяются сразу
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Storage{db: db, queryTimeout: queryTimeout}, nil
+}
+
+// Ping проверяет, что база доступна
+func (s *Storage) Ping(ctx context.Context) error {
+	const op = "storage.sqlite.Ping"
+
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Stats возвращает состояние пула соединений с базой
+func (s *Storage) Stats() sql.DBStats {
+	return s.db.Stats()
+}
+
+// Close закрывает соединения с базой
+func (s *Storage) Close() error {
+	const op = "storage.sqlite.Close"
+
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Migrator возвращает мигратор схемы с миграциями, встроенными из migrations/sqlite/
+func (s *Storage) Migrator(log *slog.Logger) (*migrator.Migrator, error) {
+	return migrator.New(log, s.db, migrator.SQLite, migrations.SQLite())
+}
+
+// withTimeout ограничивает вызов хранилища queryTimeout. Отмена ctx (клиент отключился,
+// истёк таймаут запроса) прерывает запрос к базе раньше
+func (s *Storage) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, s.queryTimeout)
+}
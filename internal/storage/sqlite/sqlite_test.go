@@ -0,0 +1,33 @@
+package sqlite_test
+
+import (
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/sqlite"
+	"NotesService/internal/storage/storagetest"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConformance(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		s, err := sqlite.New(filepath.Join(t.TempDir(), "db.sqlite"), 5*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+
+		m, err := s.Migrator(log)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m.Up(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		return s
+	})
+}
@@ -0,0 +1,24 @@
+package sqlite
+
+import "time"
+
+// timeLayout — формат времени в базе: UTC с микросекундами. Строки одной длины
+// сравниваются так же, как моменты времени, поэтому работают ORDER BY и фильтры по датам
+const timeLayout = "2006-01-02 15:04:05.000000"
+
+// currentTimestamp — текущее время в формате timeLayout, аналог CURRENT_TIMESTAMP
+const currentTimestamp = `strftime('%Y-%m-%d %H:%M:%f000', 'now')`
+
+// timestamp переводит время в формат timeLayout. Драйвер записывает time.Time
+// строкой переменной длины, поэтому время всегда передаётся в запрос через timestamp
+func timestamp(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// nullTimestamp — timestamp для необязательного времени, nil записывается как NULL
+func nullTimestamp(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
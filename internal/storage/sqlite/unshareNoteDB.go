@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"fmt"
+)
+
+func (s *Storage) UnshareNote(ctx context.Context, idOwner int64, idNote int64, idUser int64) error {
+	const op = "storage.sqlite.UnshareNote"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM note_shares
+								WHERE user_id = $3
+								  AND note_id IN (SELECT id FROM notes WHERE user_id = $1 AND id = $2)`, idOwner, idNote, idUser)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storageErr.ErrShareNotFound)
+	}
+
+	return nil
+}
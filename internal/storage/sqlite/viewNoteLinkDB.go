@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage/storageErr"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ViewNoteLink увеличивает счётчик просмотров действующей ссылки
+// и возвращает заметку вместе с новым значением счётчика
+func (s *Storage) ViewNoteLink(ctx context.Context, idLink int64) (*models.Note, int64, error) {
+	const op = "storage.sqlite.ViewNoteLink"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var (
+		idNote int64
+		views  int64
+	)
+
+	// UPDATE внутри WITH в SQLite не поддерживается, поэтому счётчик и заметка
+	// читаются двумя запросами в одной транзакции
+	err = tx.QueryRowContext(ctx, `UPDATE note_links
+						  SET view_count = view_count + 1
+						  WHERE id = $1
+						    AND revoked_at IS NULL
+						    AND (expires_at IS NULL OR expires_at > `+currentTimestamp+`)
+						    AND EXISTS (SELECT 1 FROM notes WHERE notes.id = note_links.note_id AND notes.deleted_at IS NULL)
+						  RETURNING note_id, view_count`, idLink).Scan(&idNote, &views)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, 0, fmt.Errorf("%s: %w", op, storageErr.ErrLinkNotFound)
+		}
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	note := &models.Note{}
+
+	err = tx.QueryRowContext(ctx, `SELECT id, user_id, title, content, created_at, updated_at
+						  FROM notes
+						  WHERE id = $1`, idNote).Scan(
+		&note.ID,
+		&note.UserID,
+		&note.Title,
+		&note.Content,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return note, views, nil
+}
@@ -0,0 +1,38 @@
+package sqlite
+
+import (
+	"strconv"
+	"strings"
+)
+
+// whereBuilder собирает условие WHERE из фрагментов SQL, написанных в коде.
+// Пользовательские значения в текст запроса не попадают: arg добавляет значение
+// в аргументы запроса и возвращает его плейсхолдер $N
+type whereBuilder struct {
+	conds []string
+	args  []any
+}
+
+// arg добавляет значение в аргументы запроса и возвращает плейсхолдер для него
+func (b *whereBuilder) arg(value any) string {
+	b.args = append(b.args, value)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+// and добавляет условие, объединяемое с остальными через AND
+func (b *whereBuilder) and(cond string) {
+	b.conds = append(b.conds, cond)
+}
+
+func (b *whereBuilder) String() string {
+	if len(b.conds) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(b.conds, " AND ")
+}
+
+// likeEscape экранирует спецсимволы LIKE, чтобы значение сравнивалось буквально.
+// Запрос должен указывать ESCAPE '\'
+func likeEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -3,6 +3,21 @@ package storageErr
 import "errors"
 
 var (
-	ErrNoteNotFound = errors.New("Note not found")
-	ErrUserNotFound = errors.New("User not found")
+	ErrNoteNotFound  = errors.New("Note not found")
+	ErrUserNotFound  = errors.New("User not found")
+	ErrUserExists    = errors.New("User already exists")
+	ErrShareNotFound = errors.New("Share not found")
+	ErrLinkNotFound  = errors.New("Link not found")
+
+	ErrRevisionNotFound = errors.New("Revision not found")
+	ErrVersionMismatch  = errors.New("Note version mismatch")
+
+	ErrNotebookNotFound = errors.New("Notebook not found")
+	ErrNotebookCycle    = errors.New("Notebook cannot be moved into its own subtree")
+
+	ErrEmptySearchQuery = errors.New("Search query is empty")
+
+	ErrRefreshTokenNotFound = errors.New("Refresh token not found")
+	ErrRefreshTokenExpired  = errors.New("Refresh token expired")
+	ErrRefreshTokenReused   = errors.New("Refresh token reused")
 )
@@ -0,0 +1,802 @@
+// Package storagetest — общий набор проверок, который должна проходить каждая
+// реализация storage.Storage. Тест реализации вызывает Run с функцией, создающей
+// пустое хранилище:
+//
+//	func TestConformance(t *testing.T) {
+//		storagetest.Run(t, func(t *testing.T) storage.Storage { return memory.New() })
+//	}
+package storagetest
+
+import (
+	"NotesService/internal/models"
+	"NotesService/internal/storage"
+	"NotesService/internal/storage/storageErr"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Factory создаёт пустое хранилище для одного теста
+type Factory func(t *testing.T) storage.Storage
+
+// Run запускает все проверки. Каждая проверка получает новое хранилище
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s storage.Storage)
+	}{
+		{"Users", testUsers},
+		{"SetUserPassword", testSetUserPassword},
+		{"NoteCRUD", testNoteCRUD},
+		{"NoteVersion", testNoteVersion},
+		{"PatchNote", testPatchNote},
+		{"Pagination", testPagination},
+		{"Filter", testFilter},
+		{"Tags", testTags},
+		{"Notebooks", testNotebooks},
+		{"Trash", testTrash},
+		{"Revisions", testRevisions},
+		{"Search", testSearch},
+		{"Shares", testShares},
+		{"Links", testLinks},
+		{"RefreshTokens", testRefreshTokens},
+		{"RevokedTokens", testRevokedTokens},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStorage(t))
+		})
+	}
+}
+
+func testUsers(t *testing.T, s storage.Storage) {
+	u := mustUser(t, s, "alice")
+
+	if _, err := s.RegisterUser(t.Context(), "alice", "hash"); !errors.Is(err, storageErr.ErrUserExists) {
+		t.Fatalf("RegisterUser duplicate: got %v, want ErrUserExists", err)
+	}
+
+	byName, err := s.GetUserByName(t.Context(), "alice")
+	if err != nil {
+		t.Fatalf("GetUserByName: %v", err)
+	}
+	if byName.ID != u.ID || byName.PasswordHash != "hash" {
+		t.Fatalf("GetUserByName: got %+v", byName)
+	}
+
+	byID, err := s.GetUserByID(t.Context(), u.ID)
+	if err != nil || byID.Username != "alice" {
+		t.Fatalf("GetUserByID: got %+v, %v", byID, err)
+	}
+
+	if _, err := s.GetUserByName(t.Context(), "bob"); !errors.Is(err, storageErr.ErrUserNotFound) {
+		t.Fatalf("GetUserByName missing: got %v, want ErrUserNotFound", err)
+	}
+	if _, err := s.GetUserByID(t.Context(), u.ID+100); !errors.Is(err, storageErr.ErrUserNotFound) {
+		t.Fatalf("GetUserByID missing: got %v, want ErrUserNotFound", err)
+	}
+}
+
+func testSetUserPassword(t *testing.T, s storage.Storage) {
+	u := mustUser(t, s, "alice")
+	other := mustUser(t, s, "bob")
+
+	if err := s.SetUserPassword(t.Context(), u.ID, "new-hash"); err != nil {
+		t.Fatalf("SetUserPassword: %v", err)
+	}
+
+	got, err := s.GetUserByName(t.Context(), "alice")
+	if err != nil {
+		t.Fatalf("GetUserByName: %v", err)
+	}
+	if got.PasswordHash != "new-hash" {
+		t.Fatalf("GetUserByName after SetUserPassword: got hash %q, want %q", got.PasswordHash, "new-hash")
+	}
+
+	if got, err := s.GetUserByName(t.Context(), "bob"); err != nil || got.PasswordHash != "hash" {
+		t.Fatalf("SetUserPassword changed another user: got %+v, %v", got, err)
+	}
+
+	if err := s.SetUserPassword(t.Context(), other.ID+100, "hash"); !errors.Is(err, storageErr.ErrUserNotFound) {
+		t.Fatalf("SetUserPassword missing: got %v, want ErrUserNotFound", err)
+	}
+}
+
+func testNoteCRUD(t *testing.T, s storage.Storage) {
+	u := mustUser(t, s, "alice")
+	other := mustUser(t, s, "bob")
+
+	n := mustNote(t, s, u.ID, "title", "content")
+	if n.ID == 0 || n.UserID != u.ID || n.Version != 1 || n.CreatedAt.IsZero() {
+		t.Fatalf("SaveNotes: got %+v", n)
+	}
+
+	got, err := s.GetOneNote(t.Context(), u.ID, n.ID)
+	if err != nil {
+		t.Fatalf("GetOneNote: %v", err)
+	}
+	if got.Title != "title" || got.Content != "content" || len(got.Tags) != 0 {
+		t.Fatalf("GetOneNote: got %+v", got)
+	}
+
+	if _, err := s.GetOneNote(t.Context(), other.ID, n.ID); !errors.Is(err, storageErr.ErrNoteNotFound) {
+		t.Fatalf("GetOneNote of other user: got %v, want ErrNoteNotFound", err)
+	}
+
+	updated, err := s.PutNote(t.Context(), u.ID, n.ID, "new title", "new content", []string{"b", "a"}, nil, 0)
+	if err != nil {
+		t.Fatalf("PutNote: %v", err)
+	}
+	if updated.Title != "new title" || updated.Version != 2 || !slices.Equal(updated.Tags, []string{"a", "b"}) {
+		t.Fatalf("PutNote: got %+v", updated)
+	}
+
+	// nil теги не меняются
+	updated, err = s.PutNote(t.Context(), u.ID, n.ID, "new title", "new content", nil, nil, 0)
+	if err != nil || !slices.Equal(updated.Tags, []string{"a", "b"}) {
+		t.Fatalf("PutNote with nil tags: got %+v, %v", updated, err)
+	}
+
+	if _, err := s.PutNote(t.Context(), u.ID, n.ID+100, "t", "c", nil, nil, 0); !errors.Is(err, storageErr.ErrNoteNotFound) {
+		t.Fatalf("PutNote missing: got %v, want ErrNoteNotFound", err)
+	}
+
+	if err := s.DeleteNote(t.Context(), u.ID, n.ID, 0); err != nil {
+		t.Fatalf("DeleteNote: %v", err)
+	}
+	if _, err := s.GetOneNote(t.Context(), u.ID, n.ID); !errors.Is(err, storageErr.ErrNoteNotFound) {
+		t.Fatalf("GetOneNote after delete: got %v, want ErrNoteNotFound", err)
+	}
+	if err := s.DeleteNote(t.Context(), u.ID, n.ID, 0); !errors.Is(err, storageErr.ErrNoteNotFound) {
+		t.Fatalf("DeleteNote twice: got %v, want ErrNoteNotFound", err)
+	}
+}
+
+func testNoteVersion(t *testing.T, s storage.Storage) {
+	u := mustUser(t, s, "alice")
+	n := mustNote(t, s, u.ID, "title", "content")
+
+	if _, err := s.PutNote(t.Context(), u.ID, n.ID, "t", "c", nil, nil, n.Version+1); !errors.Is(err, storageErr.ErrVersionMismatch) {
+		t.Fatalf("PutNote stale version: got %v, want ErrVersionMismatch", err)
+	}
+
+	updated, err := s.PutNote(t.Context(), u.ID, n.ID, "t", "c", nil, nil, n.Version)
+	if err != nil {
+		t.Fatalf("PutNote current version: %v", err)
+	}
+
+	if err := s.DeleteNote(t.Context(), u.ID, n.ID, n.Version); !errors.Is(err, storageErr.ErrVersionMismatch) {
+		t.Fatalf("DeleteNote stale version: got %v, want ErrVersionMismatch", err)
+	}
+	if err := s.DeleteNote(t.Context(), u.ID, n.ID, updated.Version); err != nil {
+		t.Fatalf("DeleteNote current version: %v", err)
+	}
+}
+
+func testPatchNote(t *testing.T, s storage.Storage) {
+	u := mustUser(t, s, "alice")
+	nb := mustNotebook(t, s, u.ID, "work", nil)
+	n := mustNote(t, s, u.ID, "title", "content")
+
+	patched, err := s.PatchNote(t.Context(), u.ID, n.ID, storage.NoteChanges{
+		storage.NoteFieldTitle:      "patched",
+		storage.NoteFieldNotebookID: &nb.ID,
+	}, n.Version)
+	if err != nil {
+		t.Fatalf("PatchNote: %v", err)
+	}
+	if patched.Title != "patched" || patched.Content != "content" || patched.NotebookID == nil || *patched.NotebookID != nb.ID || patched.Version != 2 {
+		t.Fatalf("PatchNote: got %+v", patched)
+	}
+
+	patched, err = s.PatchNote(t.Context(), u.ID, n.ID, storage.NoteChanges{
+		storage.NoteFieldNotebookID: (*int64)(nil),
+		storage.NoteFieldTags:       []string{"x"},
+	}, 0)
+	if err != nil || patched.NotebookID != nil || !slices.Equal(patched.Tags, []string{"x"}) {
+		t.Fatalf("PatchNote notebook and tags: got %+v, %v", patched, err)
+	}
+
+	missing := nb.ID + 100
+	if _, err := s.PatchNote(t.Context(), u.ID, n.ID, storage.NoteChanges{storage.NoteFieldNotebookID: &missing}, 0); !errors.Is(err, storageErr.ErrNotebookNotFound) {
+		t.Fatalf("PatchNote missing notebook: got %v, want ErrNotebookNotFound", err)
+	}
+	if _, err := s.PatchNote(t.Context(), u.ID, n.ID, storage.NoteChanges{storage.NoteFieldTitle: "stale"}, 1); !errors.Is(err, storageErr.ErrVersionMismatch) {
+		t.Fatalf("PatchNote stale version: got %v, want ErrVersionMismatch", err)
+	}
+
+	// Каждое поле из storage.NoteFields сохраняется по отдельности и читается обратно.
+	// Для нового поля нужно только добавить сюда значение, отличное от значения mustNote
+	values := map[string]any{
+		storage.NoteFieldTitle:      "new title",
+		storage.NoteFieldContent:    "new content",
+		storage.NoteFieldTags:       []string{"a", "b"},
+		storage.NoteFieldNotebookID: &nb.ID,
+	}
+	for _, f := range storage.NoteFields {
+		value, ok := values[f.Name]
+		if !ok {
+			t.Errorf("PatchNote: no test value for field %q", f.Name)
+			continue
+		}
+
+		fresh := mustNote(t, s, u.ID, "title", "content")
+		patched, err := s.PatchNote(t.Context(), u.ID, fresh.ID, storage.NoteChanges{f.Name: value}, fresh.Version)
+		if err != nil {
+			t.Fatalf("PatchNote %s: %v", f.Name, err)
+		}
+		if !f.Equal(f.Get(patched), value) {
+			t.Errorf("PatchNote %s: got %v, want %v", f.Name, f.Get(patched), value)
+		}
+
+		stored, err := s.GetOneNote(t.Context(), u.ID, fresh.ID)
+		if err != nil {
+			t.Fatalf("GetOneNote after PatchNote %s: %v", f.Name, err)
+		}
+		if !f.Equal(f.Get(stored), value) {
+			t.Errorf("GetOneNote after PatchNote %s: got %v, want %v", f.Name, f.Get(stored), value)
+		}
+		for _, other := range storage.NoteFields {
+			if other.Name != f.Name && !other.Equal(other.Get(stored), other.Get(fresh)) {
+				t.Errorf("PatchNote %s changed %s: got %v, want %v", f.Name, other.Name, other.Get(stored), other.Get(fresh))
+			}
+		}
+	}
+}
+
+func testPagination(t *testing.T, s storage.Storage) {
+	u := mustUser(t, s, "alice")
+
+	var ids []int64
+	for _, title := range []string{"c", "a", "e", "b", "d"} {
+		ids = append(ids, mustNote(t, s, u.ID, title, "content").ID)
+		// created_at должен различаться, иначе порядок определяет только id
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	page := storage.NotePage{Limit: 2, Sort: storage.NoteSortCreatedAt, Desc: true}
+	first := mustList(t, s, u.ID, page)
+	if !slices.Equal(noteIDs(first), []int64{ids[4], ids[3]}) {
+		t.Fatalf("first page: got %v", noteIDs(first))
+	}
+
+	page.After = keyset(first[len(first)-1], page.Sort)
+	second := mustList(t, s, u.ID, page)
+	if !slices.Equal(noteIDs(second), []int64{ids[2], ids[1]}) {
+		t.Fatalf("second page: got %v", noteIDs(second))
+	}
+
+	page.After = keyset(second[0], page.Sort)
+	page.Backward = true
+	back := mustList(t, s, u.ID, page)
+	if !slices.Equal(noteIDs(back), []int64{ids[4], ids[3]}) {
+		t.Fatalf("previous page: got %v", noteIDs(back))
+	}
+
+	offset := mustList(t, s, u.ID, storage.NotePage{Limit: 2, Offset: 4, Sort: storage.NoteSortCreatedAt, Desc: true})
+	if !slices.Equal(noteIDs(offset), []int64{ids[0]}) {
+		t.Fatalf("offset page: got %v", noteIDs(offset))
+	}
+
+	byTitle := mustList(t, s, u.ID, storage.NotePage{Limit: 10, Sort: storage.NoteSortTitle})
+	var titles []string
+	for _, n := range byTitle {
+		titles = append(titles, n.Title)
+	}
+	if !slices.Equal(titles, []string{"a", "b", "c", "d", "e"}) {
+		t.Fatalf("sort by title: got %v", titles)
+	}
+
+	titlePage := storage.NotePage{Limit: 2, Sort: storage.NoteSortTitle, After: keyset(byTitle[1], storage.NoteSortTitle)}
+	if got := mustList(t, s, u.ID, titlePage); len(got) != 2 || got[0].Title != "c" || got[1].Title != "d" {
+		t.Fatalf("title keyset page: got %v", noteIDs(got))
+	}
+
+	total, err := s.CountNotes(t.Context(), u.ID, storage.NoteFilter{})
+	if err != nil || total != 5 {
+		t.Fatalf("CountNotes: got %d, %v", total, err)
+	}
+}
+
+func testFilter(t *testing.T, s storage.Storage) {
+	u := mustUser(t, s, "alice")
+	parent := mustNotebook(t, s, u.ID, "parent", nil)
+	child := mustNotebook(t, s, u.ID, "child", &parent.ID)
+
+	work, _, err := s.SaveNotes(t.Context(), "Work plan", "short", u.ID, []string{"work", "plan"}, &parent.ID)
+	if err != nil {
+		t.Fatalf("SaveNotes: %v", err)
+	}
+	home, _, err := s.SaveNotes(t.Context(), "Home 50%_off", "a much longer content", u.ID, []string{"home"}, &child.ID)
+	if err != nil {
+		t.Fatalf("SaveNotes: %v", err)
+	}
+	loose := mustNote(t, s, u.ID, "Workout", "gym")
+
+	cases := []struct {
+		name   string
+		filter storage.NoteFilter
+		want   []int64
+	}{
+		{"tags or", storage.NoteFilter{Tags: []string{"work", "home"}}, []int64{work.ID, home.ID}},
+		{"tags and", storage.NoteFilter{Tags: []string{"work", "plan"}, MatchAllTags: true}, []int64{work.ID}},
+		{"notebook", storage.NoteFilter{NotebookID: &parent.ID}, []int64{work.ID}},
+		{"notebook recursive", storage.NoteFilter{NotebookID: &parent.ID, Recursive: true}, []int64{work.ID, home.ID}},
+		{"title prefix", storage.NoteFilter{TitlePrefix: "work"}, []int64{work.ID, loose.ID}},
+		{"title contains literal", storage.NoteFilter{TitleContains: "50%_"}, []int64{home.ID}},
+		{"title contains wildcard", storage.NoteFilter{TitleContains: "%"}, []int64{home.ID}},
+		{"content length", storage.NoteFilter{MinContentLength: ptr(4), MaxContentLength: ptr(5)}, []int64{work.ID}},
+	}
+
+	for _, c := range cases {
+		got := noteIDs(mustListFiltered(t, s, u.ID, c.filter))
+		slices.Sort(got)
+		want := slices.Sorted(slices.Values(c.want))
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, want)
+		}
+
+		total, err := s.CountNotes(t.Context(), u.ID, c.filter)
+		if err != nil || total != int64(len(want)) {
+			t.Errorf("%s: CountNotes got %d, %v", c.name, total, err)
+		}
+	}
+
+	future := time.Now().Add(time.Hour)
+	if got := mustListFiltered(t, s, u.ID, storage.NoteFilter{CreatedFrom: &future}); len(got) != 0 {
+		t.Errorf("created_from in future: got %v", noteIDs(got))
+	}
+	if got := mustListFiltered(t, s, u.ID, storage.NoteFilter{UpdatedTo: &future}); len(got) != 3 {
+		t.Errorf("updated_to in future: got %v", noteIDs(got))
+	}
+}
+
+func testTags(t *testing.T, s storage.Storage) {
+	u := mustUser(t, s, "alice")
+
+	a, _, err := s.SaveNotes(t.Context(), "a", "a", u.ID, []string{"work", "home"}, nil)
+	if err != nil {
+		t.Fatalf("SaveNotes: %v", err)
+	}
+	if _, _, err := s.SaveNotes(t.Context(), "b", "b", u.ID, []string{"work"}, nil); err != nil {
+		t.Fatalf("SaveNotes: %v", err)
+	}
+
+	tags, err := s.GetTags(t.Context(), u.ID)
+	if err != nil {
+		t.Fatalf("GetTags: %v", err)
+	}
+	if len(tags) != 2 || tags[0].Name != "home" || tags[0].NoteCount != 1 || tags[1].Name != "work" || tags[1].NoteCount != 2 {
+		t.Fatalf("GetTags: got %+v", tags)
+	}
+
+	// Заметки в корзине не учитываются
+	if err := s.DeleteNote(t.Context(), u.ID, a.ID, 0); err != nil {
+		t.Fatalf("DeleteNote: %v", err)
+	}
+	tags, err = s.GetTags(t.Context(), u.ID)
+	if err != nil || len(tags) != 1 || tags[0].Name != "work" || tags[0].NoteCount != 1 {
+		t.Fatalf("GetTags after delete: got %+v, %v", tags, err)
+	}
+}
+
+func testNotebooks(t *testing.T, s storage.Storage) {
+	u := mustUser(t, s, "alice")
+	other := mustUser(t, s, "bob")
+
+	root := mustNotebook(t, s, u.ID, "root", nil)
+	child := mustNotebook(t, s, u.ID, "child", &root.ID)
+
+	if _, err := s.CreateNotebook(t.Context(), other.ID, "x", &root.ID); !errors.Is(err, storageErr.ErrNotebookNotFound) {
+		t.Fatalf("CreateNotebook with foreign parent: got %v, want ErrNotebookNotFound", err)
+	}
+
+	notebooks, err := s.GetNotebooks(t.Context(), u.ID)
+	if err != nil || len(notebooks) != 2 || notebooks[0].Name != "child" {
+		t.Fatalf("GetNotebooks: got %+v, %v", notebooks, err)
+	}
+
+	if _, err := s.PutNotebook(t.Context(), u.ID, root.ID, "root", &child.ID); !errors.Is(err, storageErr.ErrNotebookCycle) {
+		t.Fatalf("PutNotebook into own child: got %v, want ErrNotebookCycle", err)
+	}
+	if _, err := s.PutNotebook(t.Context(), u.ID, root.ID, "root", &root.ID); !errors.Is(err, storageErr.ErrNotebookCycle) {
+		t.Fatalf("PutNotebook into itself: got %v, want ErrNotebookCycle", err)
+	}
+
+	moved, err := s.PutNotebook(t.Context(), u.ID, child.ID, "renamed", nil)
+	if err != nil || moved.Name != "renamed" || moved.ParentID != nil {
+		t.Fatalf("PutNotebook: got %+v, %v", moved, err)
+	}
+	if _, err := s.PutNotebook(t.Context(), u.ID, child.ID, "renamed", &root.ID); err != nil {
+		t.Fatalf("PutNotebook back: %v", err)
+	}
+
+	n, _, err := s.SaveNotes(t.Context(), "t", "c", u.ID, nil, &child.ID)
+	if err != nil {
+		t.Fatalf("SaveNotes in notebook: %v", err)
+	}
+
+	// Удаление блокнота удаляет вложенные, а заметки остаются вне блокнотов
+	if err := s.DeleteNotebook(t.Context(), u.ID, root.ID); err != nil {
+		t.Fatalf("DeleteNotebook: %v", err)
+	}
+	if _, err := s.GetNotebook(t.Context(), u.ID, child.ID); !errors.Is(err, storageErr.ErrNotebookNotFound) {
+		t.Fatalf("GetNotebook of deleted child: got %v, want ErrNotebookNotFound", err)
+	}
+	got, err := s.GetOneNote(t.Context(), u.ID, n.ID)
+	if err != nil || got.NotebookID != nil {
+		t.Fatalf("note after notebook delete: got %+v, %v", got, err)
+	}
+	if err := s.DeleteNotebook(t.Context(), u.ID, root.ID); !errors.Is(err, storageErr.ErrNotebookNotFound) {
+		t.Fatalf("DeleteNotebook twice: got %v, want ErrNotebookNotFound", err)
+	}
+}
+
+func testTrash(t *testing.T, s storage.Storage) {
+	u := mustUser(t, s, "alice")
+	a := mustNote(t, s, u.ID, "a", "a")
+	b := mustNote(t, s, u.ID, "b", "b")
+
+	for _, n := range []*models.Note{a, b} {
+		if err := s.DeleteNote(t.Context(), u.ID, n.ID, 0); err != nil {
+			t.Fatalf("DeleteNote: %v", err)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	trash, err := s.GetTrash(t.Context(), u.ID)
+	if err != nil || !slices.Equal(noteIDs(trash), []int64{b.ID, a.ID}) || trash[0].DeletedAt == nil {
+		t.Fatalf("GetTrash: got %v, %v", noteIDs(trash), err)
+	}
+
+	restored, err := s.RestoreNote(t.Context(), u.ID, a.ID)
+	if err != nil || restored.ID != a.ID {
+		t.Fatalf("RestoreNote: got %+v, %v", restored, err)
+	}
+	if _, err := s.RestoreNote(t.Context(), u.ID, a.ID); !errors.Is(err, storageErr.ErrNoteNotFound) {
+		t.Fatalf("RestoreNote of active note: got %v, want ErrNoteNotFound", err)
+	}
+
+	purged, err := s.PurgeTrash(t.Context(), time.Now().Add(-time.Hour))
+	if err != nil || purged != 0 {
+		t.Fatalf("PurgeTrash before deletion: got %d, %v", purged, err)
+	}
+
+	emptied, err := s.EmptyTrash(t.Context(), u.ID)
+	if err != nil || emptied != 1 {
+		t.Fatalf("EmptyTrash: got %d, %v", emptied, err)
+	}
+	if _, err := s.RestoreNote(t.Context(), u.ID, b.ID); !errors.Is(err, storageErr.ErrNoteNotFound) {
+		t.Fatalf("RestoreNote after EmptyTrash: got %v, want ErrNoteNotFound", err)
+	}
+
+	if err := s.DeleteNote(t.Context(), u.ID, a.ID, 0); err != nil {
+		t.Fatalf("DeleteNote: %v", err)
+	}
+	purged, err = s.PurgeTrash(t.Context(), time.Now().Add(time.Hour))
+	if err != nil || purged != 1 {
+		t.Fatalf("PurgeTrash: got %d, %v", purged, err)
+	}
+}
+
+func testRevisions(t *testing.T, s storage.Storage) {
+	u := mustUser(t, s, "alice")
+	n := mustNote(t, s, u.ID, "v1", "c1")
+
+	if _, err := s.PutNote(t.Context(), u.ID, n.ID, "v2", "c2", nil, nil, 0); err != nil {
+		t.Fatalf("PutNote: %v", err)
+	}
+	// Без изменения title и content версия не сохраняется
+	if _, err := s.PutNote(t.Context(), u.ID, n.ID, "v2", "c2", []string{"x"}, nil, 0); err != nil {
+		t.Fatalf("PutNote: %v", err)
+	}
+	if _, err := s.PutNote(t.Context(), u.ID, n.ID, "v3", "c3", nil, nil, 0); err != nil {
+		t.Fatalf("PutNote: %v", err)
+	}
+
+	revisions, err := s.GetNoteRevisions(t.Context(), u.ID, n.ID)
+	if err != nil || len(revisions) != 2 || revisions[0].Revision != 2 || revisions[0].Title != "v2" || revisions[1].Title != "v1" {
+		t.Fatalf("GetNoteRevisions: got %+v, %v", revisions, err)
+	}
+
+	rev, err := s.GetNoteRevision(t.Context(), u.ID, n.ID, 1)
+	if err != nil || rev.Content != "c1" {
+		t.Fatalf("GetNoteRevision: got %+v, %v", rev, err)
+	}
+	if _, err := s.GetNoteRevision(t.Context(), u.ID, n.ID, 10); !errors.Is(err, storageErr.ErrRevisionNotFound) {
+		t.Fatalf("GetNoteRevision missing: got %v, want ErrRevisionNotFound", err)
+	}
+
+	restored, err := s.RestoreNoteRevision(t.Context(), u.ID, n.ID, 1)
+	if err != nil || restored.Title != "v1" {
+		t.Fatalf("RestoreNoteRevision: got %+v, %v", restored, err)
+	}
+	revisions, err = s.GetNoteRevisions(t.Context(), u.ID, n.ID)
+	if err != nil || len(revisions) != 3 || revisions[0].Title != "v3" {
+		t.Fatalf("GetNoteRevisions after restore: got %+v, %v", revisions, err)
+	}
+
+	pruned, err := s.PruneNoteRevisions(t.Context(), 1, time.Time{})
+	if err != nil || pruned != 2 {
+		t.Fatalf("PruneNoteRevisions: got %d, %v", pruned, err)
+	}
+
+	if _, err := s.GetNoteRevisions(t.Context(), u.ID, n.ID+100); !errors.Is(err, storageErr.ErrNoteNotFound) {
+		t.Fatalf("GetNoteRevisions missing note: got %v, want ErrNoteNotFound", err)
+	}
+}
+
+func testSearch(t *testing.T, s storage.Storage) {
+	u := mustUser(t, s, "alice")
+	release := mustNote(t, s, u.ID, "Release plan", "discuss the release of version two")
+	meeting := mustNote(t, s, u.ID, "Meeting notes", "weekly sync about hiring")
+
+	if _, err := s.SearchNotes(t.Context(), u.ID, "  ", 10, 0); !errors.Is(err, storageErr.ErrEmptySearchQuery) {
+		t.Fatalf("SearchNotes empty: got %v, want ErrEmptySearchQuery", err)
+	}
+
+	cases := []struct {
+		query string
+		want  []int64
+	}{
+		{"release", []int64{release.ID}},
+		{"meet*", []int64{meeting.ID}},
+		{`"weekly sync"`, []int64{meeting.ID}},
+		{`"sync weekly"`, nil},
+		{"release or hiring", []int64{release.ID, meeting.ID}},
+		{"notes -hiring", nil},
+	}
+
+	for _, c := range cases {
+		results, err := s.SearchNotes(t.Context(), u.ID, c.query, 10, 0)
+		if err != nil {
+			t.Fatalf("SearchNotes %q: %v", c.query, err)
+		}
+
+		var got []int64
+		for _, r := range results {
+			got = append(got, r.ID)
+		}
+		slices.Sort(got)
+		if want := slices.Sorted(slices.Values(c.want)); !slices.Equal(got, want) {
+			t.Errorf("SearchNotes %q: got %v, want %v", c.query, got, want)
+		}
+	}
+}
+
+func testShares(t *testing.T, s storage.Storage) {
+	owner := mustUser(t, s, "alice")
+	reader := mustUser(t, s, "bob")
+	n := mustNote(t, s, owner.ID, "t", "c")
+
+	if _, err := s.ShareNote(t.Context(), reader.ID, n.ID, owner.ID, models.PermissionRead); !errors.Is(err, storageErr.ErrNoteNotFound) {
+		t.Fatalf("ShareNote by non-owner: got %v, want ErrNoteNotFound", err)
+	}
+
+	if _, err := s.ShareNote(t.Context(), owner.ID, n.ID, reader.ID, models.PermissionRead); err != nil {
+		t.Fatalf("ShareNote: %v", err)
+	}
+	share, err := s.ShareNote(t.Context(), owner.ID, n.ID, reader.ID, models.PermissionWrite)
+	if err != nil || share.Permission != models.PermissionWrite {
+		t.Fatalf("ShareNote update: got %+v, %v", share, err)
+	}
+
+	permission, err := s.GetSharePermission(t.Context(), n.ID, reader.ID)
+	if err != nil || permission != models.PermissionWrite {
+		t.Fatalf("GetSharePermission: got %q, %v", permission, err)
+	}
+
+	shares, err := s.GetNoteShares(t.Context(), owner.ID, n.ID)
+	if err != nil || len(shares) != 1 || shares[0].Username != "bob" {
+		t.Fatalf("GetNoteShares: got %+v, %v", shares, err)
+	}
+
+	shared, err := s.GetSharedNotes(t.Context(), reader.ID)
+	if err != nil || len(shared) != 1 || shared[0].OwnerName != "alice" {
+		t.Fatalf("GetSharedNotes: got %+v, %v", shared, err)
+	}
+
+	if err := s.UnshareNote(t.Context(), owner.ID, n.ID, reader.ID); err != nil {
+		t.Fatalf("UnshareNote: %v", err)
+	}
+	if err := s.UnshareNote(t.Context(), owner.ID, n.ID, reader.ID); !errors.Is(err, storageErr.ErrShareNotFound) {
+		t.Fatalf("UnshareNote twice: got %v, want ErrShareNotFound", err)
+	}
+	if _, err := s.GetSharePermission(t.Context(), n.ID, reader.ID); !errors.Is(err, storageErr.ErrShareNotFound) {
+		t.Fatalf("GetSharePermission after unshare: got %v, want ErrShareNotFound", err)
+	}
+}
+
+func testLinks(t *testing.T, s storage.Storage) {
+	u := mustUser(t, s, "alice")
+	n := mustNote(t, s, u.ID, "t", "c")
+
+	link, err := s.CreateNoteLink(t.Context(), u.ID, n.ID, "hash-1", "", nil)
+	if err != nil || link.ID == 0 || link.NoteID != n.ID {
+		t.Fatalf("CreateNoteLink: got %+v, %v", link, err)
+	}
+
+	byHash, err := s.GetNoteLink(t.Context(), "hash-1")
+	if err != nil || byHash.ID != link.ID {
+		t.Fatalf("GetNoteLink: got %+v, %v", byHash, err)
+	}
+	if _, err := s.GetNoteLink(t.Context(), "missing"); !errors.Is(err, storageErr.ErrLinkNotFound) {
+		t.Fatalf("GetNoteLink missing: got %v, want ErrLinkNotFound", err)
+	}
+
+	for want := int64(1); want <= 2; want++ {
+		note, views, err := s.ViewNoteLink(t.Context(), link.ID)
+		if err != nil || views != want || note.ID != n.ID {
+			t.Fatalf("ViewNoteLink: got %+v, %d, %v", note, views, err)
+		}
+	}
+
+	expired := time.Now().Add(-time.Minute)
+	old, err := s.CreateNoteLink(t.Context(), u.ID, n.ID, "hash-2", "", &expired)
+	if err != nil {
+		t.Fatalf("CreateNoteLink expired: %v", err)
+	}
+	if _, _, err := s.ViewNoteLink(t.Context(), old.ID); !errors.Is(err, storageErr.ErrLinkNotFound) {
+		t.Fatalf("ViewNoteLink expired: got %v, want ErrLinkNotFound", err)
+	}
+
+	links, err := s.GetNoteLinks(t.Context(), u.ID, n.ID)
+	if err != nil || len(links) != 2 || links[0].ID != link.ID {
+		t.Fatalf("GetNoteLinks: got %+v, %v", links, err)
+	}
+
+	if err := s.RevokeNoteLink(t.Context(), u.ID, n.ID, link.ID); err != nil {
+		t.Fatalf("RevokeNoteLink: %v", err)
+	}
+	if _, _, err := s.ViewNoteLink(t.Context(), link.ID); !errors.Is(err, storageErr.ErrLinkNotFound) {
+		t.Fatalf("ViewNoteLink revoked: got %v, want ErrLinkNotFound", err)
+	}
+	if err := s.RevokeNoteLink(t.Context(), u.ID, n.ID, link.ID+100); !errors.Is(err, storageErr.ErrLinkNotFound) {
+		t.Fatalf("RevokeNoteLink missing: got %v, want ErrLinkNotFound", err)
+	}
+}
+
+func testRefreshTokens(t *testing.T, s storage.Storage) {
+	u := mustUser(t, s, "alice")
+	expiresAt := time.Now().Add(time.Hour)
+
+	if err := s.SaveRefreshToken(t.Context(), u.ID, "r1", "family", expiresAt); err != nil {
+		t.Fatalf("SaveRefreshToken: %v", err)
+	}
+
+	rotated, err := s.RotateRefreshToken(t.Context(), "r1", "r2", expiresAt)
+	if err != nil || rotated.UserID != u.ID || rotated.FamilyID != "family" {
+		t.Fatalf("RotateRefreshToken: got %+v, %v", rotated, err)
+	}
+
+	// Повторное использование r1 отзывает всё семейство, в том числе r2
+	if _, err := s.RotateRefreshToken(t.Context(), "r1", "r3", expiresAt); !errors.Is(err, storageErr.ErrRefreshTokenReused) {
+		t.Fatalf("RotateRefreshToken reuse: got %v, want ErrRefreshTokenReused", err)
+	}
+	if _, err := s.RotateRefreshToken(t.Context(), "r2", "r4", expiresAt); !errors.Is(err, storageErr.ErrRefreshTokenReused) {
+		t.Fatalf("RotateRefreshToken of revoked family: got %v, want ErrRefreshTokenReused", err)
+	}
+
+	if _, err := s.RotateRefreshToken(t.Context(), "missing", "r5", expiresAt); !errors.Is(err, storageErr.ErrRefreshTokenNotFound) {
+		t.Fatalf("RotateRefreshToken missing: got %v, want ErrRefreshTokenNotFound", err)
+	}
+
+	if err := s.SaveRefreshToken(t.Context(), u.ID, "old", "family-2", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("SaveRefreshToken: %v", err)
+	}
+	if _, err := s.RotateRefreshToken(t.Context(), "old", "r6", expiresAt); !errors.Is(err, storageErr.ErrRefreshTokenExpired) {
+		t.Fatalf("RotateRefreshToken expired: got %v, want ErrRefreshTokenExpired", err)
+	}
+
+	if err := s.RevokeRefreshTokenFamily(t.Context(), u.ID, "missing"); !errors.Is(err, storageErr.ErrRefreshTokenNotFound) {
+		t.Fatalf("RevokeRefreshTokenFamily missing: got %v, want ErrRefreshTokenNotFound", err)
+	}
+	if err := s.RevokeUserRefreshTokens(t.Context(), u.ID); err != nil {
+		t.Fatalf("RevokeUserRefreshTokens: %v", err)
+	}
+}
+
+func testRevokedTokens(t *testing.T, s storage.Storage) {
+	u := mustUser(t, s, "alice")
+
+	if err := s.RevokeToken(t.Context(), "jti", u.ID, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if err := s.RevokeToken(t.Context(), "jti", u.ID, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("RevokeToken twice: %v", err)
+	}
+
+	revoked, err := s.IsTokenRevoked(t.Context(), "jti")
+	if err != nil || !revoked {
+		t.Fatalf("IsTokenRevoked: got %v, %v", revoked, err)
+	}
+
+	generation, err := s.IncrementTokenGeneration(t.Context(), u.ID)
+	if err != nil || generation != 1 {
+		t.Fatalf("IncrementTokenGeneration: got %d, %v", generation, err)
+	}
+	generation, err = s.GetTokenGeneration(t.Context(), u.ID)
+	if err != nil || generation != 1 {
+		t.Fatalf("GetTokenGeneration: got %d, %v", generation, err)
+	}
+	if _, err := s.GetTokenGeneration(t.Context(), u.ID+100); !errors.Is(err, storageErr.ErrUserNotFound) {
+		t.Fatalf("GetTokenGeneration missing: got %v, want ErrUserNotFound", err)
+	}
+
+	purged, err := s.PurgeExpiredTokens(t.Context(), time.Now())
+	if err != nil || purged != 1 {
+		t.Fatalf("PurgeExpiredTokens: got %d, %v", purged, err)
+	}
+	if revoked, _ := s.IsTokenRevoked(t.Context(), "jti"); revoked {
+		t.Fatal("IsTokenRevoked after purge: got true")
+	}
+}
+
+func mustUser(t *testing.T, s storage.Storage, name string) *models.User {
+	t.Helper()
+
+	u, err := s.RegisterUser(t.Context(), name, "hash")
+	if err != nil {
+		t.Fatalf("RegisterUser: %v", err)
+	}
+	return u
+}
+
+func mustNote(t *testing.T, s storage.Storage, idUser int64, title, content string) *models.Note {
+	t.Helper()
+
+	n, _, err := s.SaveNotes(t.Context(), title, content, idUser, nil, nil)
+	if err != nil {
+		t.Fatalf("SaveNotes: %v", err)
+	}
+	return n
+}
+
+func mustNotebook(t *testing.T, s storage.Storage, idUser int64, name string, parentID *int64) *models.Notebook {
+	t.Helper()
+
+	nb, err := s.CreateNotebook(t.Context(), idUser, name, parentID)
+	if err != nil {
+		t.Fatalf("CreateNotebook: %v", err)
+	}
+	return nb
+}
+
+func mustList(t *testing.T, s storage.Storage, idUser int64, page storage.NotePage) []*models.Note {
+	t.Helper()
+
+	notes, err := s.GetAllNotes(t.Context(), idUser, page, storage.NoteFilter{})
+	if err != nil {
+		t.Fatalf("GetAllNotes: %v", err)
+	}
+	return notes
+}
+
+func mustListFiltered(t *testing.T, s storage.Storage, idUser int64, filter storage.NoteFilter) []*models.Note {
+	t.Helper()
+
+	notes, err := s.GetAllNotes(t.Context(), idUser, storage.NotePage{Limit: 100, Sort: storage.NoteSortCreatedAt}, filter)
+	if err != nil {
+		t.Fatalf("GetAllNotes: %v", err)
+	}
+	return notes
+}
+
+func keyset(n *models.Note, sort storage.NoteSort) *storage.NoteKeyset {
+	key := storage.KeysetOf(n, sort)
+	return &key
+}
+
+func noteIDs(notes []*models.Note) []int64 {
+	ids := make([]int64, 0, len(notes))
+	for _, n := range notes {
+		ids = append(ids, n.ID)
+	}
+	return ids
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HTTP открывает серверный спан на каждый запрос, продолжая трассировку из заголовка
+// traceparent. Имя спана — метод и шаблон маршрута chi (GET /users/{id}/notes/{note_id}),
+// он известен только после маршрутизации, поэтому имя задаётся после обработки запроса.
+// Путь и строка запроса в спан не записываются: в них бывают токены публичных ссылок
+// и поисковые запросы
+func HTTP(next http.Handler) http.Handler {
+	tracer := otel.Tracer(instrumentation)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := tracer.Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.UserAgentOriginal(r.UserAgent()),
+			),
+			trace.WithAttributes(clientAttributes(r.RemoteAddr)...),
+		)
+		defer span.End()
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			span.SetName(r.Method + " " + rctx.RoutePattern())
+			span.SetAttributes(semconv.HTTPRoute(rctx.RoutePattern()))
+		}
+
+		status := ww.Status()
+		if status == 0 {
+			// Обработчик ничего не записал, net/http ответит 200
+			status = http.StatusOK
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// clientAttributes разбирает RemoteAddr на client.address и client.port. После
+// middleware.RealIP в RemoteAddr бывает адрес без порта — тогда он записывается целиком
+func clientAttributes(remoteAddr string) []attribute.KeyValue {
+	host, port, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return []attribute.KeyValue{semconv.ClientAddress(remoteAddr)}
+	}
+
+	attrs := []attribute.KeyValue{semconv.ClientAddress(host)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.ClientPort(p))
+	}
+	return attrs
+}
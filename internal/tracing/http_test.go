@@ -0,0 +1,30 @@
+package tracing
+
+import (
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+func TestClientAttributes(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		want       []attribute.KeyValue
+	}{
+		{name: "ipv4", remoteAddr: "192.0.2.1:54321", want: []attribute.KeyValue{semconv.ClientAddress("192.0.2.1"), semconv.ClientPort(54321)}},
+		{name: "ipv6", remoteAddr: "[2001:db8::1]:443", want: []attribute.KeyValue{semconv.ClientAddress("2001:db8::1"), semconv.ClientPort(443)}},
+		{name: "no port", remoteAddr: "192.0.2.1", want: []attribute.KeyValue{semconv.ClientAddress("192.0.2.1")}},
+		{name: "named port", remoteAddr: "192.0.2.1:http", want: []attribute.KeyValue{semconv.ClientAddress("192.0.2.1")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clientAttributes(tt.remoteAddr); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("clientAttributes(%q) = %v, want %v", tt.remoteAddr, got, tt.want)
+			}
+		})
+	}
+}
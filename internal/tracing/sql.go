@@ -0,0 +1,270 @@
+package tracing
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Connector оборачивает коннектор database/sql: каждый запрос к базе внутри трассируемого
+// вызова становится дочерним спаном с текстом SQL без литералов. system — значение
+// db.system.name, например postgresql
+func Connector(connector driver.Connector, system string) driver.Connector {
+	return &tracedConnector{
+		Connector: connector,
+		tracer:    otel.Tracer(instrumentation),
+		system:    semconv.DBSystemNameKey.String(system),
+	}
+}
+
+type tracedConnector struct {
+	driver.Connector
+	tracer trace.Tracer
+	system attribute.KeyValue
+}
+
+func (c *tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tracedConn{Conn: conn, connector: c}, nil
+}
+
+// tracedConn пропускает к соединению драйвера все необязательные интерфейсы database/sql,
+// чтобы обёртка не меняла поведение пула и преобразование параметров
+type tracedConn struct {
+	driver.Conn
+	connector *tracedConnector
+}
+
+// start открывает спан запроса, только если вызов уже трассируется: запросы миграций
+// при старте и прочие вызовы вне запроса и вызова хранилища не порождают отдельных трассировок
+func (c *tracedConn) start(ctx context.Context, query string) (context.Context, trace.Span) {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return ctx, nil
+	}
+
+	statement := sanitizeSQL(query)
+	operation := statement
+	if i := strings.IndexByte(statement, ' '); i > 0 {
+		operation = statement[:i]
+	}
+	operation = strings.ToUpper(operation)
+
+	return c.connector.tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			c.connector.system,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(statement),
+		),
+	)
+}
+
+func end(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil && err != driver.ErrSkip {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := c.start(ctx, query)
+	rows, err := queryer.QueryContext(ctx, query, args)
+	end(span, err)
+
+	return rows, err
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := c.start(ctx, query)
+	result, err := execer.ExecContext(ctx, query, args)
+	end(span, err)
+
+	return result, err
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+
+	return c.Conn.Prepare(query)
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+
+	return c.Conn.Begin()
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+
+	return nil
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c *tracedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+
+	return true
+}
+
+func (c *tracedConn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+
+	return driver.ErrSkip
+}
+
+// sanitizeSQL заменяет строковые (в том числе в долларовых кавычках) и числовые литералы
+// на ? и схлопывает пробелы.
+// Параметры ($1, $2) остаются как есть, их значения в спан не попадают
+func sanitizeSQL(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+
+	space := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+			continue
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			// Комментарий до конца строки
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+			space = true
+			continue
+		}
+
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+
+		switch {
+		case c == '\'':
+			// Строка, '' внутри — экранированная кавычка
+			for i++; i < len(query); i++ {
+				if query[i] == '\'' {
+					if i+1 < len(query) && query[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			b.WriteByte('?')
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			// Параметр $N
+			b.WriteByte(c)
+			for i+1 < len(query) && isDigit(query[i+1]) {
+				i++
+				b.WriteByte(query[i])
+			}
+		case c == '$' && !isIdent(prev(query, i)):
+			// Строка в долларовых кавычках: $$...$$ или $tag$...$tag$
+			tag := dollarTag(query[i:])
+			if tag == "" {
+				b.WriteByte(c)
+				break
+			}
+			end := strings.Index(query[i+len(tag):], tag)
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += len(tag) + end + len(tag) - 1
+			}
+			b.WriteByte('?')
+		case isDigit(c) && !isIdent(prev(query, i)):
+			for i+1 < len(query) && (isDigit(query[i+1]) || query[i+1] == '.') {
+				i++
+			}
+			b.WriteByte('?')
+		case c == '"':
+			// Идентификатор в кавычках копируется целиком
+			j := strings.IndexByte(query[i+1:], '"')
+			if j < 0 {
+				b.WriteString(query[i:])
+				i = len(query)
+				break
+			}
+			b.WriteString(query[i : i+j+2])
+			i += j + 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// dollarTag возвращает открывающий разделитель строки в долларовых кавычках в начале s
+// ($$ или $tag$) или пустую строку
+func dollarTag(s string) string {
+	for j := 1; j < len(s); j++ {
+		switch {
+		case s[j] == '$':
+			return s[:j+1]
+		case !isIdent(s[j]) || s[j] == '$' || (j == 1 && isDigit(s[j])):
+			return ""
+		}
+	}
+	return ""
+}
+
+func prev(s string, i int) byte {
+	if i == 0 {
+		return ' '
+	}
+	return s[i-1]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdent(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || (c|0x20 >= 'a' && c|0x20 <= 'z')
+}
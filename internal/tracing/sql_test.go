@@ -0,0 +1,43 @@
+package tracing
+
+import "testing"
+
+func TestSanitizeSQL(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "no literals", query: "SELECT id FROM notes", want: "SELECT id FROM notes"},
+		{name: "whitespace collapsed", query: "\n\tSELECT id\n\t  FROM notes  \n", want: "SELECT id FROM notes"},
+		{name: "string literal", query: "SELECT id FROM users WHERE user_name = 'alice'", want: "SELECT id FROM users WHERE user_name = ?"},
+		{name: "escaped quote", query: "SELECT 'it''s' AS s, 'x'", want: "SELECT ? AS s, ?"},
+		{name: "empty string", query: "WHERE title = ''", want: "WHERE title = ?"},
+		{name: "unterminated string", query: "WHERE title = 'secret", want: "WHERE title = ?"},
+		{name: "integer", query: "LIMIT 10 OFFSET 20", want: "LIMIT ? OFFSET ?"},
+		{name: "decimal", query: "WHERE rank > 0.25", want: "WHERE rank > ?"},
+		{name: "negative number", query: "WHERE id = -5", want: "WHERE id = -?"},
+		{name: "dollar quoted", query: "SELECT $$it's secret$$, 1", want: "SELECT ?, ?"},
+		{name: "dollar quoted with tag", query: "DO $body$ RAISE 'x $$ y' $body$", want: "DO ?"},
+		{name: "unterminated dollar quote", query: "SELECT $a$ secret", want: "SELECT ?"},
+		{name: "dollar in identifier", query: "SELECT col$x$ FROM t", want: "SELECT col$x$ FROM t"},
+		{name: "lone dollar", query: "SELECT a $ b", want: "SELECT a $ b"},
+		{name: "parameters kept", query: "WHERE user_id = $1 AND id = $12", want: "WHERE user_id = $1 AND id = $12"},
+		{name: "digits in identifiers", query: "SELECT t1.col2 FROM notes_v2 t1", want: "SELECT t1.col2 FROM notes_v2 t1"},
+		{name: "quoted identifier", query: `SELECT "user 1", "it's" FROM t`, want: `SELECT "user 1", "it's" FROM t`},
+		{name: "unterminated identifier", query: `SELECT "abc`, want: `SELECT "abc`},
+		{name: "line comment", query: "SELECT 1 -- password 'x'\nFROM t", want: "SELECT ? FROM t"},
+		{name: "comment at end", query: "SELECT id FROM t -- 42", want: "SELECT id FROM t"},
+		{name: "array literal", query: "ARRAY['a','b',3]", want: "ARRAY[?,?,?]"},
+		{name: "function options", query: "ts_headline('simple', n.title, n.q, $6)", want: "ts_headline(?, n.title, n.q, $6)"},
+		{name: "empty", query: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeSQL(tt.query); got != tt.want {
+				t.Errorf("sanitizeSQL(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
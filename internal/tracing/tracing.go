@@ -0,0 +1,136 @@
+// Package tracing настраивает трассировку OpenTelemetry: спаны HTTP запросов,
+// вызовов хранилища и запросов к базе, распространение контекста по W3C traceparent
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// Экспортёры спанов
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+const instrumentation = "NotesService/internal/tracing"
+
+type Options struct {
+	// none, otlp, stdout или file
+	Exporter string
+	// Адрес коллектора OTLP/HTTP, например http://otel-collector:4318. Если не задан,
+	// используются OTEL_EXPORTER_OTLP_ENDPOINT и адрес по умолчанию localhost:4318
+	OTLPEndpoint string
+	// Файл, в который экспортёр file пишет спаны, по одному JSON на строку
+	File string
+	// Доля трассировок, начатых этим сервисом, которые записываются (0..1).
+	// Если вызывающий передал traceparent, решение берётся из него
+	SampleRatio float64
+	ServiceName string
+}
+
+// Tracing — провайдер спанов сервиса. При экспортёре none спаны не записываются,
+// но trace_id из входящего traceparent всё равно попадает в логи
+type Tracing struct {
+	provider *sdktrace.TracerProvider
+	out      io.Closer
+}
+
+// Setup создаёт провайдер по opts и делает его глобальным вместе с пропагатором W3C
+// (traceparent, baggage)
+func Setup(ctx context.Context, opts Options) (*Tracing, error) {
+	const op = "tracing.Setup"
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	t := &Tracing{}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch opts.Exporter {
+	case ExporterNone:
+		return t, nil
+	case ExporterOTLP:
+		var exporterOpts []otlptracehttp.Option
+		if opts.OTLPEndpoint != "" {
+			exporterOpts = append(exporterOpts, otlptracehttp.WithEndpointURL(opts.OTLPEndpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, exporterOpts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterFile:
+		exporter, err = t.fileExporter(opts.File)
+	default:
+		err = fmt.Errorf("unknown exporter %q", opts.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	t.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(t.provider)
+
+	return t, nil
+}
+
+func (t *Tracing) fileExporter(path string) (sdktrace.SpanExporter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	t.out = f
+
+	return stdouttrace.New(stdouttrace.WithWriter(f))
+}
+
+// Shutdown отправляет накопленные спаны и останавливает экспортёр
+func (t *Tracing) Shutdown(ctx context.Context) error {
+	const op = "tracing.Shutdown"
+
+	if t.provider == nil {
+		return nil
+	}
+
+	err := t.provider.Shutdown(ctx)
+	if t.out != nil {
+		err = errors.Join(err, t.out.Close())
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -17,6 +17,6 @@ create table IF NOT EXISTS notes(
 
 -- +goose Down
 -- +goose StatementBegin
-DROP TABLE IF EXISTS users;
 DROP TABLE IF EXISTS notes;
+DROP TABLE IF EXISTS users;
 -- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- До этой миграции имена пользователей не были уникальными. Если в базе есть повторы,
+-- миграция прерывается со списком имён: их нужно переименовать или объединить вручную
+-- (см. README, «Пароли существующих пользователей»)
+-- +goose StatementBegin
+DO $$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT string_agg(user_name, ', ' ORDER BY user_name) INTO duplicates
+    FROM (SELECT user_name FROM users GROUP BY user_name HAVING count(*) > 1) d;
+
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'duplicate user_name values: %', duplicates
+            USING HINT = 'rename or merge these users, then run migrate up again';
+    END IF;
+END $$;
+-- +goose StatementEnd
+
+-- Существующие пользователи получают пустой хеш и не могут войти, пока оператор
+-- не задаст им пароль командой app users set-password
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_hash TEXT NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX IF NOT EXISTS users_user_name_key ON users (user_name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS users_user_name_key;
+ALTER TABLE users DROP COLUMN IF EXISTS password_hash;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Конфигурация 'simple' не зависит от языка заметки: слова только приводятся к нижнему регистру
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', title), 'A') ||
+    setweight(to_tsvector('simple', content), 'B')) STORED;
+CREATE INDEX IF NOT EXISTS notes_search_vector_idx ON notes USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notes DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- last_revision — номер последней сохранённой версии заметки; номера не переиспользуются
+-- даже после удаления старых версий политикой хранения
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS last_revision BIGINT NOT NULL DEFAULT 0;
+CREATE TABLE IF NOT EXISTS note_revisions(
+    note_id BIGINT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    revision BIGINT NOT NULL,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (note_id, revision));
+CREATE INDEX IF NOT EXISTS note_revisions_created_at_idx ON note_revisions (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS note_revisions;
+ALTER TABLE notes DROP COLUMN IF EXISTS last_revision;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- deleted_at IS NOT NULL — заметка лежит в корзине
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS notes_deleted_at_idx ON notes (deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM notes WHERE deleted_at IS NOT NULL;
+ALTER TABLE notes DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
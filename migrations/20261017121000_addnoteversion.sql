@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- version увеличивается при каждом изменении заметки и отдаётся клиенту как ETag
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notes DROP COLUMN IF EXISTS version;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Индексы под keyset-пагинацию списка заметок: (поле сортировки, id) в пределах пользователя
+CREATE INDEX IF NOT EXISTS notes_user_created_at_idx ON notes (user_id, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS notes_user_updated_at_idx ON notes (user_id, updated_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS notes_user_title_idx ON notes (user_id, title, id) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS notes_user_title_idx;
+DROP INDEX IF EXISTS notes_user_updated_at_idx;
+DROP INDEX IF EXISTS notes_user_created_at_idx;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+// Package migrations содержит SQL миграции схемы в формате goose.
+// Файлы встраиваются в бинарник и применяются мигратором при старте или командой migrate
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// FS — миграции PostgreSQL
+//
+//go:embed *.sql
+var FS embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFS embed.FS
+
+// SQLite возвращает миграции хранилища SQLite из каталога sqlite/
+func SQLite() fs.FS {
+	sub, err := fs.Sub(sqliteFS, "sqlite")
+	if err != nil {
+		// sqlite/ встроен при сборке, поэтому ошибка здесь — ошибка в коде
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,147 @@
+// Package diff строит построчный diff двух текстов (алгоритм Майерса)
+package diff
+
+import "strings"
+
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// maxEdits ограничивает число правок, которое ищет алгоритм. Если тексты
+// различаются сильнее, старый текст целиком удаляется, а новый вставляется
+const maxEdits = 1000
+
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines возвращает построчный diff, превращающий текст a в текст b
+func Lines(a, b string) []Line {
+	x, y := splitLines(a), splitLines(b)
+
+	// Общие начало и конец не участвуют в поиске правок
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(x)+len(y))
+	for _, text := range x[:prefix] {
+		lines = append(lines, Line{Op: OpEqual, Text: text})
+	}
+	lines = append(lines, myers(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])...)
+	for _, text := range x[len(x)-suffix:] {
+		lines = append(lines, Line{Op: OpEqual, Text: text})
+	}
+
+	return lines
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// myers находит кратчайший сценарий правок, превращающий a в b
+func myers(a, b []string) []Line {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	limit := min(n+m, maxEdits)
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, offset)
+			}
+		}
+	}
+
+	return replaceAll(a, b)
+}
+
+// backtrack восстанавливает правки по сохранённым на каждом шаге состояниям
+func backtrack(a, b []string, trace [][]int, offset int) []Line {
+	var reversed []Line
+	x, y := len(a), len(b)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, Line{Op: OpEqual, Text: a[x-1]})
+			x--
+			y--
+		}
+
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, Line{Op: OpInsert, Text: b[y-1]})
+			} else {
+				reversed = append(reversed, Line{Op: OpDelete, Text: a[x-1]})
+			}
+		}
+
+		x, y = prevX, prevY
+	}
+
+	lines := make([]Line, len(reversed))
+	for i, line := range reversed {
+		lines[len(reversed)-1-i] = line
+	}
+
+	return lines
+}
+
+func replaceAll(a, b []string) []Line {
+	lines := make([]Line, 0, len(a)+len(b))
+	for _, text := range a {
+		lines = append(lines, Line{Op: OpDelete, Text: text})
+	}
+	for _, text := range b {
+		lines = append(lines, Line{Op: OpInsert, Text: text})
+	}
+	return lines
+}
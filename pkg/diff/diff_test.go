@@ -0,0 +1,112 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// numbered возвращает строки prefix0..prefix(n-1), по одной на строку
+func numbered(prefix string, n int) string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%s%d", prefix, i)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// apply восстанавливает старый и новый текст по diff
+func apply(lines []Line) (a, b string) {
+	var x, y []string
+	for _, line := range lines {
+		switch line.Op {
+		case OpEqual:
+			x = append(x, line.Text)
+			y = append(y, line.Text)
+		case OpDelete:
+			x = append(x, line.Text)
+		case OpInsert:
+			y = append(y, line.Text)
+		}
+	}
+	return strings.Join(x, "\n"), strings.Join(y, "\n")
+}
+
+func edits(lines []Line) int {
+	count := 0
+	for _, line := range lines {
+		if line.Op != OpEqual {
+			count++
+		}
+	}
+	return count
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      string
+		wantEdits int
+	}{
+		{name: "both empty", a: "", b: "", wantEdits: 0},
+		{name: "equal", a: "one\ntwo", b: "one\ntwo", wantEdits: 0},
+		{name: "from empty", a: "", b: "one\ntwo", wantEdits: 2},
+		{name: "to empty", a: "one\ntwo", b: "", wantEdits: 2},
+		{name: "insert in middle", a: "one\nthree", b: "one\ntwo\nthree", wantEdits: 1},
+		{name: "delete in middle", a: "one\ntwo\nthree", b: "one\nthree", wantEdits: 1},
+		{name: "replace line", a: "one\ntwo\nthree", b: "one\n2\nthree", wantEdits: 2},
+		{name: "trailing newline", a: "one", b: "one\n", wantEdits: 1},
+		{name: "reordered", a: "a\nb\nc\nd", b: "b\na\nd\nc", wantEdits: 4},
+		{name: "shortest script", a: "a\nb\nc\na\nb\nb\na", b: "c\nb\na\nb\na\nc", wantEdits: 5},
+		{name: "repeated lines", a: "x\nx\nx", b: "x\nx\nx\nx\nx", wantEdits: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := Lines(tt.a, tt.b)
+
+			a, b := apply(lines)
+			if a != tt.a || b != tt.b {
+				t.Fatalf("apply(Lines()) = %q, %q; want %q, %q", a, b, tt.a, tt.b)
+			}
+			if got := edits(lines); got != tt.wantEdits {
+				t.Errorf("Lines() has %d edits, want %d: %v", got, tt.wantEdits, lines)
+			}
+		})
+	}
+}
+
+// Тексты, различающиеся больше чем на maxEdits строк, заменяются целиком, но общие
+// начало и конец остаются без изменений
+func TestLinesMaxEdits(t *testing.T) {
+	a := "head\n" + numbered("old", maxEdits) + "\ntail"
+	b := "head\n" + numbered("new", maxEdits) + "\ntail"
+
+	lines := Lines(a, b)
+
+	gotA, gotB := apply(lines)
+	if gotA != a || gotB != b {
+		t.Fatal("apply(Lines()) does not reconstruct the texts")
+	}
+	if len(lines) != 2*maxEdits+2 {
+		t.Fatalf("len(Lines()) = %d, want %d", len(lines), 2*maxEdits+2)
+	}
+
+	want := Line{Op: OpEqual, Text: "head"}
+	if lines[0] != want {
+		t.Errorf("first line = %v, want %v", lines[0], want)
+	}
+	for i, line := range lines[1 : len(lines)-1] {
+		op := OpDelete
+		if i >= maxEdits {
+			op = OpInsert
+		}
+		if line.Op != op {
+			t.Fatalf("line %d = %v, want %s", i+1, line, op)
+		}
+	}
+	want = Line{Op: OpEqual, Text: "tail"}
+	if last := lines[len(lines)-1]; last != want {
+		t.Errorf("last line = %v, want %v", last, want)
+	}
+}
@@ -0,0 +1,41 @@
+package logSlog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithContext привязывает ctx к логгеру: записи, сделанные без контекста (log.Info, log.Error),
+// обрабатываются с ctx, и обработчик логгера видит, например, спан запроса
+func WithContext(log *slog.Logger, ctx context.Context) *slog.Logger {
+	return slog.New(contextHandler{handler: log.Handler(), ctx: ctx})
+}
+
+type contextHandler struct {
+	handler slog.Handler
+	ctx     context.Context
+}
+
+func (h contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(h.context(ctx), level)
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler.Handle(h.context(ctx), r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{handler: h.handler.WithAttrs(attrs), ctx: h.ctx}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{handler: h.handler.WithGroup(name), ctx: h.ctx}
+}
+
+// context возвращает привязанный контекст, если запись сделана без своего
+func (h contextHandler) context(ctx context.Context) context.Context {
+	if ctx == nil || ctx == context.Background() {
+		return h.ctx
+	}
+	return ctx
+}
@@ -1,13 +1,18 @@
 package loggerMiddleware
 
 import (
+	sl "NotesService/pkg/logger/logSlog"
 	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
 
+// New логирует каждый запрос после его обработки. Вместо пути записывается шаблон маршрута chi
+// (/public/notes/{token}): в самом пути и строке запроса бывают токены публичных ссылок
+// и поисковые запросы, которые не должны попадать в логи
 func New(log *slog.Logger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		log := log.With(
@@ -17,9 +22,8 @@ func New(log *slog.Logger) func(next http.Handler) http.Handler {
 		log.Info("logger middleware enabled")
 
 		fn := func(w http.ResponseWriter, r *http.Request) {
-			entry := log.With(
+			entry := sl.WithContext(log, r.Context()).With(
 				slog.String("method", r.Method),
-				slog.String("path", r.URL.Path),
 				slog.String("remote_addr", r.RemoteAddr),
 				slog.String("user_agent", r.UserAgent()),
 				slog.String("request_id", middleware.GetReqID(r.Context())),
@@ -29,6 +33,7 @@ func New(log *slog.Logger) func(next http.Handler) http.Handler {
 			t1 := time.Now()
 			defer func() {
 				entry.Info("request completed",
+					slog.String("route", route(r)),
 					slog.Int("status", ww.Status()),
 					slog.Int("bytes", ww.BytesWritten()),
 					slog.String("duration", time.Since(t1).String()),
@@ -41,3 +46,11 @@ func New(log *slog.Logger) func(next http.Handler) http.Handler {
 		return http.HandlerFunc(fn)
 	}
 }
+
+// route возвращает шаблон маршрута, выбранный chi, или unmatched, если маршрут не найден
+func route(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		return rctx.RoutePattern()
+	}
+	return "unmatched"
+}
@@ -0,0 +1,75 @@
+// Package redact скрывает из логов учётные данные, токены и пользовательские данные
+package redact
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// Mask — значение, которым заменяются скрытые поля
+const Mask = "[REDACTED]"
+
+// Handler заменяет на Mask значения атрибутов с заданными ключами, в том числе внутри групп
+// и значений LogValuer (моделей, конфигурации). Регистр ключа не учитывается
+type Handler struct {
+	handler slog.Handler
+	keys    map[string]bool
+}
+
+func New(handler slog.Handler, keys []string) *Handler {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[strings.ToLower(strings.TrimSpace(key))] = true
+	}
+
+	return &Handler{handler: handler, keys: set}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.attr(a))
+		return true
+	})
+
+	return h.handler.Handle(ctx, redacted)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.attr(a)
+	}
+
+	return &Handler{handler: h.handler.WithAttrs(redacted), keys: h.keys}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{handler: h.handler.WithGroup(name), keys: h.keys}
+}
+
+// attr скрывает значение атрибута или, для группы, значения её атрибутов. LogValuer
+// раскрывается здесь же, иначе обработчик под обёрткой записал бы его без проверки ключей
+func (h *Handler) attr(a slog.Attr) slog.Attr {
+	if h.keys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, Mask)
+	}
+
+	value := a.Value.Resolve()
+	if value.Kind() != slog.KindGroup {
+		return slog.Attr{Key: a.Key, Value: value}
+	}
+
+	group := value.Group()
+	redacted := make([]slog.Attr, len(group))
+	for i, ga := range group {
+		redacted[i] = h.attr(ga)
+	}
+
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+}
@@ -0,0 +1,117 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+type credentials struct {
+	User     string
+	Password string
+}
+
+func (c credentials) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("user", c.User), slog.String("password", c.Password))
+}
+
+// tokenValuer разрешается в строку, а не в группу
+type tokenValuer string
+
+func (t tokenValuer) LogValue() slog.Value { return slog.StringValue(string(t)) }
+
+func TestHandler(t *testing.T) {
+	keys := []string{"password", " Token ", "AUTHORIZATION"}
+
+	tests := []struct {
+		name string
+		log  func(log *slog.Logger)
+		want map[string]any
+	}{
+		{
+			name: "top level",
+			log:  func(log *slog.Logger) { log.Info("msg", "password", "p", "user", "alice") },
+			want: map[string]any{"password": Mask, "user": "alice"},
+		},
+		{
+			name: "case-insensitive keys",
+			log:  func(log *slog.Logger) { log.Info("msg", "Password", "p", "TOKEN", "t", "authorization", "Bearer x") },
+			want: map[string]any{"Password": Mask, "TOKEN": Mask, "authorization": Mask},
+		},
+		{
+			name: "nested groups",
+			log: func(log *slog.Logger) {
+				log.Info("msg", slog.Group("req", slog.Group("headers", slog.String("Authorization", "Bearer x"), slog.String("accept", "json"))))
+			},
+			want: map[string]any{"req": map[string]any{"headers": map[string]any{"Authorization": Mask, "accept": "json"}}},
+		},
+		{
+			name: "masked group",
+			log:  func(log *slog.Logger) { log.Info("msg", slog.Group("token", slog.String("value", "t"))) },
+			want: map[string]any{"token": Mask},
+		},
+		{
+			name: "LogValuer group",
+			log:  func(log *slog.Logger) { log.Info("msg", slog.Any("creds", credentials{User: "alice", Password: "p"})) },
+			want: map[string]any{"creds": map[string]any{"user": "alice", "password": Mask}},
+		},
+		{
+			name: "LogValuer under masked key",
+			log:  func(log *slog.Logger) { log.Info("msg", slog.Any("token", tokenValuer("t"))) },
+			want: map[string]any{"token": Mask},
+		},
+		{
+			name: "LogValuer scalar",
+			log:  func(log *slog.Logger) { log.Info("msg", slog.Any("id", tokenValuer("42"))) },
+			want: map[string]any{"id": "42"},
+		},
+		{
+			name: "Struct tags",
+			log: func(log *slog.Logger) {
+				log.Info("msg", slog.Any("req", Struct(struct {
+					Name   string `json:"name"`
+					Secret string `json:"secret" log:"secret"`
+					Hidden string `json:"-"`
+				}{Name: "n", Secret: "s", Hidden: "h"})))
+			},
+			want: map[string]any{"req": map[string]any{"name": "n", "secret": Mask}},
+		},
+		{
+			name: "With attrs",
+			log:  func(log *slog.Logger) { log.With("password", "p").Info("msg", "user", "alice") },
+			want: map[string]any{"password": Mask, "user": "alice"},
+		},
+		{
+			name: "With group",
+			log:  func(log *slog.Logger) { log.WithGroup("g").Info("msg", "password", "p", "user", "alice") },
+			want: map[string]any{"g": map[string]any{"password": Mask, "user": "alice"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			inner := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					// Время, уровень и сообщение не участвуют в сравнении
+					if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+						return slog.Attr{}
+					}
+					return a
+				},
+			})
+
+			tt.log(slog.New(New(inner, keys)))
+
+			var got map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("unmarshal %q: %v", buf.String(), err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
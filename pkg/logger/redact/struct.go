@@ -0,0 +1,94 @@
+package redact
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// Struct представляет структуру в логе группой её экспортируемых полей. Ключ поля — имя
+// из тега json, а без него — имя поля. Поля с тегом log:"secret" заменяются на Mask
+// в любом окружении, поля с json:"-" или log:"-" пропускаются. Встроенные структуры
+// раскрываются на уровень внешней, вложенные — группами. Подходит для реализации
+// slog.LogValuer:
+//
+//	func (r LoginRequest) LogValue() slog.Value { return redact.Struct(r) }
+func Struct(v any) slog.Value {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return slog.AnyValue(nil)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return slog.AnyValue(v)
+	}
+
+	return slog.GroupValue(fields(rv)...)
+}
+
+func fields(rv reflect.Value) []slog.Attr {
+	rt := rv.Type()
+
+	attrs := make([]slog.Attr, 0, rt.NumField())
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		logTag := field.Tag.Get("log")
+		if logTag == "-" {
+			continue
+		}
+
+		key, skip := fieldKey(field)
+		if skip {
+			continue
+		}
+
+		value := rv.Field(i)
+		switch {
+		case logTag == "secret":
+			attrs = append(attrs, slog.String(key, Mask))
+		case field.Anonymous && value.Kind() == reflect.Struct:
+			attrs = append(attrs, fields(value)...)
+		case nested(value):
+			attrs = append(attrs, slog.Attr{Key: key, Value: slog.GroupValue(fields(value)...)})
+		default:
+			attrs = append(attrs, slog.Any(key, value.Interface()))
+		}
+	}
+
+	return attrs
+}
+
+// fieldKey возвращает ключ поля из тега json или имя поля
+func fieldKey(field reflect.StructField) (key string, skip bool) {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return "", true
+	case "":
+		return field.Name, false
+	}
+
+	return name, false
+}
+
+// nested сообщает, что поле — вложенная структура, которую нужно раскрыть группой.
+// Типы со своим представлением (time.Time, LogValuer) записываются как есть
+func nested(value reflect.Value) bool {
+	if value.Kind() != reflect.Struct {
+		return false
+	}
+
+	switch value.Interface().(type) {
+	case slog.LogValuer, fmt.Stringer, error:
+		return false
+	}
+
+	return true
+}
@@ -1,8 +1,10 @@
 package setupLogger
 
 import (
+	"NotesService/pkg/logger/redact"
 	"log/slog"
 	"os"
+	"slices"
 )
 
 const (
@@ -11,25 +13,36 @@ const (
 	envProd  = "prod"
 )
 
-func SetupLogger(env string) *slog.Logger {
+// Ключи, значения которых скрываются в логах любого окружения: учётные данные и токены
+var secretKeys = []string{
+	"password", "password_hash", "secret", "token", "token_hash", "refresh_token",
+	"authorization", "cookie", "url",
+}
+
+// Ключи пользовательских данных: заголовки и текст заметок, теги и поисковые запросы.
+// Видны только при локальной разработке
+var contentKeys = []string{
+	"title", "content", "snippet", "text", "tags", "q", "query",
+}
+
+// SetupLogger создаёт логгер для окружения env. Значения атрибутов с ключами из secretKeys,
+// contentKeys (кроме local) и redactKeys заменяются на [REDACTED]
+func SetupLogger(env string, redactKeys []string) *slog.Logger {
 
-	var log *slog.Logger
+	var handler slog.Handler
+	keys := slices.Concat(secretKeys, contentKeys, redactKeys)
 
 	switch env {
 	case envLocal:
-		log = slog.New(
-			slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
-		)
+		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})
+		keys = slices.Concat(secretKeys, redactKeys)
 	case envDev:
-		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
-		)
+		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})
 	case envProd:
-		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}),
-		)
+		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})
 	}
 
-	return log
+	// Записи с контекстом трассируемого запроса получают trace_id и span_id
+	return slog.New(traceHandler{redact.New(handler, keys)})
 
 }